@@ -0,0 +1,117 @@
+package hermes
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	utils "github.com/realTristan/hermes/utils"
+)
+
+// Analyzer is a function that splits a full-text value into the words that are stored in the full-text index.
+// The words that are shorter than the full-text minimum word length are skipped by the index.
+type Analyzer func(value string) []string
+
+// The names of the built-in analyzers.
+const (
+	// StandardAnalyzer lowercases the value and splits it into alphanumeric words. This is the default analyzer.
+	StandardAnalyzer string = "standard"
+	// KeywordAnalyzer lowercases the value and indexes it as a single word.
+	KeywordAnalyzer string = "keyword"
+)
+
+// analyzers is the registry of the analyzers that can be used by the full-text fields.
+var analyzers = struct {
+	mutex *sync.RWMutex
+	funcs map[string]Analyzer
+}{
+	mutex: &sync.RWMutex{},
+	funcs: map[string]Analyzer{
+		StandardAnalyzer: standardAnalyzer,
+		KeywordAnalyzer:  keywordAnalyzer,
+	},
+}
+
+// RegisterAnalyzer is a function that registers an analyzer, so that it can be used by the full-text fields.
+// This function is thread-safe.
+//
+// Parameters:
+//   - name (string): The name of the analyzer.
+//   - a (Analyzer): The analyzer.
+//
+// Returns:
+//   - error: If the name is empty, the analyzer is nil, or an analyzer with the same name is already registered.
+func RegisterAnalyzer(name string, a Analyzer) error {
+	analyzers.mutex.Lock()
+	defer analyzers.mutex.Unlock()
+
+	// Verify the analyzer
+	if len(name) == 0 {
+		return errors.New("invalid analyzer name")
+	} else if a == nil {
+		return fmt.Errorf("analyzer %s is nil", name)
+	} else if _, ok := analyzers.funcs[name]; ok {
+		return fmt.Errorf("analyzer %s already registered", name)
+	}
+
+	// Register the analyzer
+	analyzers.funcs[name] = a
+	return nil
+}
+
+// analyzer is a function that returns a registered analyzer.
+// This function is thread-safe.
+//
+// Parameters:
+//   - name (string): The name of the analyzer.
+//
+// Returns:
+//   - Analyzer: The analyzer.
+//   - error: If the analyzer isn't registered.
+func analyzer(name string) (Analyzer, error) {
+	analyzers.mutex.RLock()
+	defer analyzers.mutex.RUnlock()
+	if a, ok := analyzers.funcs[name]; ok {
+		return a, nil
+	}
+	return nil, fmt.Errorf("unknown analyzer %s", name)
+}
+
+// standardAnalyzer is the default analyzer. It lowercases the value and splits it into alphanumeric words.
+//
+// Parameters:
+//   - value (string): The full-text value.
+//
+// Returns:
+//   - []string: The words of the value.
+func standardAnalyzer(value string) []string {
+	// Clean the string value
+	value = strings.TrimSpace(value)
+	value = utils.RemoveDoubleSpaces(value)
+	value = strings.ToLower(value)
+
+	// Split the words
+	var words []string = []string{}
+	for _, word := range strings.Split(value, " ") {
+		if len(word) == 0 {
+			continue
+		}
+		words = append(words, utils.SplitByAlphaNum(utils.TrimNonAlphaNum(word))...)
+	}
+	return words
+}
+
+// keywordAnalyzer is an analyzer that lowercases the value and returns it as a single word.
+//
+// Parameters:
+//   - value (string): The full-text value.
+//
+// Returns:
+//   - []string: The value as a single word, or no words if the value is empty.
+func keywordAnalyzer(value string) []string {
+	if value = strings.ToLower(strings.TrimSpace(value)); len(value) == 0 {
+		return []string{}
+	}
+	return []string{value}
+}
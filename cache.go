@@ -1,18 +1,26 @@
 package hermes
 
-import (
-	"sync"
-)
-
 // Cache is a struct that represents an in-memory cache of key-value pairs.
 // The cache can be used to store arbitrary data, and supports concurrent access through a mutex.
 // Additionally, the cache can be configured to support full-text search using a FullText index.
 // Fields:
 //   - data (map[string]map[string]any): A map that stores the data in the cache. The keys of the map are strings that represent the cache keys, and the values are sub-maps that store the actual data under string keys.
-//   - mutex (*sync.RWMutex): A RWMutex that guards access to the cache data.
+//   - mutex (*lock): A RWMutex that guards access to the cache data, and measures the wait for it.
 //   - ft (*FullText): A FullText index that can be used for full-text search. If nil, full-text search is disabled.
+//   - compression (*compressor): The compressor used to store the values compressed. If nil, the values are stored as-is.
+//   - fields (map[string]map[string]Field): The metadata of the full-text fields of each key.
+//   - schema (*Schema): The declared fields of the records. If nil, the records are not validated.
+//   - events (*eventLog): The log of the changes made to the cache, for cache.Watch().
+//   - queries (*queryIndex): The search queries that the records are matched against when they're indexed.
+//   - stats (*cacheStats): The counters of the cache, for cache.Stats().
 type Cache struct {
-	data  map[string]map[string]any
-	mutex *sync.RWMutex
-	ft    *FullText
+	data        map[string]map[string]any
+	mutex       *lock
+	ft          *FullText
+	compression *compressor
+	fields      map[string]map[string]Field
+	schema      *Schema
+	events      *eventLog
+	queries     *queryIndex
+	stats       *cacheStats
 }
@@ -31,6 +31,11 @@ func (c *Cache) clean() {
 		c.ft.clean()
 	}
 	c.data = map[string]map[string]any{}
+	c.fields = map[string]map[string]Field{}
+	for key := range c.queries.hits {
+		c.queries.unhit(key)
+	}
+	c.events.publish(EventClean, "", nil, nil, nil)
 }
 
 // FTClean is a method of the Cache struct that clears the full-text cache contents.
@@ -51,6 +56,10 @@ func (c *Cache) FTClean() error {
 
 	// Clean the ft cache
 	c.ft.clean()
+	for key := range c.queries.hits {
+		c.queries.unhit(key)
+	}
+	c.events.publish(EventFTClean, "", nil, nil, nil)
 
 	// Return no error
 	return nil
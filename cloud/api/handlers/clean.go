@@ -15,7 +15,7 @@ import (
 func Clean(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		c.Clean()
-		return ctx.Send(utils.Success("null"))
+		return utils.Success(ctx, nil)
 	}
 }
 
@@ -28,8 +28,8 @@ func Clean(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 func FTClean(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		if err := c.FTClean(); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		}
-		return ctx.Send(utils.Success("null"))
+		return utils.Success(ctx, nil)
 	}
 }
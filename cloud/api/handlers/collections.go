@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	hermes "github.com/realTristan/hermes"
+	utils "github.com/realTristan/hermes/cloud/api/utils"
+)
+
+// Collection is a function that returns a fiber context handler function that calls a cache handler
+// with the collection named in the "collection" query parameter. If no collection is provided, the default collection is used.
+// Parameters:
+//   - cs (*hermes.Collections): A pointer to a hermes.Collections struct.
+//   - handler (func(*hermes.Cache) func(ctx *fiber.Ctx) error): The cache handler.
+//
+// Returns:
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that calls the cache handler, or returns an error message if the collection does not exist.
+func Collection(cs *hermes.Collections, handler func(*hermes.Cache) func(ctx *fiber.Ctx) error) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		if c, err := cs.Get(ctx.Query("collection")); err != nil {
+			return utils.NotFound(ctx, err)
+		} else {
+			return handler(c)(ctx)
+		}
+	}
+}
+
+// ListCollections is a handler function that returns a fiber context handler function for listing the collections.
+// Parameters:
+//   - cs (*hermes.Collections): A pointer to a hermes.Collections struct.
+//
+// Returns:
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that returns a JSON-encoded string of the collection names or an error message if the encoding fails.
+func ListCollections(cs *hermes.Collections) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		return utils.Success(ctx, cs.List())
+	}
+}
+
+// CreateCollection is a handler function that returns a fiber context handler function for creating a collection.
+// Parameters:
+//   - cs (*hermes.Collections): A pointer to a hermes.Collections struct.
+//
+// Returns:
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that creates the collection named in the "name" query parameter and returns a 201 success message, or an error message if the name is invalid (400) or the collection already exists (409).
+func CreateCollection(cs *hermes.Collections) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		var name string
+		if name = strings.Clone(ctx.Query("name")); len(name) == 0 {
+			return utils.BadRequest(ctx, "invalid collection name")
+		} else if _, err := cs.Create(name); errors.Is(err, hermes.ErrCollectionExists) {
+			return utils.Error(ctx, fiber.StatusConflict, err)
+		} else if err != nil {
+			return utils.BadRequest(ctx, err)
+		}
+		return utils.Success(ctx.Status(fiber.StatusCreated), nil)
+	}
+}
+
+// DropCollection is a handler function that returns a fiber context handler function for dropping a collection.
+// Parameters:
+//   - cs (*hermes.Collections): A pointer to a hermes.Collections struct.
+//
+// Returns:
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that drops the collection named in the "name" query parameter and returns a success message, or an error message if the collection does not exist (404) or is the default collection.
+func DropCollection(cs *hermes.Collections) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		var name string = ctx.Query("name")
+		if name == hermes.DefaultCollection {
+			return utils.BadRequest(ctx, "the default collection can't be dropped")
+		} else if err := cs.Drop(name); err != nil {
+			return utils.NotFound(ctx, err)
+		}
+		return utils.Success(ctx, nil)
+	}
+}
@@ -17,11 +17,11 @@ func Delete(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 		// Get the key from the query
 		var key string
 		if key = ctx.Query("key"); len(key) == 0 {
-			return ctx.Send(utils.Error("key not provided"))
+			return utils.BadRequest(ctx, "key not provided")
 		}
 
 		// Delete the key from the cache
 		c.Delete(key)
-		return ctx.Send(utils.Success("null"))
+		return utils.Success(ctx, nil)
 	}
 }
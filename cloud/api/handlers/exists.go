@@ -17,10 +17,10 @@ func Exists(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 		// Get the key from the query
 		var key string
 		if key = ctx.Query("key"); len(key) == 0 {
-			return ctx.Send(utils.Error("key not provided"))
+			return utils.BadRequest(ctx, "key not provided")
 		}
 
 		// Return whether the key exists
-		return ctx.Send(utils.Success(c.Exists(key)))
+		return utils.Success(ctx, c.Exists(key))
 	}
 }
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"bufio"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	hermes "github.com/realTristan/hermes"
+	utils "github.com/realTristan/hermes/cloud/api/utils"
+)
+
+// Export is a handler function that returns a fiber context handler function for exporting the cache contents.
+// The response body is streamed, so the cache is never encoded into a single buffer.
+// Parameters:
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//
+// Returns:
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that streams the cache contents as json (default) or ndjson, depending on the "format" query parameter, or returns an error message if the format is invalid.
+func Export(c *hermes.Cache) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		var format hermes.ExportFormat = hermes.ExportFormat(strings.Clone(ctx.Query("format", string(hermes.ExportHash))))
+		switch format {
+		case hermes.ExportHash:
+			ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		case hermes.ExportNDJSON:
+			ctx.Set(fiber.HeaderContentType, "application/x-ndjson")
+		default:
+			return utils.BadRequest(ctx, "invalid format")
+		}
+
+		// Stream the cache contents. The status is already sent when the
+		// export fails, so the error can only be logged
+		ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			if err := c.ExportJSON(w, format); err != nil {
+				log.Println("export:", err)
+			}
+		})
+		return nil
+	}
+}
@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
-
 	"github.com/gofiber/fiber/v2"
 	hermes "github.com/realTristan/hermes"
 	utils "github.com/realTristan/hermes/cloud/api/utils"
@@ -16,7 +14,7 @@ import (
 //   - func(ctx *fiber.Ctx) error: A fiber context handler function that checks if the full-text search is initialized and returns a success message with a boolean value indicating whether it is initialized.
 func FTIsInitialized(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
-		return ctx.Send(utils.Success(c.FTIsInitialized()))
+		return utils.Success(ctx, c.FTIsInitialized())
 	}
 }
 
@@ -31,14 +29,14 @@ func FTSetMaxBytes(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 		// Get the value from the query
 		var value int
 		if err := utils.GetMaxBytesParam(ctx, &value); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Set the max bytes
 		if err := c.FTSetMaxBytes(value); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		}
-		return ctx.Send(utils.Success("null"))
+		return utils.Success(ctx, nil)
 	}
 }
 
@@ -53,14 +51,14 @@ func FTSetMaxSize(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 		// Get the value from the query
 		var value int
 		if err := utils.GetMaxSizeParam(ctx, &value); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Set the max length
 		if err := c.FTSetMaxSize(value); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		}
-		return ctx.Send(utils.Success("null"))
+		return utils.Success(ctx, nil)
 	}
 }
 
@@ -73,11 +71,9 @@ func FTSetMaxSize(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 func FTStorage(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		if data, err := c.FTStorage(); err != nil {
-			return ctx.Send(utils.Error(err))
-		} else if data, err := json.Marshal(data); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		} else {
-			return ctx.Send(data)
+			return ctx.JSON(data)
 		}
 	}
 }
@@ -91,9 +87,9 @@ func FTStorage(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 func FTStorageLength(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		if length, err := c.FTStorageLength(); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		} else {
-			return ctx.Send(utils.Success(length))
+			return utils.Success(ctx, length)
 		}
 	}
 }
@@ -107,9 +103,9 @@ func FTStorageLength(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 func FTStorageSize(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		if size, err := c.FTStorageSize(); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		} else {
-			return ctx.Send(utils.Success(size))
+			return utils.Success(ctx, size)
 		}
 	}
 }
@@ -125,15 +121,15 @@ func FTSetMinWordLength(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 		// Get the min word length from the query
 		var minWordLength int
 		if err := utils.GetMinWordLengthParam(ctx, &minWordLength); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Update the min word length
 		if err := c.FTSetMinWordLength(minWordLength); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		}
 
 		// Return null
-		return ctx.Send(utils.Success("null"))
+		return utils.Success(ctx, nil)
 	}
 }
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"encoding/json"
+	"fmt"
 
 	"github.com/gofiber/fiber/v2"
 	hermes "github.com/realTristan/hermes"
@@ -13,20 +13,20 @@ import (
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - func(ctx *fiber.Ctx) error: A fiber context handler function that gets a value from the cache using a key provided in the query string and returns a JSON-encoded string of the value or an error message if the key is not provided or if the retrieval or encoding fails.
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that gets a value from the cache using a key provided in the query string and returns the JSON-encoded value, or an error message if the key is not provided or does not exist (404).
 func Get(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		// Get the key from the query
 		var key string
 		if key = ctx.Query("key"); len(key) == 0 {
-			return ctx.Send(utils.Error("key not provided"))
+			return utils.BadRequest(ctx, "key not provided")
 		}
 
 		// Get the value from the cache
-		if data, err := json.Marshal(c.Get(key)); err != nil {
-			return ctx.Send(utils.Error(err))
+		if value := c.Get(key); value == nil {
+			return utils.NotFound(ctx, fmt.Sprintf("key %s does not exist", key))
 		} else {
-			return ctx.Send(data)
+			return ctx.JSON(value)
 		}
 	}
 }
@@ -36,9 +36,16 @@ func Get(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - func(ctx *fiber.Ctx) error: A fiber context handler function that gets all the data from the cache and returns a success message with a JSON-encoded string of the data or an error message if the retrieval or encoding fails.
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that gets all the data from the cache and returns the JSON-encoded records, mapped by key.
 func GetAll(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
-		return nil
+		var data map[string]map[string]any = make(map[string]map[string]any)
+		for _, key := range c.Keys() {
+			// Skip the keys deleted since they were listed
+			if value := c.Get(key); value != nil {
+				data[key] = value
+			}
+		}
+		return ctx.JSON(data)
 	}
 }
@@ -15,6 +15,6 @@ import (
 func FTSequenceIndices(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		c.FTSequenceIndices()
-		return ctx.Send(utils.Success("null"))
+		return utils.Success(ctx, nil)
 	}
 }
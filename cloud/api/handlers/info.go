@@ -17,9 +17,9 @@ import (
 func Info(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		if info, err := c.Info(); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		} else {
-			return ctx.Send(utils.Success(info))
+			return utils.Success(ctx, info)
 		}
 	}
 }
@@ -35,9 +35,9 @@ func Info(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 func InfoForTesting(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		if info, err := c.InfoForTesting(); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		} else {
-			return ctx.Send(utils.Success(info))
+			return utils.Success(ctx, info)
 		}
 	}
 }
@@ -22,24 +22,24 @@ func FTInit(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 
 		// Get the max length parameter
 		if err := utils.GetMaxSizeParam(ctx, &maxSize); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Get the max bytes parameter
 		if err := utils.GetMaxBytesParam(ctx, &maxBytes); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Get the min word length parameter
 		if err := utils.GetMinWordLengthParam(ctx, &minWordLength); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Initialize the full-text cache
 		if err := c.FTInit(maxSize, maxBytes, minWordLength); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		}
-		return ctx.Send(utils.Success("null"))
+		return utils.Success(ctx, nil)
 	}
 }
 
@@ -48,7 +48,7 @@ func FTInit(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - func(ctx *fiber.Ctx) error: A fiber context handler function that initializes the full-text search cache using a JSON object, max length, max bytes, and min word length parameters provided in the query string and returns a success message or an error message if the parameters are not provided or if the initialization fails.
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that initializes the full-text search cache using the JSON object in the request body, and the max length, max bytes, and min word length parameters provided in the query string and returns a success message or an error message if the parameters are not provided or if the initialization fails.
 func FTInitJson(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		var (
@@ -60,30 +60,30 @@ func FTInitJson(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 
 		// Get the max length from the query
 		if err := utils.GetMaxSizeParam(ctx, &maxSize); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Get the max bytes from the query
 		if err := utils.GetMaxBytesParam(ctx, &maxBytes); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Get the min word length from the query
 		if err := utils.GetMinWordLengthParam(ctx, &minWordLength); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
-		// Get the JSON from the query
+		// Get the JSON from the body
 		if err := utils.GetJSONParam(ctx, &json); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Initialize the full-text cache
 		if err := c.FTInitWithMap(json, maxSize, maxBytes, minWordLength); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		}
 
 		// Return success message
-		return ctx.Send(utils.Success("null"))
+		return utils.Success(ctx, nil)
 	}
 }
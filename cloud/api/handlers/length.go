@@ -14,6 +14,6 @@ import (
 //   - func(ctx *fiber.Ctx) error: A fiber context handler function that gets the length of the cache and returns a success message with the length or an error message if the retrieval fails.
 func Length(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
-		return ctx.Send(utils.Success(c.Length()))
+		return utils.Success(ctx, c.Length())
 	}
 }
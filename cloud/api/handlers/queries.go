@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	hermes "github.com/realTristan/hermes"
+	utils "github.com/realTristan/hermes/cloud/api/utils"
+)
+
+// RegisterQuery is a handler function that returns a fiber context handler function for registering a search query, so that documents can be percolated against it.
+// Parameters:
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//
+// Returns:
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that registers the query using the id, query, and strict parameters provided in the query string and returns a success message or an error message if the parameters are invalid or the id is already registered.
+func RegisterQuery(c *hermes.Cache) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		var (
+			id     string
+			query  string
+			strict bool
+		)
+
+		// Get the id and query from the url params. They're copied
+		// since fiber reuses the query buffer after the request
+		if id = strings.Clone(ctx.Query("id")); len(id) == 0 {
+			return utils.BadRequest(ctx, "invalid id")
+		} else if query = strings.Clone(ctx.Query("query")); len(query) == 0 {
+			return utils.BadRequest(ctx, "query not provided")
+		}
+
+		// Get the strict from the url params
+		if err := utils.GetStrictParam(ctx, &strict); err != nil {
+			return utils.BadRequest(ctx, err)
+		}
+
+		// Register the query
+		if err := c.RegisterQuery(id, hermes.SearchParams{
+			Query:  query,
+			Strict: strict,
+		}); err != nil {
+			return utils.Failed(ctx, err)
+		}
+		return utils.Success(ctx, nil)
+	}
+}
+
+// UnregisterQuery is a handler function that returns a fiber context handler function for removing a registered search query.
+// Parameters:
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//
+// Returns:
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that removes the query with the id parameter provided in the query string and returns a success message or an error message if no query is registered with the id.
+func UnregisterQuery(c *hermes.Cache) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		if err := c.UnregisterQuery(ctx.Query("id")); err != nil {
+			return utils.NotFound(ctx, err)
+		}
+		return utils.Success(ctx, nil)
+	}
+}
+
+// Percolate is a handler function that returns a fiber context handler function for finding the registered queries that a document matches.
+// Parameters:
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//
+// Returns:
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that matches the document in the request body against the registered queries and returns the matching query ids or an error message if the document is invalid or the full-text index is not initialized.
+func Percolate(c *hermes.Cache) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		var doc map[string]any
+
+		// Get the document from the body
+		if err := utils.GetValueParam(ctx, &doc); err != nil {
+			return utils.BadRequest(ctx, err)
+		}
+
+		// Percolate the document
+		if ids, err := c.Percolate(doc); err != nil {
+			return utils.Failed(ctx, err)
+		} else {
+			return utils.Success(ctx, ids)
+		}
+	}
+}
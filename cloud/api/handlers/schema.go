@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	hermes "github.com/realTristan/hermes"
+	utils "github.com/realTristan/hermes/cloud/api/utils"
+)
+
+// GetSchema is a handler function that returns a fiber context handler function for getting the schema of the cache records.
+// Parameters:
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//
+// Returns:
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that returns a JSON-encoded string of the schema (null if no schema is set) or an error message if the encoding fails.
+func GetSchema(c *hermes.Cache) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		return utils.Success(ctx, c.GetSchema())
+	}
+}
+
+// SetSchema is a handler function that returns a fiber context handler function for setting the schema of the cache records.
+// Parameters:
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//
+// Returns:
+//   - func(ctx *fiber.Ctx) error: A fiber context handler function that sets the schema in the request body (null removes the schema) and returns a success message or an error message if the schema is invalid or an existing record doesn't match it.
+func SetSchema(c *hermes.Cache) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		var schema *hermes.Schema
+
+		// Get the schema from the body
+		if err := utils.GetValueParam(ctx, &schema); err != nil {
+			return utils.BadRequest(ctx, err)
+		}
+
+		// Set the schema
+		if err := c.SetSchema(schema); err != nil {
+			return utils.Failed(ctx, err)
+		}
+		return utils.Success(ctx, nil)
+	}
+}
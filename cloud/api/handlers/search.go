@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
-
 	"github.com/gofiber/fiber/v2"
 	hermes "github.com/realTristan/hermes"
 	utils "github.com/realTristan/hermes/cloud/api/utils"
@@ -24,17 +22,17 @@ func Search(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 
 		// Get the query from the url params
 		if query = ctx.Query("query"); len(query) == 0 {
-			return ctx.Send(utils.Error("query not provided"))
+			return utils.BadRequest(ctx, "query not provided")
 		}
 
 		// Get the limit from the url params
 		if err := utils.GetLimitParam(ctx, &limit); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Get the strict from the url params
 		if err := utils.GetStrictParam(ctx, &strict); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Search for the query
@@ -43,11 +41,9 @@ func Search(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 			Limit:  limit,
 			Strict: strict,
 		}); err != nil {
-			return ctx.Send(utils.Error(err))
-		} else if data, err := json.Marshal(res); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		} else {
-			return ctx.Send(data)
+			return ctx.JSON(res)
 		}
 	}
 }
@@ -68,17 +64,17 @@ func SearchOneWord(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 
 		// Get the query from the url params
 		if query = ctx.Query("query"); len(query) == 0 {
-			return ctx.Send(utils.Error("invalid query"))
+			return utils.BadRequest(ctx, "invalid query")
 		}
 
 		// Get the limit from the url params
 		if err := utils.GetLimitParam(ctx, &limit); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Get the strict from the url params
 		if err := utils.GetStrictParam(ctx, &strict); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Search for the query
@@ -87,11 +83,9 @@ func SearchOneWord(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 			Limit:  limit,
 			Strict: strict,
 		}); err != nil {
-			return ctx.Send(utils.Error(err))
-		} else if data, err := json.Marshal(res); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		} else {
-			return ctx.Send(data)
+			return ctx.JSON(res)
 		}
 	}
 }
@@ -112,17 +106,17 @@ func SearchValues(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 
 		// Get the query from the url params
 		if query = ctx.Query("query"); len(query) == 0 {
-			return ctx.Send(utils.Error("invalid query"))
+			return utils.BadRequest(ctx, "invalid query")
 		}
 
 		// Get the limit from the url params
 		if err := utils.GetLimitParam(ctx, &limit); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Get the schema from the url params
 		if err := utils.GetSchemaParam(ctx, &schema); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Search for the query
@@ -131,11 +125,9 @@ func SearchValues(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 			Limit:  limit,
 			Schema: schema,
 		}); err != nil {
-			return ctx.Send(utils.Error(err))
-		} else if data, err := json.Marshal(res); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		} else {
-			return ctx.Send(data)
+			return ctx.JSON(res)
 		}
 	}
 }
@@ -156,17 +148,17 @@ func SearchWithKey(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 
 		// Get the query from the url params
 		if query = ctx.Query("query"); len(query) == 0 {
-			return ctx.Send(utils.Error("invalid query"))
+			return utils.BadRequest(ctx, "invalid query")
 		}
 
 		// Get the key from the url params
 		if key = ctx.Query("key"); len(key) == 0 {
-			return ctx.Send(utils.Error("invalid key"))
+			return utils.BadRequest(ctx, "invalid key")
 		}
 
 		// Get the limit from the url params
 		if err := utils.GetLimitParam(ctx, &limit); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.BadRequest(ctx, err)
 		}
 
 		// Search for the query
@@ -175,11 +167,9 @@ func SearchWithKey(c *hermes.Cache) func(ctx *fiber.Ctx) error {
 			Query: query,
 			Limit: limit,
 		}); err != nil {
-			return ctx.Send(utils.Error(err))
-		} else if data, err := json.Marshal(res); err != nil {
-			return ctx.Send(utils.Error(err))
+			return utils.Failed(ctx, err)
 		} else {
-			return ctx.Send(data)
+			return ctx.JSON(res)
 		}
 	}
 }
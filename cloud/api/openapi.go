@@ -0,0 +1,238 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Schema is a JSON schema, as used by the OpenAPI document.
+type Schema map[string]any
+
+// The schemas of the results and bodies of the routes.
+var (
+	stringSchema  Schema = Schema{"type": "string"}
+	integerSchema Schema = Schema{"type": "integer"}
+	booleanSchema Schema = Schema{"type": "boolean"}
+	nullSchema    Schema = Schema{"type": "null"}
+	objectSchema  Schema = Schema{"type": "object"}
+	recordSchema  Schema = ref("Record")
+)
+
+// components is the map of the schemas that the routes refer to.
+var components map[string]Schema = map[string]Schema{
+	"Record": {
+		"type":                 "object",
+		"description":          `A record of the cache. A full-text field is a {"$hermes.full_text": true, "$hermes.value": "..."} map when it's set.`,
+		"additionalProperties": true,
+	},
+	"Schema": {
+		"type":     "object",
+		"required": []string{"fields"},
+		"properties": map[string]any{
+			"fields": arrayOf(ref("SchemaField")),
+		},
+	},
+	"SchemaField": {
+		"type":     "object",
+		"required": []string{"name", "type"},
+		"properties": map[string]any{
+			"name":      stringSchema,
+			"type":      Schema{"type": "string", "enum": []string{"text", "keyword", "int", "float", "bool", "time"}},
+			"full_text": booleanSchema,
+			"analyzer":  stringSchema,
+			"required":  booleanSchema,
+		},
+	},
+	"Error": {
+		"type":     "object",
+		"required": []string{"success", "error"},
+		"properties": map[string]any{
+			"success": Schema{"const": false},
+			"error":   stringSchema,
+		},
+	},
+}
+
+// arrayOf is a function that returns the schema of an array.
+// Parameters:
+//   - items (Schema): The schema of the items.
+//
+// Returns:
+//   - Schema: The schema of the array.
+func arrayOf(items Schema) Schema {
+	return Schema{"type": "array", "items": items}
+}
+
+// mapOf is a function that returns the schema of an object with any keys.
+// Parameters:
+//   - values (Schema): The schema of the values.
+//
+// Returns:
+//   - Schema: The schema of the object.
+func mapOf(values Schema) Schema {
+	return Schema{"type": "object", "additionalProperties": values}
+}
+
+// nullable is a function that returns a schema that also accepts null.
+// Parameters:
+//   - s (Schema): The schema.
+//
+// Returns:
+//   - Schema: The nullable schema.
+func nullable(s Schema) Schema {
+	return Schema{"oneOf": []Schema{s, nullSchema}}
+}
+
+// ref is a function that returns a reference to a schema of the components.
+// Parameters:
+//   - name (string): The name of the schema.
+//
+// Returns:
+//   - Schema: The reference.
+func ref(name string) Schema {
+	return Schema{"$ref": "#/components/schemas/" + name}
+}
+
+// OpenAPI is a function that returns the OpenAPI 3.1 document of the routes.
+// The document is generated from the Routes table, so it can be served or written to a file.
+// Returns:
+//   - map[string]any: The document, which can be encoded as JSON.
+func OpenAPI() map[string]any {
+	var paths map[string]map[string]any = make(map[string]map[string]any)
+	for _, r := range Routes {
+		if paths[r.Path] == nil {
+			paths[r.Path] = make(map[string]any)
+		}
+		paths[r.Path][strings.ToLower(r.Method)] = r.operation()
+	}
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "hermes Cache API",
+			"version":     "1",
+			"description": "The REST API of the hermes cloud app. The errors are {\"success\": false, \"error\": \"...\"} responses.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": components,
+			"securitySchemes": map[string]any{
+				"bearer": map[string]any{"type": "http", "scheme": "bearer"},
+				"apiKey": map[string]any{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+				"token":  map[string]any{"type": "apiKey", "in": "query", "name": "token"},
+			},
+		},
+	}
+}
+
+// operation is a method of the Route struct that returns the OpenAPI operation of the route.
+// Returns:
+//   - map[string]any: The operation.
+func (r Route) operation() map[string]any {
+	var op map[string]any = map[string]any{
+		"operationId": r.operationID(),
+		"summary":     r.Summary,
+	}
+
+	// Add the query parameters
+	var params []Param = r.Params
+	if r.Cache != nil {
+		params = append([]Param{{"collection", "string", false, "The name of the collection. Defaults to the default collection."}}, params...)
+	}
+	if len(params) > 0 {
+		var list []map[string]any
+		for _, p := range params {
+			list = append(list, map[string]any{
+				"name":        p.Name,
+				"in":          "query",
+				"required":    p.Required,
+				"description": p.Description,
+				"schema":      Schema{"type": p.Type},
+			})
+		}
+		op["parameters"] = list
+	}
+
+	// Add the request body
+	if r.Body != nil {
+		op["requestBody"] = map[string]any{
+			"required": true,
+			"content":  map[string]any{fiber.MIMEApplicationJSON: map[string]any{"schema": r.Body}},
+		}
+	}
+
+	// Add the scope
+	var errors []int = append([]int{}, r.Errors...)
+	if len(r.Scope) > 0 {
+		op["description"] = "Requires the " + string(r.Scope) + " scope when authentication is enabled."
+		op["security"] = []map[string][]string{{"bearer": {}}, {"apiKey": {}}, {"token": {}}}
+		op["x-hermes-scope"] = string(r.Scope)
+		errors = append(errors, fiber.StatusUnauthorized, fiber.StatusForbidden)
+	}
+	if r.Cache != nil {
+		errors = append(errors, fiber.StatusNotFound)
+	}
+	op["responses"] = r.responses(errors)
+	return op
+}
+
+// responses is a method of the Route struct that returns the OpenAPI responses of the route.
+// Parameters:
+//   - errors ([]int): The error status codes of the route. Duplicates are ignored.
+//
+// Returns:
+//   - map[string]any: The responses, mapped by status code.
+func (r Route) responses(errors []int) map[string]any {
+	var result Schema = r.Result
+	if !r.Raw {
+		result = Schema{
+			"type":     "object",
+			"required": []string{"success", "data"},
+			"properties": map[string]any{
+				"success": Schema{"const": true},
+				"data":    r.Result,
+			},
+		}
+	}
+
+	// Add the successful response
+	var status int = r.Status
+	if status == 0 {
+		status = fiber.StatusOK
+	}
+	var produces []string = r.Produces
+	if len(produces) == 0 {
+		produces = []string{fiber.MIMEApplicationJSON}
+	}
+	var content map[string]any = make(map[string]any)
+	for _, t := range produces {
+		content[t] = map[string]any{"schema": result}
+	}
+	var responses map[string]any = map[string]any{
+		strconv.Itoa(status): map[string]any{"description": http.StatusText(status), "content": content},
+	}
+
+	// Add the error responses
+	for _, code := range errors {
+		responses[strconv.Itoa(code)] = map[string]any{
+			"description": http.StatusText(code),
+			"content":     map[string]any{fiber.MIMEApplicationJSON: map[string]any{"schema": ref("Error")}},
+		}
+	}
+	return responses
+}
+
+// operationID is a method of the Route struct that returns the id of the operation, from its method and path.
+// For example, POST /cache/set is postCacheSet.
+// Returns:
+//   - string: The operation id.
+func (r Route) operationID() string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(r.Method))
+	for _, part := range strings.FieldsFunc(r.Path, func(c rune) bool { return c == '/' || c == '.' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
@@ -4,48 +4,262 @@ import (
 	"github.com/gofiber/fiber/v2"
 	hermes "github.com/realTristan/hermes"
 	"github.com/realTristan/hermes/cloud/api/handlers"
+	"github.com/realTristan/hermes/cloud/auth"
 )
 
-// SetRoutes is a function that sets the routes for the hermes Cache API.
+// Route is a struct that represents a route of the hermes Cache API. The routes are set and
+// documented from the same table, so the OpenAPI document always matches the handlers.
+// Fields:
+//   - Method (string): The http method.
+//   - Path (string): The path of the route.
+//   - Scope (auth.Scope): The scope required to call the route. Empty for the public routes.
+//   - Summary (string): The description of the route.
+//   - Params ([]Param): The query parameters of the route.
+//   - Body (Schema): The schema of the JSON request body. Nil if the route takes no body.
+//   - Result (Schema): The schema of the result.
+//   - Raw (bool): Whether the result is the response body, instead of the "data" of a {"success": true, "data": ...} response.
+//   - Status (int): The status code of a successful response. Defaults to 200.
+//   - Produces ([]string): The content types of the response. Defaults to application/json.
+//   - Errors ([]int): The error status codes of the handler. 401 and 403 are added to the scoped routes, and 404 to the cache routes.
+//   - Handler (func(*hermes.Collections) fiber.Handler): The handler of a route that isn't bound to a collection.
+//   - Cache (func(*hermes.Cache) func(ctx *fiber.Ctx) error): The handler of a route that is called with the collection in the "collection" query parameter.
+type Route struct {
+	Method   string
+	Path     string
+	Scope    auth.Scope
+	Summary  string
+	Params   []Param
+	Body     Schema
+	Result   Schema
+	Raw      bool
+	Status   int
+	Produces []string
+	Errors   []int
+	Handler  func(cs *hermes.Collections) fiber.Handler
+	Cache    func(c *hermes.Cache) func(ctx *fiber.Ctx) error
+}
+
+// Param is a struct that represents a query parameter of a route.
+// Fields:
+//   - Name (string): The name of the parameter.
+//   - Type (string): The JSON schema type of the parameter: string, integer or boolean.
+//   - Required (bool): Whether the parameter is required.
+//   - Description (string): The description of the parameter.
+type Param struct {
+	Name        string
+	Type        string
+	Required    bool
+	Description string
+}
+
+// The query parameters that are shared by several routes.
+var (
+	keyParam           Param = Param{"key", "string", true, "The key of the record."}
+	queryParam         Param = Param{"query", "string", true, "The search query."}
+	limitParam         Param = Param{"limit", "integer", true, "The maximum number of results."}
+	strictParam        Param = Param{"strict", "boolean", true, "Whether the words of the records must match the query exactly, instead of containing it."}
+	maxSizeParam       Param = Param{"maxsize", "integer", true, "The maximum number of words in the full-text index. -1 for no limit."}
+	maxBytesParam      Param = Param{"maxbytes", "integer", true, "The maximum size of the full-text index, in bytes. -1 for no limit."}
+	minWordLengthParam Param = Param{"minwordlength", "integer", true, "The minimum length of the indexed words."}
+)
+
+// Routes is the table of the routes of the hermes Cache API.
+var Routes []Route = []Route{
+	// Dev Testing Handler
+	{Method: fiber.MethodGet, Path: "/dev/hermes", Summary: "Check that the API is running.",
+		Result: stringSchema, Raw: true, Produces: []string{fiber.MIMETextPlain},
+		Handler: func(*hermes.Collections) fiber.Handler {
+			return func(c *fiber.Ctx) error {
+				return c.SendString("hermes Cache API Successfully Running!")
+			}
+		}},
+
+	// Collection Handlers
+	{Method: fiber.MethodGet, Path: "/collections", Scope: auth.ScopeRead, Summary: "List the names of the collections.",
+		Result: arrayOf(stringSchema), Handler: handlers.ListCollections},
+	{Method: fiber.MethodPost, Path: "/collections/create", Scope: auth.ScopeAdmin, Summary: "Create a collection.",
+		Params: []Param{{"name", "string", true, "The name of the collection."}},
+		Result: nullSchema, Status: fiber.StatusCreated, Errors: []int{fiber.StatusBadRequest, fiber.StatusConflict}, Handler: handlers.CreateCollection},
+	{Method: fiber.MethodDelete, Path: "/collections/drop", Scope: auth.ScopeAdmin, Summary: "Drop a collection. The default collection can't be dropped.",
+		Params: []Param{{"name", "string", true, "The name of the collection."}},
+		Result: nullSchema, Errors: []int{fiber.StatusBadRequest, fiber.StatusNotFound}, Handler: handlers.DropCollection},
+
+	// Cache Handlers
+	{Method: fiber.MethodGet, Path: "/cache/values", Scope: auth.ScopeRead, Summary: "Get the records of the cache.",
+		Result: arrayOf(recordSchema), Raw: true, Cache: handlers.Values},
+	{Method: fiber.MethodGet, Path: "/cache/length", Scope: auth.ScopeRead, Summary: "Get the number of records in the cache.",
+		Result: integerSchema, Cache: handlers.Length},
+	{Method: fiber.MethodPost, Path: "/cache/clean", Scope: auth.ScopeAdmin, Summary: "Delete every record of the cache.",
+		Result: nullSchema, Cache: handlers.Clean},
+	{Method: fiber.MethodPost, Path: "/cache/set", Scope: auth.ScopeWrite, Summary: "Set a record. The key must not exist.",
+		Params: []Param{keyParam}, Body: recordSchema,
+		Result: nullSchema, Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.Set},
+	{Method: fiber.MethodDelete, Path: "/cache/delete", Scope: auth.ScopeWrite, Summary: "Delete a record.",
+		Params: []Param{keyParam},
+		Result: nullSchema, Errors: []int{fiber.StatusBadRequest}, Cache: handlers.Delete},
+	{Method: fiber.MethodGet, Path: "/cache/get", Scope: auth.ScopeRead, Summary: "Get a record.",
+		Params: []Param{keyParam},
+		Result: recordSchema, Raw: true, Errors: []int{fiber.StatusBadRequest}, Cache: handlers.Get},
+	{Method: fiber.MethodGet, Path: "/cache/get/all", Scope: auth.ScopeRead, Summary: "Get the records of the cache, mapped by key.",
+		Result: mapOf(recordSchema), Raw: true, Cache: handlers.GetAll},
+	{Method: fiber.MethodGet, Path: "/cache/keys", Scope: auth.ScopeRead, Summary: "Get the keys of the cache.",
+		Result: arrayOf(stringSchema), Raw: true, Cache: handlers.Keys},
+	{Method: fiber.MethodGet, Path: "/cache/info", Scope: auth.ScopeRead, Summary: "Get the number of records, and the size of the full-text index.",
+		Result: objectSchema, Errors: []int{fiber.StatusUnprocessableEntity}, Cache: handlers.Info},
+	{Method: fiber.MethodGet, Path: "/cache/info/testing", Scope: auth.ScopeAdmin, Summary: "Get the info of the cache, with its contents.",
+		Result: objectSchema, Errors: []int{fiber.StatusUnprocessableEntity}, Cache: handlers.InfoForTesting},
+	{Method: fiber.MethodGet, Path: "/cache/exists", Scope: auth.ScopeRead, Summary: "Check whether a key exists.",
+		Params: []Param{keyParam},
+		Result: booleanSchema, Errors: []int{fiber.StatusBadRequest}, Cache: handlers.Exists},
+	{Method: fiber.MethodGet, Path: "/cache/export", Scope: auth.ScopeRead, Summary: "Stream the records as a json object mapped by key, or as ndjson. The full-text fields are wrapped in $hermes.full_text maps.",
+		Params: []Param{{"format", "string", false, "json (default) or ndjson."}},
+		Result: mapOf(recordSchema), Raw: true, Produces: []string{fiber.MIMEApplicationJSON, "application/x-ndjson"}, Errors: []int{fiber.StatusBadRequest}, Cache: handlers.Export},
+	{Method: fiber.MethodGet, Path: "/cache/schema", Scope: auth.ScopeRead, Summary: "Get the schema of the records, or null if no schema is set.",
+		Result: nullable(ref("Schema")), Cache: handlers.GetSchema},
+	{Method: fiber.MethodPost, Path: "/cache/schema", Scope: auth.ScopeAdmin, Summary: "Set the schema of the records. null removes the schema.",
+		Body:   nullable(ref("Schema")),
+		Result: nullSchema, Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.SetSchema},
+
+	// Full-text Cache Handlers
+	{Method: fiber.MethodPost, Path: "/ft/init", Scope: auth.ScopeAdmin, Summary: "Initialize the full-text index.",
+		Params: []Param{maxSizeParam, maxBytesParam, minWordLengthParam},
+		Result: nullSchema, Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.FTInit},
+	{Method: fiber.MethodPost, Path: "/ft/init/json", Scope: auth.ScopeAdmin, Summary: "Initialize the full-text index with records, mapped by key.",
+		Params: []Param{maxSizeParam, maxBytesParam, minWordLengthParam}, Body: mapOf(recordSchema),
+		Result: nullSchema, Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.FTInitJson},
+	{Method: fiber.MethodPost, Path: "/ft/clean", Scope: auth.ScopeAdmin, Summary: "Clear the full-text index.",
+		Result: nullSchema, Errors: []int{fiber.StatusUnprocessableEntity}, Cache: handlers.FTClean},
+	{Method: fiber.MethodGet, Path: "/ft/search", Scope: auth.ScopeRead, Summary: "Search the full-text index.",
+		Params: []Param{queryParam, limitParam, strictParam},
+		Result: arrayOf(recordSchema), Raw: true, Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.Search},
+	{Method: fiber.MethodGet, Path: "/ft/search/oneword", Scope: auth.ScopeRead, Summary: "Search the full-text index for a single word.",
+		Params: []Param{queryParam, limitParam, strictParam},
+		Result: arrayOf(recordSchema), Raw: true, Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.SearchOneWord},
+	{Method: fiber.MethodGet, Path: "/ft/search/values", Scope: auth.ScopeRead, Summary: "Search the values of the records.",
+		Params: []Param{queryParam, limitParam, {"schema", "string", true, `The JSON object of the fields to search, for example {"name":true}.`}},
+		Result: arrayOf(recordSchema), Raw: true, Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.SearchValues},
+	{Method: fiber.MethodGet, Path: "/ft/search/withkey", Scope: auth.ScopeRead, Summary: "Search the values of a field of the records.",
+		Params: []Param{queryParam, {"key", "string", true, "The field to search."}, limitParam},
+		Result: arrayOf(recordSchema), Raw: true, Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.SearchWithKey},
+	{Method: fiber.MethodPost, Path: "/ft/queries/register", Scope: auth.ScopeWrite, Summary: "Register a search query, so that documents can be percolated against it.",
+		Params: []Param{{"id", "string", true, "The id of the query."}, queryParam, strictParam},
+		Result: nullSchema, Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.RegisterQuery},
+	{Method: fiber.MethodDelete, Path: "/ft/queries/unregister", Scope: auth.ScopeWrite, Summary: "Remove a registered search query.",
+		Params: []Param{{"id", "string", true, "The id of the query."}},
+		Result: nullSchema, Cache: handlers.UnregisterQuery},
+	{Method: fiber.MethodPost, Path: "/ft/percolate", Scope: auth.ScopeRead, Summary: "Get the ids of the registered queries that a document matches.",
+		Body:   recordSchema,
+		Result: arrayOf(stringSchema), Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.Percolate},
+	{Method: fiber.MethodPost, Path: "/ft/maxbytes", Scope: auth.ScopeAdmin, Summary: "Set the maximum size of the full-text index.",
+		Params: []Param{maxBytesParam},
+		Result: nullSchema, Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.FTSetMaxBytes},
+	{Method: fiber.MethodPost, Path: "/ft/maxsize", Scope: auth.ScopeAdmin, Summary: "Set the maximum number of words in the full-text index.",
+		Params: []Param{maxSizeParam},
+		Result: nullSchema, Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.FTSetMaxSize},
+	{Method: fiber.MethodPost, Path: "/ft/minwordlength", Scope: auth.ScopeAdmin, Summary: "Set the minimum length of the indexed words, and rebuild the index.",
+		Params: []Param{minWordLengthParam},
+		Result: nullSchema, Errors: []int{fiber.StatusBadRequest, fiber.StatusUnprocessableEntity}, Cache: handlers.FTSetMinWordLength},
+	{Method: fiber.MethodGet, Path: "/ft/storage", Scope: auth.ScopeAdmin, Summary: "Get the full-text index.",
+		Result: objectSchema, Raw: true, Errors: []int{fiber.StatusUnprocessableEntity}, Cache: handlers.FTStorage},
+	{Method: fiber.MethodGet, Path: "/ft/storage/size", Scope: auth.ScopeRead, Summary: "Get the size of the full-text index, in bytes.",
+		Result: integerSchema, Errors: []int{fiber.StatusUnprocessableEntity}, Cache: handlers.FTStorageSize},
+	{Method: fiber.MethodGet, Path: "/ft/storage/length", Scope: auth.ScopeRead, Summary: "Get the number of words in the full-text index.",
+		Result: integerSchema, Errors: []int{fiber.StatusUnprocessableEntity}, Cache: handlers.FTStorageLength},
+	{Method: fiber.MethodGet, Path: "/ft/isinitialized", Scope: auth.ScopeRead, Summary: "Check whether the full-text index is initialized.",
+		Result: booleanSchema, Cache: handlers.FTIsInitialized},
+	{Method: fiber.MethodPost, Path: "/ft/indices/sequence", Scope: auth.ScopeAdmin, Summary: "Renumber the record indices of the full-text index.",
+		Result: nullSchema, Cache: handlers.FTSequenceIndices},
+}
+
+// SetRoutes is a function that sets the routes for the hermes Cache API, without authentication.
+// Every cache route takes an optional "collection" query parameter. If it's not provided, the default collection is used.
 // Parameters:
 //   - app (*fiber.App): A pointer to a fiber.App struct.
-//   - cache (*hermes.Cache): A pointer to a hermes.Cache struct.
+//   - cs (*hermes.Collections): A pointer to a hermes.Collections struct.
 //
 // Returns:
 //   - void: This function does not return anything.
-func SetRoutes(app *fiber.App, cache *hermes.Cache) {
-	// Dev Testing Handler
-	app.Get("/dev/hermes", func(c *fiber.Ctx) error {
-		return c.SendString("hermes Cache API Successfully Running!")
-	})
+func SetRoutes(app *fiber.App, cs *hermes.Collections) {
+	SetRoutesWithAuth(app, cs, nil)
+}
 
-	// Cache Handlers
-	app.Get("/cache/values", handlers.Values(cache))
-	app.Get("/cache/length", handlers.Length(cache))
-	app.Post("/cache/clean", handlers.Clean(cache))
-	app.Post("/cache/set", handlers.Set(cache))
-	app.Delete("/cache/delete", handlers.Delete(cache))
-	app.Get("/cache/get", handlers.Get(cache))
-	app.Get("/cache/get/all", handlers.GetAll(cache))
-	app.Get("/cache/keys", handlers.Keys(cache))
-	app.Get("/cache/info", handlers.Info(cache))
-	app.Get("/cache/info/testing", handlers.InfoForTesting(cache))
-	app.Get("/cache/exists", handlers.Exists(cache))
+// Config is a struct that represents the settings of the routes.
+// Fields:
+//   - Auth (auth.Authenticator): The authenticator of the requests. If nil, authentication is disabled.
+//   - ReadOnly (bool): Whether the routes that require the write or admin scope respond with 403, like on a replication follower.
+//   - Writes (func(Route) fiber.Handler): The handlers of the routes that require the write or admin scope, instead of their own
+//     handlers, like the handlers of a cluster that apply the writes through its log. The routes that it returns nil for respond with 403.
+//   - Reads (fiber.Handler): A handler that is called before the handlers of the read routes, like the handler of a cluster that serves
+//     the reads on its leader. If nil, the reads are served directly.
+type Config struct {
+	Auth     auth.Authenticator
+	ReadOnly bool
+	Writes   func(r Route) fiber.Handler
+	Reads    fiber.Handler
+}
 
-	// Full-text Cache Handlers
-	app.Post("/ft/init", handlers.FTInit(cache))
-	app.Post("/ft/init/json", handlers.FTInitJson(cache))
-	app.Post("/ft/clean", handlers.FTClean(cache))
-	app.Get("/ft/search", handlers.Search(cache))
-	app.Get("/ft/search/oneword", handlers.SearchOneWord(cache))
-	app.Get("/ft/search/values", handlers.SearchValues(cache))
-	app.Get("/ft/search/withkey", handlers.SearchWithKey(cache))
-	app.Post("/ft/maxbytes", handlers.FTSetMaxBytes(cache))
-	app.Post("/ft/maxsize", handlers.FTSetMaxSize(cache))
-	app.Post("/ft/minwordlength", handlers.FTSetMinWordLength(cache))
-	app.Get("/ft/storage", handlers.FTStorage(cache))
-	app.Get("/ft/storage/size", handlers.FTStorageSize(cache))
-	app.Get("/ft/storage/length", handlers.FTStorageLength(cache))
-	app.Get("/ft/isinitialized", handlers.FTIsInitialized(cache))
-	app.Post("/ft/indices/sequence", handlers.FTSequenceIndices(cache))
+// SetRoutesWithAuth is a function that sets the routes for the hermes Cache API, and serves their OpenAPI document at /openapi.json.
+// Each route requires a scope: read routes only read the cache, write routes set and delete records,
+// and admin routes clean the cache, change the full-text settings, or manage the collections.
+// Parameters:
+//   - app (*fiber.App): A pointer to a fiber.App struct.
+//   - cs (*hermes.Collections): A pointer to a hermes.Collections struct.
+//   - a (auth.Authenticator): The authenticator of the requests. If nil, authentication is disabled.
+//
+// Returns:
+//   - void: This function does not return anything.
+func SetRoutesWithAuth(app *fiber.App, cs *hermes.Collections, a auth.Authenticator) {
+	SetRoutesWithConfig(app, cs, Config{Auth: a})
+}
+
+// SetRoutesWithConfig is a function that sets the routes for the hermes Cache API with their settings,
+// and serves their OpenAPI document at /openapi.json.
+// Parameters:
+//   - app (*fiber.App): A pointer to a fiber.App struct.
+//   - cs (*hermes.Collections): A pointer to a hermes.Collections struct.
+//   - config (Config): The settings of the routes.
+//
+// Returns:
+//   - void: This function does not return anything.
+func SetRoutesWithConfig(app *fiber.App, cs *hermes.Collections, config Config) {
+	for _, r := range Routes {
+		var handler fiber.Handler
+		if r.Cache != nil {
+			handler = handlers.Collection(cs, r.Cache)
+		} else {
+			handler = r.Handler(cs)
+		}
+		switch {
+		case config.Writes != nil && r.Scope.Writes():
+			if write := config.Writes(r); write != nil {
+				app.Add(r.Method, r.Path, auth.Require(config.Auth, r.Scope), write)
+			} else {
+				app.Add(r.Method, r.Path, auth.Require(config.Auth, r.Scope), readOnly)
+			}
+		case config.ReadOnly && r.Scope.Writes():
+			app.Add(r.Method, r.Path, auth.Require(config.Auth, r.Scope), readOnly)
+		case config.Reads != nil && len(r.Scope) > 0:
+			app.Add(r.Method, r.Path, auth.Require(config.Auth, r.Scope), config.Reads, handler)
+		case len(r.Scope) > 0:
+			app.Add(r.Method, r.Path, auth.Require(config.Auth, r.Scope), handler)
+		default:
+			app.Add(r.Method, r.Path, handler)
+		}
+	}
+
+	// Serve the OpenAPI document
+	var doc map[string]any = OpenAPI()
+	app.Get("/openapi.json", func(c *fiber.Ctx) error {
+		return c.JSON(doc)
+	})
+}
+
+// readOnly is a function that responds to the requests that write to a read-only server.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a fiber context.
+//
+// Returns:
+//   - error: The error of the 403 response, if any.
+func readOnly(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{"success": false, "error": auth.ErrReadOnly.Error()})
 }
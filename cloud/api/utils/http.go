@@ -2,24 +2,64 @@ package utils
 
 import (
 	"fmt"
+
+	"github.com/gofiber/fiber/v2"
 )
 
-// Error is a function that returns an error message with the provided error.
+// Error is a function that responds with the status code and a JSON-encoded error message.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a Fiber context.
+//   - status (int): The status code of the response.
+//   - err (T): The error to include in the error message.
+//
+// Returns:
+//   - error: An error if the response could not be written, or nil if successful.
+func Error[T any](ctx *fiber.Ctx, status int, err T) error {
+	return ctx.Status(status).JSON(fiber.Map{"success": false, "error": fmt.Sprint(err)})
+}
+
+// BadRequest is a function that responds with a 400 status code, for requests with missing or invalid params.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a Fiber context.
+//   - err (T): The error to include in the error message.
+//
+// Returns:
+//   - error: An error if the response could not be written, or nil if successful.
+func BadRequest[T any](ctx *fiber.Ctx, err T) error {
+	return Error(ctx, fiber.StatusBadRequest, err)
+}
+
+// NotFound is a function that responds with a 404 status code, for requests of a collection or key that does not exist.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a Fiber context.
+//   - err (T): The error to include in the error message.
+//
+// Returns:
+//   - error: An error if the response could not be written, or nil if successful.
+func NotFound[T any](ctx *fiber.Ctx, err T) error {
+	return Error(ctx, fiber.StatusNotFound, err)
+}
+
+// Failed is a function that responds with a 422 status code, for valid requests that the cache could not apply,
+// for example because the full-text index is not initialized or a record doesn't match the schema.
 // Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a Fiber context.
 //   - err (T): The error to include in the error message.
 //
 // Returns:
-//   - []byte: A byte slice containing the error message with the provided error.
-func Error[T any](err T) []byte {
-	return []byte(fmt.Sprintf(`{"success":false,"error":"%v"}`, err))
+//   - error: An error if the response could not be written, or nil if successful.
+func Failed[T any](ctx *fiber.Ctx, err T) error {
+	return Error(ctx, fiber.StatusUnprocessableEntity, err)
 }
 
-// Success is a function that returns a success message with the provided data.
+// Success is a function that responds with a JSON-encoded success message with the provided data.
+// The status code is 200, unless it was set before.
 // Parameters:
-//   - v (T): The data to include in the success message.
+//   - ctx (*fiber.Ctx): A pointer to a Fiber context.
+//   - v (any): The data to include in the success message.
 //
 // Returns:
-//   - []byte: A byte slice containing the success message with the provided data.
-func Success[T any](v T) []byte {
-	return []byte(fmt.Sprintf(`{"success":true,"data":%v}`, v))
+//   - error: An error if the data could not be encoded, or nil if successful.
+func Success(ctx *fiber.Ctx, v any) error {
+	return ctx.JSON(fiber.Map{"success": true, "data": v})
 }
@@ -1,27 +1,25 @@
 package utils
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// GetValueParam is a function that retrieves a value from a query parameter in a Fiber context and decodes it into a value of type T.
+// GetValueParam is a function that retrieves a value from the JSON request body in a Fiber context and decodes it into a value of type T.
+// Requests without a body can send the value as a base64-encoded "value" query parameter instead.
 // Parameters:
 //   - ctx (*fiber.Ctx): A pointer to a Fiber context.
 //   - value (*T): A pointer to a value of type T to store the decoded value.
 //
 // Returns:
-//   - error: An error message if the decoding fails or the query parameter is invalid, or nil if the decoding is successful.
+//   - error: An error message if the decoding fails or the value is not provided, or nil if the decoding is successful.
 func GetValueParam[T any](ctx *fiber.Ctx, value *T) error {
-	if v := ctx.Query("value"); len(v) == 0 {
-		return errors.New("invalid value")
-	} else if err := Decode(v, &value); err != nil {
-		return err
-	}
-	return nil
+	return getBody(ctx, "value", value)
 }
 
 // GetMaxSizeParam is a function that retrieves the "maxsize" query parameter from a Fiber context and stores it in an integer pointer.
@@ -33,7 +31,6 @@ func GetValueParam[T any](ctx *fiber.Ctx, value *T) error {
 //   - error: An error message if the "maxsize" query parameter is invalid or cannot be converted to an integer, or nil if the retrieval is successful.
 func GetMaxSizeParam(ctx *fiber.Ctx, maxSize *int) error {
 	if s := ctx.Query("maxsize"); len(s) == 0 {
-		fmt.Println(s)
 		return errors.New("invalid maxsize")
 	} else if i, err := strconv.Atoi(s); err != nil {
 		return err
@@ -73,23 +70,20 @@ func GetMinWordLengthParam(ctx *fiber.Ctx, minWordLength *int) error {
 	return nil
 }
 
-// GetJSONParam is a function that retrieves a JSON-encoded value from a query parameter in a Fiber context and decodes it into a value of type T.
+// GetJSONParam is a function that retrieves the JSON request body in a Fiber context and decodes it into a value of type T.
+// Requests without a body can send the JSON as a base64-encoded "json" query parameter instead.
 // Parameters:
 //   - ctx (*fiber.Ctx): A pointer to a Fiber context.
 //   - json (*T): A pointer to a value of type T to store the decoded JSON.
 //
 // Returns:
-//   - error: An error message if the decoding fails or the query parameter is invalid, or nil if the decoding is successful.
+//   - error: An error message if the decoding fails or the JSON is not provided, or nil if the decoding is successful.
 func GetJSONParam[T any](ctx *fiber.Ctx, json *T) error {
-	if s := ctx.Query("json"); len(s) == 0 {
-		return errors.New("invalid json")
-	} else if err := Decode(s, &json); err != nil {
-		return err
-	}
-	return nil
+	return getBody(ctx, "json", json)
 }
 
 // GetSchemaParam is a function that retrieves a schema from a query parameter in a Fiber context and decodes it into a map of string keys and boolean values.
+// The schema is a JSON object, or a base64-encoded JSON object for the older clients.
 // Parameters:
 //   - ctx (*fiber.Ctx): A pointer to a Fiber context.
 //   - schema (*map[string]bool): A pointer to a map of string keys and boolean values to store the decoded schema.
@@ -100,6 +94,8 @@ func GetSchemaParam(ctx *fiber.Ctx, schema *map[string]bool) error {
 	// Get the schema from the url params
 	if s := ctx.Query("schema"); len(s) == 0 {
 		return errors.New("invalid schema")
+	} else if strings.HasPrefix(s, "{") {
+		return json.Unmarshal([]byte(s), schema)
 	} else if err := Decode(s, schema); err != nil {
 		return err
 	}
@@ -143,3 +139,24 @@ func GetStrictParam(ctx *fiber.Ctx, strict *bool) error {
 	}
 	return nil
 }
+
+// getBody is a function that decodes the JSON request body in a Fiber context into a value of type T.
+// If the request has no body, the base64-encoded query parameter with the provided name is decoded instead.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a Fiber context.
+//   - name (string): The name of the query parameter that is read if the request has no body.
+//   - v (*T): A pointer to a value of type T to store the decoded JSON.
+//
+// Returns:
+//   - error: An error message if the decoding fails, the content type isn't JSON, or nothing is provided, or nil if the decoding is successful.
+func getBody[T any](ctx *fiber.Ctx, name string, v *T) error {
+	if body := ctx.Body(); len(body) > 0 {
+		if t := ctx.Get(fiber.HeaderContentType); len(t) > 0 && !strings.HasPrefix(t, fiber.MIMEApplicationJSON) {
+			return fmt.Errorf("invalid content type %s, expected %s", t, fiber.MIMEApplicationJSON)
+		}
+		return json.Unmarshal(body, v)
+	} else if s := ctx.Query(name); len(s) > 0 {
+		return Decode(s, v)
+	}
+	return fmt.Errorf("%s not provided", name)
+}
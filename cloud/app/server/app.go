@@ -1,39 +1,79 @@
 package main
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"os"
 
 	utils "hermes/utils"
-
-	"github.com/gofiber/fiber/v2"
-	hermes "github.com/realTristan/hermes"
-	Socket "github.com/realTristan/hermes/cloud/socket"
 )
 
+// Command struct for the subcommands of the server binary
+type command struct {
+	run   func(args []string) error
+	about string
+}
+
+// The subcommands, in the order they're listed in the usage
+var commandNames = []string{"serve", "load", "export", "snapshot", "import", "token", "version"}
+
+// Map of the subcommands
+var commands = map[string]command{
+	"serve":    {serve, "serve the collections over the REST API, the websocket, gRPC and the redis protocol"},
+	"load":     {load, "load and index a data file, and save it to the snapshot directory"},
+	"export":   {export, "write a collection of the snapshot directory as json or ndjson"},
+	"snapshot": {snapshot, "ask a running server to save its collections to its snapshot directory"},
+	"import":   {importCSV, "import a csv file into a collection, and save it to the snapshot directory"},
+	"token":    {signToken, "sign an hmac token for a client"},
+	"version":  {printVersion, "print the version of the server"},
+}
+
 // Main function
 func main() {
-	// Verify that the user is trying to serve the cache
-	if len(os.Args) < 1 || os.Args[1] != "serve" {
-		panic("incorrect usage. example: ./hermes serve -p {port}")
+	// Verify that the user provided a command
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
 	}
-
-	// Get the arg data
-	var args, err = utils.GetArgData(os.Args)
-	if err != nil || args.Port() == nil {
-		panic("incorrect usage. example: ./hermes serve -p {port}")
+	var name string = os.Args[1]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		usage(os.Stdout)
+		return
 	}
 
-	// Get the port and json file
-	var cache *hermes.Cache = hermes.InitCache()
+	// Get the command
+	var cmd, ok = commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "hermes: unknown command %s\n\n", name)
+		usage(os.Stderr)
+		os.Exit(2)
+	}
 
-	// Initialize a new fiber app
-	var app *fiber.App = fiber.New(fiber.Config{
-		Prefork:      false,
-		ServerHeader: "hermes",
-	})
-	Socket.SetRouter(app, cache)
+	// Run the command. The flag errors have already been printed with the usage
+	var err error = cmd.run(os.Args[2:])
+	var usageErr *utils.UsageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return
+	case errors.As(err, &usageErr):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "hermes %s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
 
-	// Listen on the port
-	log.Fatal(app.Listen(args.Port().(string)))
+// Print the usage of the server binary
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: hermes <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, name := range commandNames {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].about)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'hermes <command> -h' for the flags of a command.")
 }
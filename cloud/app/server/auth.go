@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	utils "hermes/utils"
+
+	"github.com/realTristan/hermes/cloud/auth"
+)
+
+// Create the authenticator from the auth args. Returns nil if
+// no credentials are configured, which disables authentication
+func authenticator(args utils.AuthArgs) (auth.Authenticator, error) {
+	var auths []auth.Authenticator
+	if len(args.APIKeys) > 0 {
+		if a, err := auth.LoadAPIKeys(args.APIKeys); err != nil {
+			return nil, err
+		} else {
+			auths = append(auths, a)
+		}
+	}
+	if len(args.HMACSecret) > 0 {
+		if a, err := auth.LoadHMACTokens(args.HMACSecret); err != nil {
+			return nil, err
+		} else {
+			auths = append(auths, a)
+		}
+	}
+	if len(args.JWTKey) > 0 {
+		if a, err := auth.LoadJWT(args.JWTKey, auth.JWTOptions{
+			Issuer:   args.JWTIssuer,
+			Audience: args.JWTAudience,
+			Leeway:   30 * time.Second,
+		}); err != nil {
+			return nil, err
+		} else {
+			auths = append(auths, a)
+		}
+	}
+	return auth.Chain(auths...), nil
+}
+
+// Sign an hmac token with the secret file, and print it
+func signToken(args []string) error {
+	var (
+		flags  *flag.FlagSet = flag.NewFlagSet("token", flag.ContinueOnError)
+		secret string
+		id     auth.Identity
+		scope  string
+		ttl    time.Duration
+	)
+	flags.SetOutput(os.Stderr)
+	flags.StringVar(&secret, "hmac-secret", "", "the file that contains the hmac secret")
+	flags.StringVar(&id.Subject, "sub", "", "the name of the client")
+	flags.StringVar(&scope, "scope", string(auth.ScopeRead), "the scope of the token: read, write or admin")
+	flags.DurationVar(&ttl, "ttl", 24*time.Hour, "how long the token is valid. zero for tokens that don't expire")
+
+	// Parse the flags
+	if err := flags.Parse(args); err != nil {
+		return &utils.UsageError{Err: err}
+	} else if len(secret) == 0 {
+		return errors.New("no hmac secret file provided")
+	}
+	var err error
+	if id.Scope, err = auth.ParseScope(scope); err != nil {
+		return err
+	}
+
+	// Sign the token
+	var tokens *auth.HMACTokens
+	if tokens, err = auth.LoadHMACTokens(secret); err != nil {
+		return err
+	}
+	var token string
+	if token, err = tokens.Sign(id, ttl); err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	utils "hermes/utils"
+
+	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/cloud/cluster"
+)
+
+// How long a node waits before it tries to join the cluster again
+const joinRetryInterval time.Duration = 2 * time.Second
+
+// Start the node of the cluster in the settings. The raft logs are
+// written to stderr, at the level of the server logs
+func newNode(config *utils.Config, collections *hermes.Collections, tlsConfig *tls.Config) (*cluster.Node, error) {
+	return cluster.NewNode(collections, cluster.Config{
+		ID:        config.Cluster.ID,
+		Address:   config.Cluster.Address,
+		Advertise: config.Cluster.Advertise,
+		API:       clusterAPI(config, tlsConfig != nil),
+		Dir:       config.Cluster.Dir,
+		Bootstrap: config.Cluster.Bootstrap,
+		LogOutput: os.Stderr,
+		LogLevel:  config.LogLevel,
+	})
+}
+
+// Get the url of the api of the node. It defaults to the host and port
+// of the server, or to localhost if the server listens on every interface
+func clusterAPI(config *utils.Config, secure bool) string {
+	if len(config.Cluster.API) > 0 {
+		return strings.TrimSuffix(config.Cluster.API, "/")
+	}
+	var host string = config.Host
+	if ip := net.ParseIP(host); len(host) == 0 || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	var scheme string = "http"
+	if secure {
+		scheme = "https"
+	}
+	return scheme + "://" + net.JoinHostPort(host, strconv.Itoa(config.Port))
+}
+
+// Ask a node of the cluster to add this node, until it succeeds or the context is
+// done. The node forwards the request to the leader if it's not the leader itself
+func joinCluster(ctx context.Context, config *utils.Config, secure bool) {
+	var (
+		args    utils.ClusterArgs = config.Cluster
+		address string            = args.Address
+	)
+	if len(args.Advertise) > 0 {
+		address = args.Advertise
+	}
+	var body, _ = json.Marshal(cluster.Server{ID: args.ID, Address: address, API: clusterAPI(config, secure)})
+	var tlsConfig, err = loadCA(args.CA)
+	if err != nil {
+		utils.Logf(utils.LogError, "failed to join the cluster: %v", err)
+		return
+	}
+	var client *http.Client = &http.Client{
+		Timeout:   cluster.DefaultTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+	for {
+		if err := join(ctx, client, args, body); err == nil {
+			utils.Logf(utils.LogInfo, "joined the cluster through %s", args.Join)
+			return
+		} else {
+			utils.Logf(utils.LogWarn, "failed to join the cluster through %s: %v", args.Join, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(joinRetryInterval):
+		}
+	}
+}
+
+// Send the join request of the node
+func join(ctx context.Context, client *http.Client, args utils.ClusterArgs, body []byte) error {
+	var r, err = http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(args.Join, "/")+"/cluster/join", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	if len(args.Token) > 0 {
+		r.Header.Set("Authorization", "Bearer "+args.Token)
+	}
+	resp, err := client.Do(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var message, _ = io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	utils "hermes/utils"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// Write a collection of the snapshot directory as json or ndjson,
+// in the format that the -data flag and cache.FTInitWithJson() read
+func export(args []string) error {
+	var collection, format, output string
+	var config, err = utils.ParseConfig("export", args, os.Stderr, func(flags *flag.FlagSet) {
+		flags.StringVar(&collection, "collection", hermes.DefaultCollection, "the collection to export")
+		flags.StringVar(&format, "format", string(hermes.ExportHash), "the format to export: json or ndjson")
+		flags.StringVar(&output, "o", "", "the file to write (default: stdout)")
+	})
+	if err != nil {
+		return err
+	} else if len(config.Snapshot.Dir) == 0 {
+		return errors.New("no snapshot directory provided. example: hermes export -snapshot-dir ./snapshot -o data.json")
+	} else if format != string(hermes.ExportHash) && format != string(hermes.ExportNDJSON) {
+		return fmt.Errorf("invalid format %s. expected json or ndjson", format)
+	}
+
+	// Find the collection in the snapshot
+	var s *snapshots = newSnapshots(config.Snapshot.Dir)
+	var m *manifest
+	if m, err = s.manifest(); err != nil {
+		return err
+	}
+	var cm, ok = m.Collections[collection]
+	if !ok {
+		return fmt.Errorf("collection %s is not in the snapshot", collection)
+	}
+
+	// Get the output writer
+	var w io.Writer = os.Stdout
+	if len(output) > 0 {
+		var f *os.File
+		if f, err = os.Create(filepath.Clean(output)); err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	// The snapshot files are already in the ndjson format
+	if format == string(hermes.ExportNDJSON) {
+		var f *os.File
+		if f, err = os.Open(filepath.Join(s.dir, filepath.Base(cm.File))); err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(w, bufio.NewReader(f))
+		return err
+	}
+
+	// Load the collection, and write it in the hash format
+	var c *hermes.Cache = hermes.InitCache()
+	if err := s.restoreCollection(c, cm); err != nil {
+		return err
+	}
+	return c.ExportJSON(w, hermes.ExportHash)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler for the liveness endpoint. Responds as long as the server answers requests
+func healthz(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"status": "ok",
+	})
+}
+
+// Readiness struct for reporting whether the server accepts requests.
+// The listener is only opened once the collections are loaded, so
+// the server is ready as soon as it answers, until it shuts down.
+// A follower is only ready once it has received the collections of its leader
+type readiness struct {
+	stopping atomic.Bool
+	loaded   map[string]int
+	startup  time.Duration
+	synced   func() bool
+}
+
+// Create the readiness report with the number of records that were
+// loaded into each collection, and how long the startup took
+func newReadiness(loaded map[string]int, startup time.Duration) *readiness {
+	return &readiness{
+		loaded:  loaded,
+		startup: startup,
+	}
+}
+
+// Report that the server is shutting down
+func (r *readiness) stop() {
+	r.stopping.Store(true)
+}
+
+// Handler for the readiness endpoint. Responds with 503 once the server is
+// shutting down, or while a follower hasn't received the collections yet
+func (r *readiness) handler(c *fiber.Ctx) error {
+	if r.stopping.Load() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"status": "stopping",
+		})
+	} else if r.synced != nil && !r.synced() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"status": "syncing",
+		})
+	}
+	return c.JSON(fiber.Map{
+		"status":  "ready",
+		"loaded":  r.loaded,
+		"startup": r.startup.Round(time.Millisecond).String(),
+	})
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	utils "hermes/utils"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// Import a csv file into a collection, like the load command. With a
+// snapshot directory, the collection is saved in the snapshot, which the
+// server restores at startup. The records can also be written as a json
+// data file that can be loaded with cache.FTInitWithJson()
+func importCSV(args []string) error {
+	var data, err = utils.GetImportArgs(args, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	// Open the csv file
+	var input *os.File
+	if input, err = os.Open(filepath.Clean(data.Input)); err != nil {
+		return err
+	}
+	defer input.Close()
+
+	// Import the rows into the collection
+	var (
+		c     *hermes.Cache = hermes.InitCache()
+		start time.Time     = time.Now()
+	)
+	if err := c.FTInit(data.FT.MaxSize, data.FT.MaxBytes, data.FT.MinWordLength); err != nil {
+		return err
+	}
+	var progress hermes.Progress = func(loaded int) {
+		if loaded%progressInterval == 0 {
+			utils.Logf(utils.LogInfo, "imported %d rows from %s", loaded, data.Input)
+		}
+	}
+	if err := c.ImportCSV(bufio.NewReader(input), data.Mapping, progress); err != nil {
+		return err
+	}
+	var words, _ = c.FTStorageLength()
+	var size, _ = c.FTStorageSize()
+	fmt.Printf("imported %d records in %s: %d words, %d bytes\n", c.Length(), time.Since(start).Round(time.Millisecond), words, size)
+
+	// Write the json data file
+	if len(data.Output) > 0 {
+		if err := writeJSON(c, data.Output); err != nil {
+			return err
+		}
+		fmt.Printf("wrote the records to %s\n", data.Output)
+	}
+
+	// Save the collection in the snapshot
+	if len(data.SnapshotDir) > 0 {
+		if err := newSnapshots(data.SnapshotDir).saveOne(data.Collection, c); err != nil {
+			return err
+		}
+		fmt.Printf("saved the %s collection to %s\n", data.Collection, data.SnapshotDir)
+	}
+	return nil
+}
+
+// Write the records of a cache to a json data file
+func writeJSON(c *hermes.Cache, file string) error {
+	var f, err = os.Create(filepath.Clean(file))
+	if err != nil {
+		return err
+	}
+	if err := c.ExportJSON(f, hermes.ExportHash); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
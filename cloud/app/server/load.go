@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	utils "hermes/utils"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// How many records are loaded between the progress logs
+const progressInterval int = 100000
+
+// Load a json or ndjson data file into a cache, and initialize its
+// full-text index with the settings. The ndjson files are detected
+// by their .ndjson or .jsonl extension
+func loadData(c *hermes.Cache, file string, ft utils.FTArgs) error {
+	var f, err = os.Open(filepath.Clean(file))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Log the progress of large files
+	var progress hermes.Progress = func(loaded int) {
+		if loaded%progressInterval == 0 {
+			utils.Logf(utils.LogInfo, "loaded %d records from %s", loaded, file)
+		}
+	}
+
+	// Load the records
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".ndjson", ".jsonl":
+		if err := c.FTInit(ft.MaxSize, ft.MaxBytes, ft.MinWordLength); err != nil {
+			return err
+		}
+		return c.LoadNDJSON(bufio.NewReader(f), progress)
+	default:
+		return c.FTInitWithReader(bufio.NewReader(f), ft.MaxSize, ft.MaxBytes, ft.MinWordLength, progress)
+	}
+}
+
+// Load and index a data file without serving it, to verify the data and
+// the full-text limits. With a snapshot directory, the data is saved as
+// a collection of the snapshot, which the server restores at startup
+func load(args []string) error {
+	var collection string
+	var config, err = utils.ParseConfig("load", args, os.Stderr, func(flags *flag.FlagSet) {
+		flags.StringVar(&collection, "collection", hermes.DefaultCollection, "the collection to save the data as, in the snapshot directory")
+	})
+	if err != nil {
+		return err
+	} else if len(config.Data) == 0 {
+		return errors.New("no data file provided. example: hermes load -data data.json -snapshot-dir ./snapshot")
+	}
+
+	// Load the data
+	var (
+		c     *hermes.Cache = hermes.InitCache()
+		start time.Time     = time.Now()
+	)
+	if err := loadData(c, config.Data, config.FT); err != nil {
+		return err
+	}
+	var words, _ = c.FTStorageLength()
+	var size, _ = c.FTStorageSize()
+	fmt.Printf("loaded %d records in %s: %d words, %d bytes\n", c.Length(), time.Since(start).Round(time.Millisecond), words, size)
+
+	// Save the data in the snapshot
+	if len(config.Snapshot.Dir) > 0 {
+		if err := newSnapshots(config.Snapshot.Dir).saveOne(collection, c); err != nil {
+			return err
+		}
+		fmt.Printf("saved the %s collection to %s\n", collection, config.Snapshot.Dir)
+	}
+	return nil
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/cloud/cluster"
+	"github.com/realTristan/hermes/cloud/replication"
+	Resp "github.com/realTristan/hermes/cloud/resp"
+	Socket "github.com/realTristan/hermes/cloud/socket"
+	sutils "github.com/realTristan/hermes/cloud/socket/utils"
+	"google.golang.org/grpc/status"
+)
+
+// The upper bounds of the request latency buckets, in seconds
+var latencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// How often the size of the full-text indexes is measured. Measuring
+// the size encodes the whole index, so it's not done for every scrape
+const indexSizeInterval time.Duration = time.Minute
+
+// Histogram struct for the latencies of a route or socket function
+type histogram struct {
+	buckets []atomic.Uint64 // not cumulative, the last bucket is +Inf
+	sum     atomic.Int64    // nanoseconds
+}
+
+// Add a latency to the histogram
+func (h *histogram) observe(d time.Duration) {
+	var seconds float64 = d.Seconds()
+	var i int = sort.SearchFloat64s(latencyBuckets, seconds)
+	h.buckets[i].Add(1)
+	h.sum.Add(int64(d))
+}
+
+// Metrics struct for the counters of the server. The counters are
+// atomics, and the mutex only guards the creation of the counters
+// of a new route, function or outcome
+type metrics struct {
+	mutex       *sync.RWMutex
+	requests    map[[3]string]*atomic.Uint64 // transport, name, outcome
+	latencies   map[[2]string]*histogram     // transport, name
+	indexBytes  map[string]int
+	collections *hermes.Collections
+	socket      *Socket.Socket
+	leader      *replication.Leader
+	follower    *replication.Follower
+	node        *cluster.Node
+	start       time.Time
+}
+
+// Create the metrics of the collections
+func newMetrics(collections *hermes.Collections) *metrics {
+	return &metrics{
+		mutex:       &sync.RWMutex{},
+		requests:    make(map[[3]string]*atomic.Uint64),
+		latencies:   make(map[[2]string]*histogram),
+		indexBytes:  make(map[string]int),
+		collections: collections,
+		start:       time.Now(),
+	}
+}
+
+// Count a request and its latency
+func (m *metrics) observe(transport, name, outcome string, d time.Duration) {
+	var (
+		key     [3]string = [3]string{transport, name, outcome}
+		hkey    [2]string = [2]string{transport, name}
+		counter *atomic.Uint64
+		h       *histogram
+	)
+	m.mutex.RLock()
+	counter, h = m.requests[key], m.latencies[hkey]
+	m.mutex.RUnlock()
+
+	// Create the counters of a new route or outcome
+	if counter == nil || h == nil {
+		m.mutex.Lock()
+		if counter = m.requests[key]; counter == nil {
+			counter = &atomic.Uint64{}
+			m.requests[key] = counter
+		}
+		if h = m.latencies[hkey]; h == nil {
+			h = &histogram{buckets: make([]atomic.Uint64, len(latencyBuckets)+1)}
+			m.latencies[hkey] = h
+		}
+		m.mutex.Unlock()
+	}
+	counter.Add(1)
+	h.observe(d)
+}
+
+// Middleware that counts the http requests by route and status
+func (m *metrics) middleware(c *fiber.Ctx) error {
+	var start time.Time = time.Now()
+	var err error = c.Next()
+
+	// Get the status that the error handler will respond with
+	var status int = c.Response().StatusCode()
+	var e *fiber.Error
+	if errors.As(err, &e) {
+		status = e.Code
+	} else if err != nil {
+		status = fiber.StatusInternalServerError
+	}
+
+	// Use the route pattern, so that the paths of
+	// the requests that don't match a route aren't labels
+	var name string = c.Route().Path
+	if status == fiber.StatusNotFound && name == "/" && c.Path() != "/" {
+		name = "unmatched"
+	}
+	m.observe("http", name, strconv.Itoa(status), time.Since(start))
+	return err
+}
+
+// Count a socket function call by function and error code
+func (m *metrics) observeSocket(function string, d time.Duration, err error) {
+	var outcome string = "ok"
+	var e *sutils.Error
+	if errors.As(err, &e) {
+		outcome = e.Code
+	} else if err != nil {
+		outcome = sutils.CodeFailed
+	}
+	m.observe("ws", function, outcome, d)
+}
+
+// Count a gRPC call by method and status code
+func (m *metrics) observeGRPC(method string, d time.Duration, err error) {
+	m.observe("grpc", method, status.Code(err).String(), d)
+}
+
+// Count a redis command by command and error code
+func (m *metrics) observeRESP(command string, d time.Duration, err error) {
+	var outcome string = "ok"
+	var e *Resp.Error
+	if errors.As(err, &e) {
+		outcome = e.Code
+	}
+	m.observe("resp", command, outcome, d)
+}
+
+// Measure the size of the full-text indexes at every interval
+func (m *metrics) measureIndexes(interval time.Duration) {
+	for {
+		var sizes map[string]int = make(map[string]int)
+		for _, name := range m.collections.List() {
+			if c, err := m.collections.Get(name); err == nil {
+				if size, err := c.FTStorageSize(); err == nil {
+					sizes[name] = size
+				}
+			}
+		}
+		m.mutex.Lock()
+		m.indexBytes = sizes
+		m.mutex.Unlock()
+		time.Sleep(interval)
+	}
+}
+
+// Handler for the metrics endpoint, in the prometheus text format.
+// The stats of the caches are read without locking them
+func (m *metrics) handler(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	var b *strings.Builder = &strings.Builder{}
+	m.write(b)
+	return c.SendString(b.String())
+}
+
+// Write the metrics in the prometheus text format
+func (m *metrics) write(w io.Writer) {
+	m.writeRequests(w)
+	m.writeCollections(w)
+	if m.socket != nil {
+		header(w, "hermes_socket_connections", "gauge", "The number of open websocket connections.")
+		fmt.Fprintf(w, "hermes_socket_connections %d\n", m.socket.Connections())
+	}
+	m.writeReplication(w)
+	m.writeCluster(w)
+	header(w, "hermes_start_time_seconds", "gauge", "When the server started, in unix seconds.")
+	fmt.Fprintf(w, "hermes_start_time_seconds %d\n", m.start.Unix())
+}
+
+// Write the request counters and latency histograms
+func (m *metrics) writeRequests(w io.Writer) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Write the request counters
+	var keys [][3]string = make([][3]string, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.Join(keys[i][:], "\x00") < strings.Join(keys[j][:], "\x00")
+	})
+	header(w, "hermes_requests_total", "counter", "The number of http requests, socket function calls, grpc calls and redis commands, by route, function, method or command, and status or error code.")
+	for _, key := range keys {
+		fmt.Fprintf(w, "hermes_requests_total{transport=%s,name=%s,outcome=%s} %d\n",
+			quote(key[0]), quote(key[1]), quote(key[2]), m.requests[key].Load())
+	}
+
+	// Write the latency histograms
+	var hkeys [][2]string = make([][2]string, 0, len(m.latencies))
+	for key := range m.latencies {
+		hkeys = append(hkeys, key)
+	}
+	sort.Slice(hkeys, func(i, j int) bool {
+		return hkeys[i][0] < hkeys[j][0] || (hkeys[i][0] == hkeys[j][0] && hkeys[i][1] < hkeys[j][1])
+	})
+	header(w, "hermes_request_duration_seconds", "histogram", "The latency of the http requests, socket function calls, grpc calls and redis commands.")
+	for _, key := range hkeys {
+		var (
+			h      *histogram = m.latencies[key]
+			labels string     = fmt.Sprintf("transport=%s,name=%s", quote(key[0]), quote(key[1]))
+			total  uint64
+		)
+		for i := range h.buckets {
+			total += h.buckets[i].Load()
+			var le string = "+Inf"
+			if i < len(latencyBuckets) {
+				le = strconv.FormatFloat(latencyBuckets[i], 'g', -1, 64)
+			}
+			fmt.Fprintf(w, "hermes_request_duration_seconds_bucket{%s,le=%q} %d\n", labels, le, total)
+		}
+		fmt.Fprintf(w, "hermes_request_duration_seconds_sum{%s} %g\n", labels, time.Duration(h.sum.Load()).Seconds())
+		fmt.Fprintf(w, "hermes_request_duration_seconds_count{%s} %d\n", labels, total)
+	}
+}
+
+// Write the stats of the collections
+func (m *metrics) writeCollections(w io.Writer) {
+	var (
+		names []string = m.collections.List()
+		stats []hermes.Stats
+	)
+	for _, name := range names {
+		if c, err := m.collections.Get(name); err == nil {
+			stats = append(stats, c.Stats())
+		} else {
+			stats = append(stats, hermes.Stats{})
+		}
+	}
+
+	// Write a metric for each collection
+	var perCollection = func(metric, kind, help string, value func(hermes.Stats) string) {
+		header(w, metric, kind, help)
+		for i, name := range names {
+			fmt.Fprintf(w, "%s{collection=%s} %s\n", metric, quote(name), value(stats[i]))
+		}
+	}
+	perCollection("hermes_keys", "gauge", "The number of records in the collection.", func(s hermes.Stats) string {
+		return strconv.Itoa(s.Keys)
+	})
+	perCollection("hermes_ft_words", "gauge", "The number of words in the full-text index.", func(s hermes.Stats) string {
+		return strconv.Itoa(s.Words)
+	})
+	perCollection("hermes_searches_total", "counter", "The number of full-text and value searches.", func(s hermes.Stats) string {
+		return strconv.FormatUint(s.Searches, 10)
+	})
+	perCollection("hermes_search_hits_total", "counter", "The number of results returned by the searches.", func(s hermes.Stats) string {
+		return strconv.FormatUint(s.SearchHits, 10)
+	})
+	perCollection("hermes_lock_acquisitions_total", "counter", "The number of times the collection was locked.", func(s hermes.Stats) string {
+		return strconv.FormatUint(s.LockAcquisitions, 10)
+	})
+	perCollection("hermes_lock_wait_seconds_total", "counter", "The time spent waiting to lock the collection.", func(s hermes.Stats) string {
+		return strconv.FormatFloat(s.LockWait.Seconds(), 'g', -1, 64)
+	})
+	perCollection("hermes_evictions_total", "counter", "The number of records that were evicted. Always 0, the cache never evicts records.", func(s hermes.Stats) string {
+		return strconv.FormatUint(s.Evictions, 10)
+	})
+	perCollection("hermes_expirations_total", "counter", "The number of records that expired. Always 0, the records never expire.", func(s hermes.Stats) string {
+		return strconv.FormatUint(s.Expirations, 10)
+	})
+
+	// Write the measured index sizes
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	header(w, "hermes_ft_index_bytes", "gauge", "The size of the full-text index, measured every minute.")
+	for _, name := range names {
+		if size, ok := m.indexBytes[name]; ok {
+			fmt.Fprintf(w, "hermes_ft_index_bytes{collection=%s} %d\n", quote(name), size)
+		}
+	}
+}
+
+// Write the number of followers of the server, and the
+// replication lag of a follower
+func (m *metrics) writeReplication(w io.Writer) {
+	if m.leader != nil {
+		header(w, "hermes_replication_followers", "gauge", "The number of followers that the changes are streamed to.")
+		fmt.Fprintf(w, "hermes_replication_followers %d\n", m.leader.Followers())
+	}
+	if m.follower == nil {
+		return
+	}
+	var stats replication.Stats = m.follower.Stats()
+	var connected, synced int
+	if stats.Connected {
+		connected = 1
+	}
+	if stats.Synced {
+		synced = 1
+	}
+	header(w, "hermes_replication_connected", "gauge", "Whether the follower is connected to its leader.")
+	fmt.Fprintf(w, "hermes_replication_connected %d\n", connected)
+	header(w, "hermes_replication_synced", "gauge", "Whether the follower has received every collection of its leader.")
+	fmt.Fprintf(w, "hermes_replication_synced %d\n", synced)
+	if !stats.LastHeartbeat.IsZero() {
+		header(w, "hermes_replication_heartbeat_age_seconds", "gauge", "How long ago the last heartbeat of the leader was received.")
+		fmt.Fprintf(w, "hermes_replication_heartbeat_age_seconds %g\n", time.Since(stats.LastHeartbeat).Seconds())
+	}
+
+	// Write the lag of each collection, in changes
+	var names []string = make([]string, 0, len(stats.Lag))
+	for name := range stats.Lag {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	header(w, "hermes_replication_lag_changes", "gauge", "The number of changes of the collection that the leader has made and the follower hasn't applied, at the last heartbeat.")
+	for _, name := range names {
+		fmt.Fprintf(w, "hermes_replication_lag_changes{collection=%s} %d\n", quote(name), stats.Lag[name])
+	}
+	header(w, "hermes_replication_changes_total", "counter", "The number of changes of the leader that were applied.")
+	fmt.Fprintf(w, "hermes_replication_changes_total %d\n", stats.Changes)
+	header(w, "hermes_replication_snapshots_total", "counter", "The number of collection snapshots of the leader that were applied.")
+	fmt.Fprintf(w, "hermes_replication_snapshots_total %d\n", stats.Snapshots)
+	header(w, "hermes_replication_connects_total", "counter", "The number of times the follower connected to its leader.")
+	fmt.Fprintf(w, "hermes_replication_connects_total %d\n", stats.Connects)
+}
+
+// Write the state of the cluster node: whether it's the
+// leader, and how far its log is stored and applied
+func (m *metrics) writeCluster(w io.Writer) {
+	if m.node == nil {
+		return
+	}
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var state, _ = m.node.Status(ctx)
+	var leader int
+	if m.node.IsLeader() {
+		leader = 1
+	}
+	header(w, "hermes_cluster_leader", "gauge", "Whether the node is the leader of the cluster.")
+	fmt.Fprintf(w, "hermes_cluster_leader %d\n", leader)
+	header(w, "hermes_cluster_servers", "gauge", "The number of members of the cluster.")
+	fmt.Fprintf(w, "hermes_cluster_servers %d\n", len(state.Servers))
+	header(w, "hermes_cluster_term", "gauge", "The term of the current election.")
+	fmt.Fprintf(w, "hermes_cluster_term %d\n", state.Term)
+	header(w, "hermes_cluster_commit_index", "gauge", "The index of the last committed log entry.")
+	fmt.Fprintf(w, "hermes_cluster_commit_index %d\n", state.CommitIndex)
+	header(w, "hermes_cluster_applied_index", "gauge", "The index of the last log entry that was applied to the collections.")
+	fmt.Fprintf(w, "hermes_cluster_applied_index %d\n", state.AppliedIndex)
+	header(w, "hermes_cluster_snapshot_index", "gauge", "The index of the last log entry of the last snapshot.")
+	fmt.Fprintf(w, "hermes_cluster_snapshot_index %d\n", state.SnapshotIndex)
+}
+
+// Write the help and type lines of a metric
+func header(w io.Writer, metric, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", metric, help, metric, kind)
+}
+
+// Quote a label value, with the escapes of the prometheus text format
+func quote(value string) string {
+	var r *strings.Replacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(value) + `"`
+}
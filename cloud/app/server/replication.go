@@ -0,0 +1,27 @@
+package main
+
+import (
+	utils "hermes/utils"
+
+	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/cloud/replication"
+)
+
+// Create the follower of the leader in the replication settings.
+// The connection errors are logged, and the follower reconnects
+func newFollower(args utils.ReplicationArgs, collections *hermes.Collections) (*replication.Follower, error) {
+	var config replication.Config = replication.Config{
+		URL:   args.Leader,
+		Token: args.Token,
+		OnError: func(err error) {
+			utils.Logf(utils.LogWarn, "replication from %s: %v", args.Leader, err)
+		},
+	}
+
+	// Verify the certificate of the leader with the CAs of the file
+	var err error
+	if config.TLS, err = loadCA(args.CA); err != nil {
+		return nil, err
+	}
+	return replication.NewFollower(collections, config)
+}
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	utils "hermes/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/cloud/api"
+	"github.com/realTristan/hermes/cloud/auth"
+	"github.com/realTristan/hermes/cloud/cluster"
+	"github.com/realTristan/hermes/cloud/replication"
+	Resp "github.com/realTristan/hermes/cloud/resp"
+	Rpc "github.com/realTristan/hermes/cloud/rpc"
+	Socket "github.com/realTristan/hermes/cloud/socket"
+	sutils "github.com/realTristan/hermes/cloud/socket/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// How long the open connections are waited for at shutdown
+const shutdownTimeout time.Duration = 10 * time.Second
+
+// Serve the collections over the REST API, the websocket, gRPC and the redis protocol
+func serve(args []string) error {
+	var config, err = utils.ParseConfig("serve", args, os.Stderr, nil)
+	if err != nil {
+		return err
+	}
+	var level, _ = utils.ParseLogLevel(config.LogLevel)
+	utils.SetLogLevel(level)
+
+	// Create the authenticator. A follower rejects the writes, which are sent to its leader,
+	// and a cluster node only accepts the writes of the REST API, which go through the log
+	var socketConfig Socket.Config = Socket.DefaultConfig()
+	if socketConfig.Auth, err = authenticator(config.Auth); err != nil {
+		return err
+	}
+	socketConfig.ReadOnly = config.ReadOnly()
+
+	// Load the tls certificates
+	var tlsConfig *tls.Config
+	if tlsConfig, err = loadTLSConfig(config.TLS); err != nil {
+		return err
+	}
+
+	// Initialize the collections
+	var (
+		start       time.Time = time.Now()
+		collections *hermes.Collections
+		loaded      map[string]int
+	)
+	if collections, loaded, err = initCollections(config); err != nil {
+		return err
+	}
+
+	// Follow the leader in the background. The collections are replaced with its collections
+	var follower *replication.Follower
+	if config.Follows() {
+		if follower, err = newFollower(config.Replication, collections); err != nil {
+			return err
+		}
+	}
+
+	// Start the node of the cluster. The collections are filled from the log of the cluster
+	var (
+		node     *cluster.Node
+		handlers *cluster.Handlers
+	)
+	if config.Clustered() {
+		var caConfig *tls.Config
+		if caConfig, err = loadCA(config.Cluster.CA); err != nil {
+			return err
+		} else if node, err = newNode(config, collections, tlsConfig); err != nil {
+			return err
+		}
+		defer node.Shutdown()
+		handlers = cluster.NewHandlers(node, cluster.HandlersConfig{
+			Reads: cluster.Consistency(config.Cluster.Reads),
+			TLS:   caConfig,
+		})
+	}
+
+	// Initialize a new fiber app
+	var app *fiber.App = fiber.New(fiber.Config{
+		Prefork:               false,
+		ServerHeader:          "hermes",
+		DisableStartupMessage: true,
+	})
+	if utils.LogEnabled(utils.LogDebug) {
+		app.Use(logger.New())
+	}
+
+	// Count the requests and the socket function calls
+	var stats *metrics = newMetrics(collections)
+	stats.follower = follower
+	stats.node = node
+	app.Use(stats.middleware)
+	socketConfig.Observe = stats.observeSocket
+	go stats.measureIndexes(indexSizeInterval)
+
+	// Report that the server is alive, that the collections are loaded,
+	// and the metrics. The metrics require the read scope, like the data
+	var ready *readiness = newReadiness(loaded, time.Since(start))
+	if follower != nil {
+		ready.synced = follower.Synced
+	} else if node != nil {
+		ready.synced = func() bool {
+			var leader, _ = node.Leader()
+			return len(leader) > 0
+		}
+	}
+	app.Get("/healthz", healthz)
+	app.Get("/readyz", ready.handler)
+	app.Get("/metrics", auth.Require(socketConfig.Auth, auth.ScopeRead), stats.handler)
+
+	// Save the collections on demand and periodically
+	var snaps *snapshots
+	if len(config.Snapshot.Dir) > 0 {
+		snaps = newSnapshots(config.Snapshot.Dir)
+		Socket.CollectionFunctions["server.snapshot"] = func(_ *sutils.Params, cs *hermes.Collections) (any, error) {
+			return snaps.save(cs)
+		}
+		Socket.Scopes["server.snapshot"] = auth.ScopeAdmin
+		if config.Snapshot.Interval > 0 {
+			go saveEvery(snaps, collections, config.Snapshot.Interval)
+		}
+	}
+
+	// Stream the changes of the collections to the followers. A follower
+	// can be followed as well, since its changes are published like the writes
+	var leader *replication.Leader = replication.NewLeader(collections)
+	stats.leader = leader
+	app.Post(replication.Path, auth.Require(socketConfig.Auth, auth.ScopeAdmin), leader.Handler)
+
+	// Serve the membership changes and the status of the cluster
+	if handlers != nil {
+		handlers.SetRoutes(app, socketConfig.Auth)
+	}
+
+	// Serve the transports. The REST writes of a cluster node are applied through the log
+	if config.Serves("http") {
+		var apiConfig api.Config = api.Config{
+			Auth:     socketConfig.Auth,
+			ReadOnly: config.ReadOnly(),
+		}
+		if handlers != nil {
+			apiConfig.Writes, apiConfig.Reads = handlers.Writes, handlers.Reads()
+		}
+		api.SetRoutesWithConfig(app, collections, apiConfig)
+	}
+	if config.Serves("ws") {
+		stats.socket = Socket.SetRouterWithConfig(app, collections, socketConfig)
+	}
+
+	// Listen on the address
+	var addr string = net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	var ln net.Listener
+	if tlsConfig != nil {
+		ln, err = tls.Listen("tcp", addr, tlsConfig)
+	} else {
+		ln, err = net.Listen("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	utils.Logf(utils.LogInfo, "hermes %s listening on %s (%s)", version, ln.Addr(), config.Transport)
+
+	// Serve the gRPC services on their own port, with the same collections and authenticator
+	var grpcServer *grpc.Server
+	if config.Serves("grpc") {
+		if grpcServer, err = serveGRPC(config, collections, socketConfig.Auth, tlsConfig, stats); err != nil {
+			ln.Close()
+			return err
+		}
+	}
+
+	// Serve the redis protocol on its own port
+	var respServer *Resp.Server
+	if config.Serves("resp") {
+		if respServer, err = serveRESP(config, collections, socketConfig.Auth, tlsConfig, stats); err != nil {
+			ln.Close()
+			if grpcServer != nil {
+				grpcServer.Stop()
+			}
+			return err
+		}
+	}
+
+	// Follow the leader until the server is interrupted
+	var ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	if follower != nil {
+		utils.Logf(utils.LogInfo, "following %s", config.Replication.Leader)
+		go follower.Run(ctx)
+	}
+
+	// Join the cluster once the api is served, so that the leader can forward the requests to the node
+	if node != nil && len(config.Cluster.Join) > 0 {
+		go joinCluster(ctx, config, tlsConfig != nil)
+	}
+
+	// Serve until the server is interrupted
+	var served chan error = make(chan error, 1)
+	go func() {
+		served <- app.Listener(ln)
+	}()
+	var signals chan os.Signal = make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err = <-served:
+		return err
+	case sig := <-signals:
+		utils.Logf(utils.LogInfo, "received %s, shutting down", sig)
+		ready.stop()
+	}
+
+	// Stop following the leader and end the streams of the followers,
+	// then stop accepting requests and save the collections
+	cancel()
+	leader.Close()
+	if err := app.ShutdownWithTimeout(shutdownTimeout); err != nil {
+		utils.Logf(utils.LogWarn, "failed to close the connections: %v", err)
+	}
+	if grpcServer != nil {
+		stopGRPC(grpcServer, shutdownTimeout)
+	}
+	if respServer != nil {
+		respServer.Close()
+	}
+	if node != nil {
+		if err := node.Shutdown(); err != nil {
+			utils.Logf(utils.LogWarn, "failed to stop the cluster node: %v", err)
+		}
+	}
+	if snaps != nil {
+		if _, err := snaps.save(collections); err != nil {
+			return err
+		}
+		utils.Logf(utils.LogInfo, "saved the collections to %s", snaps.dir)
+	}
+	return nil
+}
+
+// Listen on the grpc port and serve the gRPC services in the background
+func serveGRPC(config *utils.Config, collections *hermes.Collections, authenticator auth.Authenticator, tlsConfig *tls.Config, stats *metrics) (*grpc.Server, error) {
+	var opts []grpc.ServerOption
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(withALPN(tlsConfig, "h2"))))
+	}
+	var server *grpc.Server = Rpc.NewServer(collections, Rpc.Config{
+		Auth:     authenticator,
+		ReadOnly: config.ReadOnly(),
+		Observe:  stats.observeGRPC,
+	}, opts...)
+
+	// The tls handshake is done by the credentials, so the listener is plain
+	var ln, err = net.Listen("tcp", net.JoinHostPort(config.Host, strconv.Itoa(config.GRPCPort)))
+	if err != nil {
+		return nil, err
+	}
+	utils.Logf(utils.LogInfo, "hermes %s listening on %s (grpc)", version, ln.Addr())
+	go func() {
+		if err := server.Serve(ln); err != nil {
+			utils.Logf(utils.LogError, "the grpc server stopped: %v", err)
+		}
+	}()
+	return server, nil
+}
+
+// Listen on the resp port and serve the redis protocol in the background
+func serveRESP(config *utils.Config, collections *hermes.Collections, authenticator auth.Authenticator, tlsConfig *tls.Config, stats *metrics) (*Resp.Server, error) {
+	var (
+		addr string = net.JoinHostPort(config.Host, strconv.Itoa(config.RESPPort))
+		ln   net.Listener
+		err  error
+	)
+	if tlsConfig != nil {
+		ln, err = tls.Listen("tcp", addr, tlsConfig)
+	} else {
+		ln, err = net.Listen("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var server *Resp.Server = Resp.NewServer(collections, Resp.Config{
+		Auth:     authenticator,
+		ReadOnly: config.ReadOnly(),
+		Observe:  stats.observeRESP,
+	})
+	utils.Logf(utils.LogInfo, "hermes %s listening on %s (resp)", version, ln.Addr())
+	go func() {
+		if err := server.Serve(ln); err != nil && err != Resp.ErrServerClosed {
+			utils.Logf(utils.LogError, "the resp server stopped: %v", err)
+		}
+	}()
+	return server, nil
+}
+
+// Stop the gRPC server once its calls end, or close them after the timeout.
+// The watch streams only end when they're closed, so they're always cut
+func stopGRPC(server *grpc.Server, timeout time.Duration) {
+	var stopped chan struct{} = make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		utils.Logf(utils.LogWarn, "the grpc calls did not end in %s, closing them", timeout)
+		server.Stop()
+	}
+}
+
+// Create the collections, and restore the snapshot or load the data files.
+// The number of records of each collection is returned for the readiness report
+func initCollections(config *utils.Config) (*hermes.Collections, map[string]int, error) {
+	var collections *hermes.Collections = hermes.InitCollections()
+
+	// A follower gets its collections from the leader, and a cluster node
+	// from the log of the cluster, so the snapshot isn't restored
+	if config.Follows() || config.Clustered() {
+		return collections, lengths(collections), nil
+	}
+
+	// Restore the snapshot
+	if len(config.Snapshot.Dir) > 0 {
+		if restored, err := newSnapshots(config.Snapshot.Dir).restore(collections); err != nil {
+			return nil, nil, err
+		} else if restored {
+			if len(config.Data) > 0 || len(config.Collections) > 0 {
+				utils.Logf(utils.LogInfo, "restored the snapshot of %s, the data files are not loaded", config.Snapshot.Dir)
+			}
+			return collections, lengths(collections), nil
+		}
+	}
+
+	// Initialize the default collection
+	var c, _ = collections.Get(hermes.DefaultCollection)
+	if err := initCollection(c, hermes.DefaultCollection, config.Data, config.FT); err != nil {
+		return nil, nil, err
+	}
+
+	// Create and initialize the other collections
+	for name, args := range config.Collections {
+		var ft utils.FTArgs = config.FT
+		if args.FT != nil {
+			ft = *args.FT
+		}
+		if name != hermes.DefaultCollection {
+			var err error
+			if c, err = collections.Create(name); err != nil {
+				return nil, nil, err
+			}
+		} else {
+			c, _ = collections.Get(name)
+		}
+		if err := initCollection(c, name, args.Data, ft); err != nil {
+			return nil, nil, fmt.Errorf("collection %s: %w", name, err)
+		}
+	}
+	return collections, lengths(collections), nil
+}
+
+// Load the data file into a collection, or only initialize
+// its full-text index if there's no data file
+func initCollection(c *hermes.Cache, name string, data string, ft utils.FTArgs) error {
+	if len(data) > 0 {
+		var start time.Time = time.Now()
+		if err := loadData(c, data, ft); err != nil {
+			return err
+		}
+		utils.Logf(utils.LogInfo, "loaded %d records from %s into the %s collection in %s", c.Length(), data, name, time.Since(start).Round(time.Millisecond))
+	} else if ft.Init {
+		return c.FTInit(ft.MaxSize, ft.MaxBytes, ft.MinWordLength)
+	}
+	return nil
+}
+
+// Get the number of records of each collection
+func lengths(collections *hermes.Collections) map[string]int {
+	var result map[string]int = make(map[string]int)
+	for _, name := range collections.List() {
+		if c, err := collections.Get(name); err == nil {
+			result[name] = c.Length()
+		}
+	}
+	return result
+}
+
+// Save the collections at every interval
+func saveEvery(snaps *snapshots, collections *hermes.Collections, interval time.Duration) {
+	var ticker *time.Ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, err := snaps.save(collections); err != nil {
+			utils.Logf(utils.LogError, "failed to save the collections: %v", err)
+		} else {
+			utils.Logf(utils.LogDebug, "saved the collections to %s", snaps.dir)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	utils "hermes/utils"
+
+	"github.com/fasthttp/websocket"
+	hermes "github.com/realTristan/hermes"
+)
+
+// The name of the file that lists the collections of a snapshot
+const manifestFile string = "manifest.json"
+
+// Manifest struct for the collections of a snapshot
+type manifest struct {
+	Time        time.Time                     `json:"time"`
+	Collections map[string]collectionManifest `json:"collections"`
+}
+
+// Collection manifest struct for the settings of a collection in a snapshot.
+// The records are in an ndjson file that can be loaded with cache.LoadNDJSON()
+type collectionManifest struct {
+	File     string         `json:"file"`
+	Keys     int            `json:"keys"`
+	FullText *ftSettings    `json:"full_text,omitempty"`
+	Schema   *hermes.Schema `json:"schema,omitempty"`
+}
+
+// Full-text settings struct for the limits of a full-text index
+type ftSettings struct {
+	MaxSize       int `json:"max_size"`
+	MaxBytes      int `json:"max_bytes"`
+	MinWordLength int `json:"min_word_length"`
+}
+
+// Snapshots struct for saving the collections to a directory. The
+// mutex prevents two snapshots from writing the same files at once
+type snapshots struct {
+	mutex *sync.Mutex
+	dir   string
+}
+
+// Create a snapshots struct for a directory
+func newSnapshots(dir string) *snapshots {
+	return &snapshots{
+		mutex: &sync.Mutex{},
+		dir:   dir,
+	}
+}
+
+// Save the collections to the snapshot directory. Each snapshot writes
+// its collections to new files, and only the manifest is replaced, so a
+// failed snapshot leaves the previous one intact
+func (s *snapshots) save(cs *hermes.Collections) (*manifest, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Save the collections
+	var m *manifest = &manifest{
+		Time:        time.Now().UTC(),
+		Collections: make(map[string]collectionManifest),
+	}
+	for _, name := range cs.List() {
+		var c, err = cs.Get(name)
+		if err != nil {
+			continue // dropped while saving
+		}
+		if m.Collections[name], err = s.saveCollection(name, c, m.Time); err != nil {
+			s.removeUnused(nil)
+			return nil, err
+		}
+	}
+
+	// Write the manifest, then remove the files of the previous
+	// snapshot and of the dropped collections
+	if err := s.commit(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Save a single collection to the snapshot directory, and keep the other
+// collections of the snapshot. Used by the load command
+func (s *snapshots) saveOne(name string, c *hermes.Cache) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Read the current manifest
+	var m, err = s.manifest()
+	if errors.Is(err, os.ErrNotExist) {
+		m = &manifest{Collections: make(map[string]collectionManifest)}
+	} else if err != nil {
+		return err
+	}
+
+	// Save the collection and update the manifest
+	m.Time = time.Now().UTC()
+	if m.Collections[name], err = s.saveCollection(name, c, m.Time); err != nil {
+		s.removeUnused(nil)
+		return err
+	}
+	return s.commit(m)
+}
+
+// Write the records of a collection to a new ndjson file, named
+// after the time of the snapshot
+func (s *snapshots) saveCollection(name string, c *hermes.Cache, t time.Time) (collectionManifest, error) {
+	var cm collectionManifest = collectionManifest{
+		File:   fmt.Sprintf("%s.%d.ndjson", url.PathEscape(name), t.UnixNano()),
+		Keys:   c.Length(),
+		Schema: c.GetSchema(),
+	}
+	if maxSize, maxBytes, minWordLength, err := c.FTSettings(); err == nil {
+		cm.FullText = &ftSettings{maxSize, maxBytes, minWordLength}
+	}
+
+	// Write the records
+	return cm, s.writeFile(cm.File, func(w *bufio.Writer) error {
+		return c.ExportJSON(w, hermes.ExportNDJSON)
+	})
+}
+
+// Replace the manifest, then remove the collection files that it doesn't use
+func (s *snapshots) commit(m *manifest) error {
+	if err := s.writeManifest(m); err != nil {
+		s.removeUnused(nil)
+		return err
+	}
+	s.removeUnused(m)
+	return nil
+}
+
+// Write the manifest file
+func (s *snapshots) writeManifest(m *manifest) error {
+	return s.writeFile(manifestFile, func(w *bufio.Writer) error {
+		var encoder *json.Encoder = json.NewEncoder(w)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(m)
+	})
+}
+
+// Write a file of the snapshot directory through a temporary file,
+// and replace the file once it has been written completely
+func (s *snapshots) writeFile(name string, write func(*bufio.Writer) error) error {
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return err
+	}
+	var f, err = os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	// Write the file
+	var w *bufio.Writer = bufio.NewWriter(f)
+	if err := write(w); err != nil {
+		f.Close()
+		return err
+	} else if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(s.dir, name))
+}
+
+// Remove the collection files that aren't in the manifest. If the
+// manifest is nil, the files of the current manifest are kept
+func (s *snapshots) removeUnused(m *manifest) {
+	if m == nil {
+		var err error
+		if m, err = s.manifest(); errors.Is(err, os.ErrNotExist) {
+			m = &manifest{}
+		} else if err != nil {
+			return
+		}
+	}
+	var used map[string]bool = make(map[string]bool)
+	for _, cm := range m.Collections {
+		used[cm.File] = true
+	}
+	var files, _ = filepath.Glob(filepath.Join(s.dir, "*.ndjson"))
+	for _, file := range files {
+		if !used[filepath.Base(file)] {
+			os.Remove(file)
+		}
+	}
+}
+
+// Read the manifest of the snapshot directory
+func (s *snapshots) manifest() (*manifest, error) {
+	var data, err = os.ReadFile(filepath.Join(s.dir, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var m *manifest = &manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("%s: %w", manifestFile, err)
+	}
+	return m, nil
+}
+
+// Restore the collections of the snapshot. Returns false if
+// the directory doesn't contain a snapshot yet
+func (s *snapshots) restore(cs *hermes.Collections) (bool, error) {
+	var m, err = s.manifest()
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	// Restore the collections
+	for name, cm := range m.Collections {
+		var c *hermes.Cache
+		if c, err = cs.Get(name); err != nil {
+			if c, err = cs.Create(name); err != nil {
+				return false, err
+			}
+		}
+		if err := s.restoreCollection(c, cm); err != nil {
+			return false, fmt.Errorf("collection %s: %w", name, err)
+		}
+		utils.Logf(utils.LogInfo, "restored %d records into the %s collection", c.Length(), name)
+	}
+	return true, nil
+}
+
+// Restore a collection from its ndjson file
+func (s *snapshots) restoreCollection(c *hermes.Cache, cm collectionManifest) error {
+	if cm.Schema != nil {
+		if err := c.SetSchema(cm.Schema); err != nil {
+			return err
+		}
+	}
+	if cm.FullText != nil {
+		if err := c.FTInit(cm.FullText.MaxSize, cm.FullText.MaxBytes, cm.FullText.MinWordLength); err != nil {
+			return err
+		}
+	}
+
+	// Load the records
+	var f, err = os.Open(filepath.Join(s.dir, filepath.Base(cm.File)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.LoadNDJSON(bufio.NewReader(f), nil)
+}
+
+// Ask a running server to save its collections to its snapshot directory.
+// The server is reached with the host, port and tls settings of the config
+func snapshot(args []string) error {
+	var token, clientCert, clientKey string
+	var config, err = utils.ParseConfig("snapshot", args, os.Stderr, func(flags *flag.FlagSet) {
+		flags.StringVar(&token, "token", "", "the api key or token of an admin client")
+		flags.StringVar(&clientCert, "client-cert", "", "the client certificate file, for servers that verify the clients")
+		flags.StringVar(&clientKey, "client-key", "", "the client key file, for servers that verify the clients")
+	})
+	if err != nil {
+		return err
+	}
+
+	// Get the url of the server
+	var host string = config.Host
+	if len(host) == 0 || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	var u url.URL = url.URL{
+		Scheme: "ws",
+		Host:   net.JoinHostPort(host, strconv.Itoa(config.Port)),
+		Path:   "/ws/hermes",
+	}
+
+	// Trust the certificate of the server, if it's self-signed
+	var dialer websocket.Dialer = *websocket.DefaultDialer
+	if len(config.TLS.Cert) > 0 {
+		u.Scheme = "wss"
+		dialer.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		if pool, err := x509.SystemCertPool(); err == nil {
+			dialer.TLSClientConfig.RootCAs = pool
+		} else {
+			dialer.TLSClientConfig.RootCAs = x509.NewCertPool()
+		}
+		if pem, err := os.ReadFile(config.TLS.Cert); err == nil {
+			dialer.TLSClientConfig.RootCAs.AppendCertsFromPEM(pem)
+		}
+		if len(clientCert) > 0 {
+			if cert, err := tls.LoadX509KeyPair(clientCert, clientKey); err != nil {
+				return err
+			} else {
+				dialer.TLSClientConfig.Certificates = []tls.Certificate{cert}
+			}
+		}
+	}
+
+	// Connect to the server
+	var header http.Header = http.Header{}
+	if len(token) > 0 {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	conn, resp, err := dialer.Dial(u.String(), header)
+	if err != nil {
+		if resp != nil {
+			return fmt.Errorf("%s: %s", u.String(), resp.Status)
+		}
+		return err
+	}
+	defer conn.Close()
+
+	// Call the snapshot function
+	if err := conn.WriteJSON(map[string]any{"v": 1, "id": 1, "function": "server.snapshot"}); err != nil {
+		return err
+	}
+	var reply struct {
+		OK     bool            `json:"ok"`
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	conn.SetReadDeadline(time.Now().Add(10 * time.Minute))
+	if err := conn.ReadJSON(&reply); err != nil {
+		return err
+	} else if !reply.OK && reply.Error != nil {
+		return fmt.Errorf("%s: %s", reply.Error.Code, reply.Error.Message)
+	}
+
+	// Print the saved collections
+	var m manifest
+	if err := json.Unmarshal(reply.Result, &m); err != nil {
+		return err
+	}
+	var names []string
+	for name := range m.Collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = fmt.Sprintf("%s (%d keys)", name, m.Collections[name].Keys)
+	}
+	fmt.Printf("saved %s at %s\n", strings.Join(names, ", "), m.Time.Format(time.RFC3339))
+	return nil
+}
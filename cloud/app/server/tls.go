@@ -0,0 +1,180 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	utils "hermes/utils"
+)
+
+// How often the certificate files are checked for changes
+const reloadInterval time.Duration = 5 * time.Second
+
+// Certificates struct for serving the certificate and client CAs
+// from the files, and reloading them when the files change
+type certificates struct {
+	mutex    *sync.Mutex
+	args     utils.TLSArgs
+	modTimes [3]time.Time
+	checked  time.Time
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+}
+
+// Load the certificates and create the tls config. Returns nil if
+// no certificate is configured, which serves plain http
+func loadTLSConfig(args utils.TLSArgs) (*tls.Config, error) {
+	if len(args.Cert) == 0 && len(args.Key) == 0 {
+		if len(args.ClientCA) > 0 {
+			return nil, errors.New("-tls-client-ca requires -tls-cert and -tls-key")
+		}
+		return nil, nil
+	} else if len(args.Cert) == 0 || len(args.Key) == 0 {
+		return nil, errors.New("both -tls-cert and -tls-key must be provided")
+	}
+
+	// Load the files
+	var c *certificates = &certificates{
+		mutex: &sync.Mutex{},
+		args:  args,
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	// Create the config. The certificate and client CAs are
+	// read for each handshake, so that the reloaded files are used
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			var cert, clientCA = c.get()
+			var config *tls.Config = &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+			}
+			if clientCA != nil {
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+				config.ClientCAs = clientCA
+			}
+			return config, nil
+		},
+	}, nil
+}
+
+// Copy a tls config for a server that negotiates its application protocols
+// with alpn, like grpc with h2. The protocols are set in the configs of the
+// handshakes as well, since they replace the copied config
+func withALPN(config *tls.Config, protos ...string) *tls.Config {
+	var c *tls.Config = config.Clone()
+	c.NextProtos = append(c.NextProtos, protos...)
+	if get := config.GetConfigForClient; get != nil {
+		c.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			var config, err = get(hello)
+			if config != nil {
+				config = config.Clone()
+				config.NextProtos = append(config.NextProtos, protos...)
+			}
+			return config, err
+		}
+	}
+	return c
+}
+
+// Get the current certificate and client CAs, and reload
+// them first if the files have changed
+func (c *certificates) get() (*tls.Certificate, *x509.CertPool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Check the files at most once per interval
+	if time.Since(c.checked) >= reloadInterval {
+		if c.changed() {
+			// Keep the previous certificates if the new files are invalid,
+			// for example if only one of them has been replaced yet
+			if err := c.load(); err != nil {
+				utils.Logf(utils.LogError, "failed to reload the tls certificates: %v", err)
+			} else {
+				utils.Logf(utils.LogInfo, "reloaded the tls certificates")
+			}
+		}
+		c.checked = time.Now()
+	}
+	return c.cert, c.clientCA
+}
+
+// Check whether the modification time of a file has changed
+func (c *certificates) changed() bool {
+	for i, file := range c.files() {
+		if len(file) == 0 {
+			continue
+		}
+		if info, err := os.Stat(file); err == nil && !info.ModTime().Equal(c.modTimes[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Load the certificate, key and client CA files
+func (c *certificates) load() error {
+	// Get the modification times before reading the files, so
+	// that a change made while reading is loaded at the next check
+	var modTimes [3]time.Time
+	for i, file := range c.files() {
+		if len(file) == 0 {
+			continue
+		}
+		if info, err := os.Stat(file); err != nil {
+			return err
+		} else {
+			modTimes[i] = info.ModTime()
+		}
+	}
+
+	// Load the certificate and the key
+	cert, err := tls.LoadX509KeyPair(c.args.Cert, c.args.Key)
+	if err != nil {
+		return err
+	}
+
+	// Load the client CAs
+	var clientCA *x509.CertPool
+	if len(c.args.ClientCA) > 0 {
+		if pem, err := os.ReadFile(c.args.ClientCA); err != nil {
+			return err
+		} else if clientCA = x509.NewCertPool(); !clientCA.AppendCertsFromPEM(pem) {
+			return errors.New("no certificates found in " + c.args.ClientCA)
+		}
+	}
+
+	// Set the certificates
+	c.cert, c.clientCA, c.modTimes = &cert, clientCA, modTimes
+	return nil
+}
+
+// The certificate files, in the order of the modification times
+func (c *certificates) files() [3]string {
+	return [3]string{c.args.Cert, c.args.Key, c.args.ClientCA}
+}
+
+// Create the tls config of the requests to the other servers, that verifies their
+// certificates with the CAs of the file. Returns nil for the system CAs if there's no file
+func loadCA(file string) (*tls.Config, error) {
+	if len(file) == 0 {
+		return nil, nil
+	}
+	var pem, err = os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+	var pool *x509.CertPool = x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no certificates found in " + file)
+	}
+	return &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: pool}, nil
+}
@@ -0,0 +1,402 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config struct for the server settings. The settings are read from the
+// config file, then from the environment variables, then from the flags
+type Config struct {
+	// The host and port to listen on. An empty host listens on all interfaces
+	Host string `yaml:"host" toml:"host"`
+	Port int    `yaml:"port" toml:"port"`
+	// The port of the gRPC services. HTTP/2 isn't served on the port of the REST API and the websocket
+	GRPCPort int `yaml:"grpc_port" toml:"grpc_port"`
+	// The port of the redis protocol
+	RESPPort int `yaml:"resp_port" toml:"resp_port"`
+	// The comma-separated transports that are served: http for the REST API, ws for the websocket,
+	// grpc for the gRPC services, and resp for the redis protocol
+	Transport string `yaml:"transport" toml:"transport"`
+	// The json or ndjson file that is loaded into the default collection at startup
+	Data string `yaml:"data" toml:"data"`
+	// The minimum level of the logs: debug, info, warn or error
+	LogLevel string       `yaml:"log_level" toml:"log_level"`
+	FT       FTArgs       `yaml:"ft" toml:"ft"`
+	Snapshot SnapshotArgs `yaml:"snapshot" toml:"snapshot"`
+	Auth     AuthArgs     `yaml:"auth" toml:"auth"`
+	TLS      TLSArgs      `yaml:"tls" toml:"tls"`
+	// The leader that the server follows. A follower serves the data of its leader, and rejects the writes
+	Replication ReplicationArgs `yaml:"replication" toml:"replication"`
+	// The raft cluster that the server is a node of. The writes of a node go through the log of the cluster
+	Cluster ClusterArgs `yaml:"cluster" toml:"cluster"`
+	// The collections that are created at startup, mapped by name. Only read from the config file
+	Collections map[string]CollectionArgs `yaml:"collections" toml:"collections"`
+}
+
+// CollectionArgs struct for a collection that is created at startup
+type CollectionArgs struct {
+	// The json or ndjson file that is loaded into the collection
+	Data string `yaml:"data" toml:"data"`
+	// The full-text settings of the collection. Defaults to the ft settings of the default collection
+	FT *FTArgs `yaml:"ft" toml:"ft"`
+}
+
+// FTArgs struct for the full-text settings of a collection
+type FTArgs struct {
+	// Whether the full-text index is initialized at startup. It's
+	// always initialized when a data file is loaded
+	Init bool `yaml:"init" toml:"init"`
+	// The limits of the full-text index. -1 for no limit
+	MaxSize       int `yaml:"max_size" toml:"max_size"`
+	MaxBytes      int `yaml:"max_bytes" toml:"max_bytes"`
+	MinWordLength int `yaml:"min_word_length" toml:"min_word_length"`
+}
+
+// SnapshotArgs struct for the persistence settings
+type SnapshotArgs struct {
+	// The directory that the collections are saved to, and restored from at startup
+	Dir string `yaml:"dir" toml:"dir"`
+	// How often the collections are saved. Zero to only save them at shutdown
+	Interval time.Duration `yaml:"interval" toml:"interval"`
+}
+
+// AuthArgs struct for the authentication settings
+type AuthArgs struct {
+	// The json file that maps the api keys to the identities
+	APIKeys string `yaml:"api_keys" toml:"api_keys"`
+	// The file that contains the secret of the hmac tokens
+	HMACSecret string `yaml:"hmac_secret" toml:"hmac_secret"`
+	// The key file that the jwts are verified with
+	JWTKey string `yaml:"jwt_key" toml:"jwt_key"`
+	// The issuer and audience that the jwts must have
+	JWTIssuer   string `yaml:"jwt_issuer" toml:"jwt_issuer"`
+	JWTAudience string `yaml:"jwt_audience" toml:"jwt_audience"`
+}
+
+// TLSArgs struct for the tls settings
+type TLSArgs struct {
+	// The certificate and key files. The files are reloaded when they change
+	Cert string `yaml:"cert" toml:"cert"`
+	Key  string `yaml:"key" toml:"key"`
+	// The file of the CAs that the client certificates must be signed by
+	ClientCA string `yaml:"client_ca" toml:"client_ca"`
+}
+
+// ReplicationArgs struct for the follower settings
+type ReplicationArgs struct {
+	// The url of the leader, like http://localhost:3000. Empty if the server isn't a follower
+	Leader string `yaml:"leader" toml:"leader"`
+	// The credential sent to the leader. It needs the admin scope if the leader has authentication
+	Token string `yaml:"token" toml:"token"`
+	// The file of the CAs that the certificate of the leader must be signed by. Defaults to the system CAs
+	CA string `yaml:"ca" toml:"ca"`
+}
+
+// ClusterArgs struct for the raft cluster settings
+type ClusterArgs struct {
+	// The unique id of the node. Empty if the server isn't a node of a cluster
+	ID string `yaml:"id" toml:"id"`
+	// The host:port that the node listens on for the other nodes
+	Address string `yaml:"address" toml:"address"`
+	// The host:port that the other nodes connect to, if it's not the address
+	Advertise string `yaml:"advertise" toml:"advertise"`
+	// The url of the api of the node, that the other nodes forward the requests to when it's
+	// the leader. Defaults to the host and port of the server, or localhost if the host is empty
+	API string `yaml:"api" toml:"api"`
+	// The directory of the log and the snapshots. Empty to keep them in memory
+	Dir string `yaml:"dir" toml:"dir"`
+	// Whether to start a new cluster with this node. Ignored once the node has a log
+	Bootstrap bool `yaml:"bootstrap" toml:"bootstrap"`
+	// The url of a node of the cluster to join, like http://10.0.0.1:3000
+	Join string `yaml:"join" toml:"join"`
+	// The credential sent to the node that is joined. It needs the admin scope if the node has authentication
+	Token string `yaml:"token" toml:"token"`
+	// The file of the CAs that the certificates of the other nodes must be signed by. Defaults to the system CAs
+	CA string `yaml:"ca" toml:"ca"`
+	// The consistency of the reads of the REST API: stale reads are served by every node,
+	// and leader reads are forwarded to the leader, which serves the latest writes
+	Reads string `yaml:"reads" toml:"reads"`
+}
+
+// UsageError is returned when the arguments of a command are invalid.
+// The error and the usage have already been printed
+type UsageError struct {
+	Err error
+}
+
+// Error returns the message of the usage error
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the flag parsing error
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// Get the default settings
+func DefaultConfig() *Config {
+	return &Config{
+		Port:      3000,
+		GRPCPort:  50051,
+		RESPPort:  6379,
+		Transport: "http,ws",
+		LogLevel:  "info",
+		Cluster: ClusterArgs{
+			Reads: "stale",
+		},
+		FT: FTArgs{
+			MaxSize:       -1,
+			MaxBytes:      -1,
+			MinWordLength: 3,
+		},
+	}
+}
+
+// Get the settings of a command from the config file, the environment
+// variables and the flags. The extra function can define the flags that
+// are specific to the command, and can be nil
+func ParseConfig(name string, args []string, output io.Writer, extra func(*flag.FlagSet)) (*Config, error) {
+	// Find the config file first, so that the
+	// environment variables and the flags override it
+	var file string = os.Getenv("HERMES_CONFIG")
+	var pre *flag.FlagSet = newFlagSet(name, DefaultConfig(), &file, extra)
+	pre.SetOutput(io.Discard)
+	_ = pre.Parse(args) // the errors are reported by the second parse
+
+	// Read the config file
+	var config *Config = DefaultConfig()
+	if len(file) > 0 {
+		if err := config.read(file); err != nil {
+			return nil, err
+		}
+	}
+
+	// Define the flags with the settings of the file as the defaults
+	var flags *flag.FlagSet = newFlagSet(name, config, &file, extra)
+	flags.SetOutput(output)
+
+	// Set the environment variables
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		if len(f.Name) == 1 || f.Name == "config" || err != nil {
+			return
+		}
+		if value, ok := os.LookupEnv(EnvName(f.Name)); ok {
+			if e := f.Value.Set(value); e != nil {
+				err = fmt.Errorf("invalid value %q for %s: %w", value, EnvName(f.Name), e)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the flags
+	if err := flags.Parse(args); err != nil {
+		return nil, &UsageError{err}
+	} else if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %s", flags.Arg(0))
+	}
+	return config, config.validate()
+}
+
+// Get the name of the environment variable of a flag. For
+// example, the -ft-max-size flag is read from HERMES_FT_MAX_SIZE
+func EnvName(flag string) string {
+	return "HERMES_" + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// Create a flag set that sets the fields of the config
+func newFlagSet(name string, config *Config, file *string, extra func(*flag.FlagSet)) *flag.FlagSet {
+	var flags *flag.FlagSet = flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: hermes %s [flags]\n\n", name)
+		fmt.Fprintln(flags.Output(), "The flags can also be set with HERMES_<FLAG> environment variables, or in the config file.")
+		fmt.Fprintln(flags.Output(), "For example, -ft-max-size is read from HERMES_FT_MAX_SIZE, or from max_size in the ft section.")
+		fmt.Fprintln(flags.Output())
+		flags.PrintDefaults()
+	}
+	flags.StringVar(file, "config", *file, "the yaml or toml config file")
+	flags.StringVar(&config.Host, "host", config.Host, "the host to listen on")
+	flags.IntVar(&config.Port, "port", config.Port, "the port to listen on")
+	flags.IntVar(&config.Port, "p", config.Port, "shorthand for -port")
+	flags.IntVar(&config.GRPCPort, "grpc-port", config.GRPCPort, "the port of the grpc services")
+	flags.IntVar(&config.RESPPort, "resp-port", config.RESPPort, "the port of the redis protocol")
+	flags.StringVar(&config.Transport, "transport", config.Transport, "the comma-separated transports to serve: http for the REST API, ws for the websocket, grpc for the grpc services, and resp for the redis protocol")
+	flags.StringVar(&config.Data, "data", config.Data, "the json or ndjson file to load into the default collection at startup")
+	flags.StringVar(&config.LogLevel, "log-level", config.LogLevel, "the minimum level of the logs: debug, info, warn or error")
+	flags.BoolVar(&config.FT.Init, "ft", config.FT.Init, "initialize the full-text index of the default collection at startup")
+	flags.IntVar(&config.FT.MaxSize, "ft-max-size", config.FT.MaxSize, "the maximum number of words in the full-text index")
+	flags.IntVar(&config.FT.MaxBytes, "ft-max-bytes", config.FT.MaxBytes, "the maximum size of the full-text index, in bytes")
+	flags.IntVar(&config.FT.MinWordLength, "min-word-length", config.FT.MinWordLength, "the minimum length of the words in the full-text index")
+	flags.StringVar(&config.Snapshot.Dir, "snapshot-dir", config.Snapshot.Dir, "the directory that the collections are saved to and restored from")
+	flags.DurationVar(&config.Snapshot.Interval, "snapshot-interval", config.Snapshot.Interval, "how often the collections are saved. zero to only save them at shutdown")
+	flags.StringVar(&config.Auth.APIKeys, "api-keys", config.Auth.APIKeys, "the json file of the api keys")
+	flags.StringVar(&config.Auth.HMACSecret, "hmac-secret", config.Auth.HMACSecret, "the file that contains the hmac token secret")
+	flags.StringVar(&config.Auth.JWTKey, "jwt-key", config.Auth.JWTKey, "the key file that the jwts are verified with")
+	flags.StringVar(&config.Auth.JWTIssuer, "jwt-issuer", config.Auth.JWTIssuer, "the issuer that the jwts must have")
+	flags.StringVar(&config.Auth.JWTAudience, "jwt-audience", config.Auth.JWTAudience, "the audience that the jwts must have")
+	flags.StringVar(&config.TLS.Cert, "tls-cert", config.TLS.Cert, "the tls certificate file")
+	flags.StringVar(&config.TLS.Key, "tls-key", config.TLS.Key, "the tls key file")
+	flags.StringVar(&config.TLS.ClientCA, "tls-client-ca", config.TLS.ClientCA, "the file of the CAs that the client certificates must be signed by")
+	flags.StringVar(&config.Replication.Leader, "replica-of", config.Replication.Leader, "the url of the leader to follow, like http://localhost:3000")
+	flags.StringVar(&config.Replication.Token, "replica-token", config.Replication.Token, "the credential sent to the leader")
+	flags.StringVar(&config.Replication.CA, "replica-ca", config.Replication.CA, "the file of the CAs that the certificate of the leader must be signed by")
+	flags.StringVar(&config.Cluster.ID, "cluster-id", config.Cluster.ID, "the unique id of the node in the raft cluster")
+	flags.StringVar(&config.Cluster.Address, "cluster-addr", config.Cluster.Address, "the host:port that the node listens on for the other nodes")
+	flags.StringVar(&config.Cluster.Advertise, "cluster-advertise", config.Cluster.Advertise, "the host:port that the other nodes connect to, if it's not the listening address")
+	flags.StringVar(&config.Cluster.API, "cluster-api", config.Cluster.API, "the url of the api of the node, that the requests are forwarded to when it's the leader")
+	flags.StringVar(&config.Cluster.Dir, "cluster-dir", config.Cluster.Dir, "the directory of the log and the snapshots of the node. empty to keep them in memory")
+	flags.BoolVar(&config.Cluster.Bootstrap, "cluster-bootstrap", config.Cluster.Bootstrap, "start a new cluster with this node")
+	flags.StringVar(&config.Cluster.Join, "cluster-join", config.Cluster.Join, "the url of a node of the cluster to join, like http://10.0.0.1:3000")
+	flags.StringVar(&config.Cluster.Token, "cluster-token", config.Cluster.Token, "the credential sent to the node that is joined")
+	flags.StringVar(&config.Cluster.CA, "cluster-ca", config.Cluster.CA, "the file of the CAs that the certificates of the other nodes must be signed by")
+	flags.StringVar(&config.Cluster.Reads, "cluster-reads", config.Cluster.Reads, "the consistency of the REST reads: stale or leader")
+	if extra != nil {
+		extra(flags)
+	}
+	return flags
+}
+
+// Read the settings from a yaml or toml config file
+func (config *Config) read(file string) error {
+	var data, err = os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return err
+	}
+
+	// Decode the file. The unknown settings are reported,
+	// so that a misspelled setting isn't silently ignored
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		var dec *yaml.Decoder = yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(config); err != nil && err != io.EOF {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	case ".toml":
+		if md, err := toml.Decode(string(data), config); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		} else if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("%s: unknown setting %s", file, undecoded[0])
+		}
+	default:
+		return fmt.Errorf("%s: the config file must be a .yaml, .yml or .toml file", file)
+	}
+	return nil
+}
+
+// Get whether a transport is served
+func (config *Config) Serves(transport string) bool {
+	for _, t := range strings.Split(config.Transport, ",") {
+		if strings.TrimSpace(t) == transport {
+			return true
+		}
+	}
+	return false
+}
+
+// Get whether the server follows a leader
+func (config *Config) Follows() bool {
+	return len(config.Replication.Leader) > 0
+}
+
+// Get whether the transports reject the writes. A follower sends its writes to the
+// leader, and a cluster node only applies the writes of the REST API through its log
+func (config *Config) ReadOnly() bool {
+	return config.Follows() || config.Clustered()
+}
+
+// Get whether the server is a node of a cluster
+func (config *Config) Clustered() bool {
+	return len(config.Cluster.ID) > 0
+}
+
+// Verify the settings
+func (config *Config) validate() error {
+	if config.Port < 0 || config.Port > 65535 {
+		return fmt.Errorf("invalid port %d", config.Port)
+	} else if config.Serves("grpc") && (config.GRPCPort < 0 || config.GRPCPort > 65535) {
+		return fmt.Errorf("invalid grpc port %d", config.GRPCPort)
+	} else if config.Serves("grpc") && config.GRPCPort == config.Port && config.Port != 0 {
+		return fmt.Errorf("the grpc port %d is also the http port", config.GRPCPort)
+	} else if config.Serves("resp") && (config.RESPPort < 0 || config.RESPPort > 65535) {
+		return fmt.Errorf("invalid resp port %d", config.RESPPort)
+	} else if config.Serves("resp") && config.RESPPort != 0 && (config.RESPPort == config.Port || (config.Serves("grpc") && config.RESPPort == config.GRPCPort)) {
+		return fmt.Errorf("the resp port %d is also the http or grpc port", config.RESPPort)
+	}
+	if _, err := ParseLogLevel(config.LogLevel); err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(config.Transport)) == 0 {
+		return errors.New("no transport to serve")
+	}
+	for _, t := range strings.Split(config.Transport, ",") {
+		if t = strings.TrimSpace(t); t != "http" && t != "ws" && t != "grpc" && t != "resp" {
+			return fmt.Errorf("invalid transport %q, expected http, ws, grpc or resp", t)
+		}
+	}
+	if config.FT.MinWordLength < 0 {
+		return errors.New("the minimum word length can't be negative")
+	}
+	for name, c := range config.Collections {
+		if len(name) == 0 {
+			return errors.New("invalid collection name")
+		} else if name == "default" && (len(config.Data) > 0 || config.FT.Init) {
+			return errors.New("the default collection is set with both the data and ft settings, and the collections section")
+		} else if c.FT != nil && c.FT.MinWordLength < 0 {
+			return fmt.Errorf("collection %s: the minimum word length can't be negative", name)
+		}
+	}
+	if len(config.Replication.Leader) == 0 && (len(config.Replication.Token) > 0 || len(config.Replication.CA) > 0) {
+		return errors.New("the replication token and ca require a leader")
+	} else if config.Follows() && (len(config.Data) > 0 || len(config.Collections) > 0) {
+		return errors.New("a follower gets its data from the leader, it can't load data files")
+	}
+	if err := config.validateCluster(); err != nil {
+		return err
+	}
+	if config.Snapshot.Interval < 0 {
+		return errors.New("the snapshot interval can't be negative")
+	} else if config.Snapshot.Interval > 0 && len(config.Snapshot.Dir) == 0 {
+		return errors.New("the snapshot interval requires a snapshot directory")
+	}
+	return nil
+}
+
+// Verify the cluster settings. A node gets its data from the log of
+// the cluster, so it can't load data files or restore snapshots
+func (config *Config) validateCluster() error {
+	var c ClusterArgs = config.Cluster
+	if !config.Clustered() {
+		if len(c.Address) > 0 || len(c.Advertise) > 0 || len(c.API) > 0 || len(c.Dir) > 0 || c.Bootstrap || len(c.Join) > 0 || len(c.Token) > 0 || len(c.CA) > 0 {
+			return errors.New("the cluster settings require a node id")
+		}
+		return nil
+	}
+	switch {
+	case len(c.Address) == 0:
+		return errors.New("a cluster node requires an address")
+	case c.Bootstrap && len(c.Join) > 0:
+		return errors.New("a cluster node either bootstraps a cluster or joins one")
+	case c.Reads != "stale" && c.Reads != "leader":
+		return fmt.Errorf("invalid cluster reads %q, expected stale or leader", c.Reads)
+	case config.Follows():
+		return errors.New("a cluster node can't follow a leader")
+	case len(config.Data) > 0 || len(config.Collections) > 0 || config.FT.Init || len(config.Snapshot.Dir) > 0:
+		return errors.New("a cluster node gets its data from the log of the cluster, it can't load data files or snapshots")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// ImportArgs struct
+type ImportArgs struct {
+	Input       string
+	Output      string
+	Collection  string
+	SnapshotDir string
+	FT          FTArgs
+	Mapping     hermes.CSVMapping
+}
+
+// Get the import subcommand arguments
+func GetImportArgs(args []string, output io.Writer) (*ImportArgs, error) {
+	var (
+		data  *ImportArgs   = &ImportArgs{FT: DefaultConfig().FT}
+		flags *flag.FlagSet = flag.NewFlagSet("import", flag.ContinueOnError)
+		ft    string
+		types string
+		tsv   bool
+	)
+	flags.SetOutput(output)
+	flags.StringVar(&data.Input, "i", "", "the csv file to import")
+	flags.StringVar(&data.Output, "o", "", "the json data file to write the imported records to")
+	flags.StringVar(&data.Collection, "collection", hermes.DefaultCollection, "the collection to save the records as, in the snapshot directory")
+	flags.StringVar(&data.SnapshotDir, "snapshot-dir", "", "the directory to save the collection to")
+	flags.IntVar(&data.FT.MaxSize, "ft-max-size", data.FT.MaxSize, "the maximum number of words in the full-text index")
+	flags.IntVar(&data.FT.MaxBytes, "ft-max-bytes", data.FT.MaxBytes, "the maximum size of the full-text index, in bytes")
+	flags.IntVar(&data.FT.MinWordLength, "min-word-length", data.FT.MinWordLength, "the minimum length of the words in the full-text index")
+	flags.StringVar(&data.Mapping.Key, "key", "", "the column that contains the record keys")
+	flags.StringVar(&ft, "ft", "", "comma separated columns to store in the full-text index")
+	flags.StringVar(&types, "types", "", "comma separated column types. example: age:int,price:float,active:bool,created:date")
+	flags.StringVar(&data.Mapping.DateLayout, "date-layout", "2006-01-02", "the layout used to parse the date columns")
+	flags.BoolVar(&tsv, "tsv", false, "whether the file is tab separated")
+
+	// Parse the flags
+	if err := flags.Parse(args); err != nil {
+		return nil, &UsageError{Err: err}
+	}
+	if len(data.Input) == 0 {
+		return nil, errors.New("no input file provided")
+	}
+	if len(data.Mapping.Key) == 0 {
+		return nil, errors.New("no key column provided")
+	}
+
+	// Set the full-text columns
+	if len(ft) > 0 {
+		data.Mapping.FullText = strings.Split(ft, ",")
+	}
+
+	// Set the column types
+	data.Mapping.Types = make(map[string]hermes.ColumnType)
+	if len(types) > 0 {
+		for _, t := range strings.Split(types, ",") {
+			var column, columnType, ok = strings.Cut(t, ":")
+			if !ok {
+				return nil, errors.New("invalid column type " + t)
+			}
+			data.Mapping.Types[column] = hermes.ColumnType(columnType)
+		}
+	}
+
+	// Set the delimiter
+	if tsv {
+		data.Mapping.Comma = '\t'
+	}
+	return data, nil
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+// LogLevel type for the levels of the server logs
+type LogLevel int32
+
+// The log levels, from the most to the least verbose
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+// The names of the log levels
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+// The minimum level of the logs that are written
+var minLogLevel atomic.Int32
+
+// Write the info logs by default
+func init() {
+	minLogLevel.Store(int32(LogInfo))
+}
+
+// Get the log level with a name
+func ParseLogLevel(name string) (LogLevel, error) {
+	for i, level := range logLevels {
+		if strings.EqualFold(name, level) {
+			return LogLevel(i), nil
+		}
+	}
+	return LogInfo, fmt.Errorf("invalid log level %s. expected one of %s", name, strings.Join(logLevels, ", "))
+}
+
+// Set the minimum level of the logs that are written
+func SetLogLevel(level LogLevel) {
+	minLogLevel.Store(int32(level))
+}
+
+// Check whether the logs of a level are written
+func LogEnabled(level LogLevel) bool {
+	return int32(level) >= minLogLevel.Load()
+}
+
+// Write a log if its level is enabled
+func Logf(level LogLevel, format string, v ...any) {
+	if LogEnabled(level) {
+		log.Printf(strings.ToUpper(logLevels[level])+" "+format, v...)
+	}
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// APIKeys is an authenticator that accepts a fixed set of keys.
+// The keys are compared in constant time.
+type APIKeys struct {
+	keys map[[sha256.Size]byte]*Identity
+}
+
+// NewAPIKeys is a function that creates an API key authenticator.
+//
+// Parameters:
+//   - keys (map[string]Identity): The identities, mapped by key.
+//
+// Returns:
+//   - *APIKeys: A pointer to the authenticator.
+//   - error: If a key is empty, or an identity has an invalid scope.
+func NewAPIKeys(keys map[string]Identity) (*APIKeys, error) {
+	var a *APIKeys = &APIKeys{keys: make(map[[sha256.Size]byte]*Identity, len(keys))}
+	for key, id := range keys {
+		if len(key) == 0 {
+			return nil, fmt.Errorf("empty api key for %s", id.Subject)
+		} else if id.Scope.rank() == 0 {
+			return nil, fmt.Errorf("api key for %s: invalid scope %q", id.Subject, id.Scope)
+		}
+		var identity Identity = id
+		a.keys[sha256.Sum256([]byte(key))] = &identity
+	}
+	return a, nil
+}
+
+// LoadAPIKeys is a function that creates an API key authenticator from a json file.
+// The file maps the keys to the identities: {"key": {"sub": "dashboard", "scope": "read"}}.
+//
+// Parameters:
+//   - file (string): The path to the json file.
+//
+// Returns:
+//   - *APIKeys: A pointer to the authenticator.
+//   - error: If the file can't be read or is invalid.
+func LoadAPIKeys(file string) (*APIKeys, error) {
+	var data, err = os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+	var keys map[string]Identity
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return NewAPIKeys(keys)
+}
+
+// Authenticate is a method of the APIKeys struct that verifies an API key.
+// The key is hashed before the lookup, and the hashes are compared in constant time,
+// so that the comparison doesn't leak the keys.
+//
+// Parameters:
+//   - credential (string): The API key.
+//
+// Returns:
+//   - *Identity: A copy of the identity of the key.
+//   - error: ErrUnauthorized if the key is unknown.
+func (a *APIKeys) Authenticate(credential string) (*Identity, error) {
+	var (
+		hash  [sha256.Size]byte = sha256.Sum256([]byte(credential))
+		found *Identity
+	)
+	for k, id := range a.keys {
+		if subtle.ConstantTimeCompare(k[:], hash[:]) == 1 {
+			found = id
+		}
+	}
+	if found == nil {
+		return nil, ErrUnauthorized
+	}
+	var id Identity = *found
+	return &id, nil
+}
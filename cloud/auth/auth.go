@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Scope is the level of access of an identity. Each scope includes the scopes below it:
+// read < write < admin.
+type Scope string
+
+// The authorization scopes.
+const (
+	// ScopeRead allows reading and searching the cache
+	ScopeRead Scope = "read"
+	// ScopeWrite allows setting and deleting records
+	ScopeWrite Scope = "write"
+	// ScopeAdmin allows cleaning the cache, changing the full-text settings and managing the collections
+	ScopeAdmin Scope = "admin"
+)
+
+// ErrUnauthorized is returned when no credentials are provided, or they are invalid.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrReadOnly is returned when a write is sent to a read-only server, like a replication follower.
+var ErrReadOnly = errors.New("the server is read-only, the writes must be sent to the leader")
+
+// rank is a method of the Scope type that returns the level of the scope.
+//
+// Returns:
+//   - int: The level of the scope, or 0 if the scope is invalid.
+func (s Scope) rank() int {
+	switch s {
+	case ScopeRead:
+		return 1
+	case ScopeWrite:
+		return 2
+	case ScopeAdmin:
+		return 3
+	}
+	return 0
+}
+
+// Allows is a method of the Scope type that returns whether the scope includes another scope.
+//
+// Parameters:
+//   - required (Scope): The scope required by a route or function.
+//
+// Returns:
+//   - bool: Whether the scope is at least the required scope.
+func (s Scope) Allows(required Scope) bool {
+	return s.rank() > 0 && s.rank() >= required.rank()
+}
+
+// Writes is a method of the Scope type that returns whether the scope changes the data,
+// so that read-only servers can reject the routes and functions that require it.
+//
+// Returns:
+//   - bool: Whether the scope is the write or admin scope.
+func (s Scope) Writes() bool {
+	return s.rank() >= ScopeWrite.rank()
+}
+
+// ParseScope is a function that parses a scope. Space separated lists of scopes are accepted,
+// as they're used in the "scope" claim of JWTs, and the highest scope is returned.
+//
+// Parameters:
+//   - s (string): The scope, or a space separated list of scopes.
+//
+// Returns:
+//   - Scope: The highest scope.
+//   - error: If the string doesn't contain a valid scope.
+func ParseScope(s string) (Scope, error) {
+	var scope Scope
+	for _, f := range strings.Fields(s) {
+		if v := Scope(f); v.rank() > scope.rank() {
+			scope = v
+		}
+	}
+	if scope.rank() == 0 {
+		return "", fmt.Errorf("invalid scope %q", s)
+	}
+	return scope, nil
+}
+
+// Identity is a struct that represents an authenticated client.
+type Identity struct {
+	// The name of the client
+	Subject string `json:"sub"`
+	// The level of access of the client
+	Scope Scope `json:"scope"`
+}
+
+// Authenticator is the interface of the credential verifiers.
+type Authenticator interface {
+	// Authenticate verifies a credential and returns the identity it belongs to.
+	// ErrUnauthorized is returned if the credential is invalid.
+	Authenticate(credential string) (*Identity, error)
+}
+
+// chain is a list of authenticators that are tried in order.
+type chain []Authenticator
+
+// Chain is a function that combines several authenticators. A credential is accepted
+// if any of the authenticators accepts it, so that API keys and tokens can be used side by side.
+//
+// Parameters:
+//   - auths (...Authenticator): The authenticators. Nil authenticators are skipped.
+//
+// Returns:
+//   - Authenticator: The combined authenticator, or nil if no authenticator is provided.
+func Chain(auths ...Authenticator) Authenticator {
+	var c chain
+	for _, a := range auths {
+		if a != nil {
+			c = append(c, a)
+		}
+	}
+	if len(c) == 0 {
+		return nil
+	}
+	return c
+}
+
+// Authenticate is a method of the chain type that tries each authenticator in order.
+//
+// Parameters:
+//   - credential (string): The credential.
+//
+// Returns:
+//   - *Identity: The identity of the first authenticator that accepts the credential.
+//   - error: ErrUnauthorized if no authenticator accepts the credential.
+func (c chain) Authenticate(credential string) (*Identity, error) {
+	for _, a := range c {
+		if id, err := a.Authenticate(credential); err == nil {
+			return id, nil
+		}
+	}
+	return nil, ErrUnauthorized
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// identityKey is the key of the authenticated identity in the fiber locals.
+const identityKey string = "hermes.identity"
+
+// Credential is a function that returns the credential of a request. It's read from the
+// "Authorization: Bearer ..." header, the "X-API-Key" header, or the "token" query parameter,
+// since browsers can't set headers on websocket connections.
+//
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a fiber context.
+//
+// Returns:
+//   - string: The credential, or an empty string if none is provided.
+func Credential(ctx *fiber.Ctx) string {
+	if h := ctx.Get(fiber.HeaderAuthorization); len(h) > 7 && strings.EqualFold(h[:7], "bearer ") {
+		return strings.TrimSpace(h[7:])
+	} else if key := ctx.Get("X-API-Key"); len(key) > 0 {
+		return key
+	}
+	return ctx.Query("token")
+}
+
+// Authenticate is a function that authenticates a request, and stores the identity in the fiber locals.
+//
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a fiber context.
+//   - a (Authenticator): The authenticator.
+//
+// Returns:
+//   - *Identity: The identity of the request.
+//   - error: ErrUnauthorized if no credential is provided, or it's invalid.
+func Authenticate(ctx *fiber.Ctx, a Authenticator) (*Identity, error) {
+	if id, ok := ctx.Locals(identityKey).(*Identity); ok {
+		return id, nil
+	}
+	var credential string = Credential(ctx)
+	if len(credential) == 0 {
+		return nil, ErrUnauthorized
+	}
+	var id, err = a.Authenticate(credential)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	ctx.Locals(identityKey, id)
+	return id, nil
+}
+
+// Require is a function that returns a fiber handler that only lets the requests with a scope through.
+// Unauthenticated requests get a 401 response, and requests with a lower scope get a 403 response.
+// If the authenticator is nil, authentication is disabled and every request is let through.
+//
+// Parameters:
+//   - a (Authenticator): The authenticator. Can be nil.
+//   - scope (Scope): The scope required by the route.
+//
+// Returns:
+//   - fiber.Handler: The handler.
+func Require(a Authenticator, scope Scope) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if a == nil {
+			return ctx.Next()
+		}
+		if id, err := Authenticate(ctx, a); err != nil {
+			ctx.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"success": false, "error": err.Error()})
+		} else if !id.Scope.Allows(scope) {
+			return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{"success": false, "error": "the " + string(scope) + " scope is required"})
+		}
+		return ctx.Next()
+	}
+}
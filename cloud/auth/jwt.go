@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// JWTOptions is a struct that contains the claims that the JWTs are verified against.
+type JWTOptions struct {
+	// If set, the "iss" claim must be equal to it
+	Issuer string
+	// If set, the "aud" claim must contain it
+	Audience string
+	// The clock skew allowed when verifying the "exp" and "nbf" claims
+	Leeway time.Duration
+}
+
+// JWT is an authenticator that accepts JSON Web Tokens, verified with a local key.
+// HS256 tokens are verified with a shared secret, RS256 tokens with an RSA public key,
+// and ES256 tokens with a P-256 ECDSA public key. The "sub" claim is the subject of the identity,
+// and the "scope" claim is a space separated list of scopes, of which the highest is used.
+type JWT struct {
+	alg     string
+	key     any
+	options JWTOptions
+	now     func() time.Time
+}
+
+// jwtClaims is the struct of the verified JWT claims.
+type jwtClaims struct {
+	Subject   string   `json:"sub"`
+	Scope     string   `json:"scope"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	Expires   *int64   `json:"exp"`
+	NotBefore *int64   `json:"nbf"`
+}
+
+// audience is the "aud" claim, which is either a string or a list of strings.
+type audience []string
+
+// UnmarshalJSON is a method of the audience type that decodes a string or a list of strings.
+//
+// Parameters:
+//   - data ([]byte): The json value.
+//
+// Returns:
+//   - error: If the value is neither a string nor a list of strings.
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+// NewJWT is a function that creates a JWT authenticator with a key.
+//
+// Parameters:
+//   - key (any): A []byte secret for HS256, an *rsa.PublicKey for RS256, or an *ecdsa.PublicKey on the P-256 curve for ES256.
+//   - options (JWTOptions): The claims that the tokens are verified against.
+//
+// Returns:
+//   - *JWT: A pointer to the authenticator.
+//   - error: If the key type isn't supported.
+func NewJWT(key any, options JWTOptions) (*JWT, error) {
+	var j *JWT = &JWT{key: key, options: options, now: time.Now}
+	switch k := key.(type) {
+	case []byte:
+		if len(k) < 32 {
+			return nil, errors.New("the HS256 secret must be at least 32 bytes")
+		}
+		j.alg = "HS256"
+	case *rsa.PublicKey:
+		j.alg = "RS256"
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, errors.New("only P-256 ECDSA keys are supported")
+		}
+		j.alg = "ES256"
+	default:
+		return nil, fmt.Errorf("unsupported jwt key type %T", key)
+	}
+	return j, nil
+}
+
+// LoadJWT is a function that creates a JWT authenticator with a key read from a file.
+// PEM files with a public key ("PUBLIC KEY" or "RSA PUBLIC KEY") or a certificate are used for RS256 and ES256.
+// Any other file is used as the HS256 secret, without its surrounding whitespace.
+//
+// Parameters:
+//   - file (string): The path to the key file.
+//   - options (JWTOptions): The claims that the tokens are verified against.
+//
+// Returns:
+//   - *JWT: A pointer to the authenticator.
+//   - error: If the file can't be read, or the key is invalid.
+func LoadJWT(file string, options JWTOptions) (*JWT, error) {
+	var data, err = os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the file as a secret if it isn't a PEM file
+	var block, _ = pem.Decode(data)
+	if block == nil {
+		return NewJWT([]byte(strings.TrimSpace(string(data))), options)
+	}
+
+	// Parse the public key
+	var key any
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported pem block %s", file, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return NewJWT(key, options)
+}
+
+// Authenticate is a method of the JWT struct that verifies a token.
+// The "alg" header must match the key, so that a public key can't be used as an HMAC secret.
+//
+// Parameters:
+//   - credential (string): The token.
+//
+// Returns:
+//   - *Identity: The identity of the token.
+//   - error: ErrUnauthorized if the token is malformed, the signature is invalid, or a claim doesn't match.
+func (j *JWT) Authenticate(credential string) (*Identity, error) {
+	var parts []string = strings.Split(credential, ".")
+	if len(parts) != 3 {
+		return nil, ErrUnauthorized
+	}
+
+	// Verify the header
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != j.alg {
+		return nil, ErrUnauthorized
+	}
+
+	// Verify the signature
+	var sig, err = base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !j.verify(parts[0]+"."+parts[1], sig) {
+		return nil, ErrUnauthorized
+	}
+
+	// Verify the claims
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrUnauthorized
+	} else if err := j.validate(claims); err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	// Get the scope
+	var scope, serr = ParseScope(claims.Scope)
+	if serr != nil {
+		return nil, ErrUnauthorized
+	}
+	return &Identity{Subject: claims.Subject, Scope: scope}, nil
+}
+
+// verify is a method of the JWT struct that verifies the signature of a token.
+//
+// Parameters:
+//   - signed (string): The header and payload segments of the token.
+//   - sig ([]byte): The decoded signature.
+//
+// Returns:
+//   - bool: Whether the signature is valid.
+func (j *JWT) verify(signed string, sig []byte) bool {
+	var digest [sha256.Size]byte = sha256.Sum256([]byte(signed))
+	switch k := j.key.(type) {
+	case []byte:
+		var mac = hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		return hmac.Equal(sig, mac.Sum(nil))
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	case *ecdsa.PublicKey:
+		if len(sig) != 64 {
+			return false
+		}
+		var r, s = new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(k, digest[:], r, s)
+	}
+	return false
+}
+
+// validate is a method of the JWT struct that verifies the registered claims of a token.
+//
+// Parameters:
+//   - claims (jwtClaims): The claims.
+//
+// Returns:
+//   - error: If the token expired, isn't valid yet, or has the wrong issuer or audience.
+func (j *JWT) validate(claims jwtClaims) error {
+	var now time.Time = j.now()
+	if claims.Expires != nil && !now.Before(time.Unix(*claims.Expires, 0).Add(j.options.Leeway)) {
+		return errors.New("token expired")
+	}
+	if claims.NotBefore != nil && now.Add(j.options.Leeway).Before(time.Unix(*claims.NotBefore, 0)) {
+		return errors.New("token not valid yet")
+	}
+	if len(j.options.Issuer) > 0 && claims.Issuer != j.options.Issuer {
+		return errors.New("invalid issuer")
+	}
+	if len(j.options.Audience) > 0 {
+		for _, aud := range claims.Audience {
+			if aud == j.options.Audience {
+				return nil
+			}
+		}
+		return errors.New("invalid audience")
+	}
+	return nil
+}
+
+// decodeSegment is a function that decodes a base64url encoded json segment of a token.
+//
+// Parameters:
+//   - segment (string): The segment.
+//   - v (any): A pointer to the value to decode the segment into.
+//
+// Returns:
+//   - error: If the segment isn't valid base64url encoded json.
+func decodeSegment(segment string, v any) error {
+	var data, err = base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,141 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// Op is the operation of a command of the log.
+type Op string
+
+// The operations of the commands. Each write to the collections is a command, that every node applies in the same order.
+const (
+	// Set a record. The key must not exist
+	OpSet Op = "set"
+	// Set a record, whether the key exists or not
+	OpReplace Op = "replace"
+	// Delete a record
+	OpDelete Op = "delete"
+	// Delete every record of the collection
+	OpClean Op = "clean"
+	// Initialize the full-text index with its settings, and with the records of the command if it has any
+	OpFTInit Op = "ft.init"
+	// Clear the full-text index
+	OpFTClean Op = "ft.clean"
+	// Set the maximum number of words of the full-text index
+	OpFTMaxSize Op = "ft.maxsize"
+	// Set the maximum size of the full-text index, in bytes
+	OpFTMaxBytes Op = "ft.maxbytes"
+	// Set the minimum length of the indexed words
+	OpFTMinWordLength Op = "ft.minwordlength"
+	// Renumber the record indices of the full-text index
+	OpFTSequence Op = "ft.sequence"
+	// Set or remove the schema of the records
+	OpSchema Op = "schema"
+	// Create a collection
+	OpCreate Op = "collections.create"
+	// Drop a collection
+	OpDrop Op = "collections.drop"
+	// Set the api address of a node, so that the other nodes can forward the requests to it when it's the leader
+	OpMember Op = "member"
+	// Forget the api address of a node that left the cluster
+	OpMemberRemove Op = "member.remove"
+)
+
+// FTSettings is a struct that represents the settings of a full-text index.
+type FTSettings struct {
+	// The maximum number of words. -1 for no limit
+	MaxSize int `json:"max_size"`
+	// The maximum size, in bytes. -1 for no limit
+	MaxBytes int `json:"max_bytes"`
+	// The minimum length of the indexed words
+	MinWordLength int `json:"min_word_length"`
+}
+
+// Command is a struct that represents a write to the collections, as it's stored in the log.
+type Command struct {
+	// The operation of the command
+	Op Op `json:"op"`
+	// The name of the collection. Empty for the default collection
+	Collection string `json:"collection,omitempty"`
+	// The key of the record, for the set, replace and delete commands
+	Key string `json:"key,omitempty"`
+	// The record, for the set and replace commands. The full-text values are stored in the
+	// wrapped json format, so a value created with WithFT is a full-text value on every node
+	Value map[string]any `json:"value,omitempty"`
+	// The records, mapped by key, that the full-text index is initialized with
+	Records map[string]map[string]any `json:"records,omitempty"`
+	// The full-text settings, for the ft.init commands
+	FT *FTSettings `json:"ft,omitempty"`
+	// The new limit, for the ft.maxsize, ft.maxbytes and ft.minwordlength commands
+	Limit int `json:"limit,omitempty"`
+	// The schema, for the schema commands. Nil removes the schema
+	Schema *hermes.Schema `json:"schema,omitempty"`
+	// The id of the node, for the member commands
+	ID string `json:"id,omitempty"`
+	// The api address of the node, for the member commands
+	Address string `json:"address,omitempty"`
+}
+
+// Consistency is the consistency of a read.
+type Consistency string
+
+const (
+	// The read is served by the leader, once the writes that were committed before it are applied,
+	// so it sees every write that was acknowledged before it started
+	ReadLeader Consistency = "leader"
+	// The read is served by the local node, which can be behind the leader
+	ReadStale Consistency = "stale"
+)
+
+// ErrNotFound is the error of the commands of a collection that doesn't exist.
+var ErrNotFound error = errors.New("not found")
+
+// ErrConflict is the error of the commands that create a collection that already exists.
+var ErrConflict error = errors.New("conflict")
+
+// ErrNoLeader is the error of the writes and leader reads while the cluster has no leader, for example during an election.
+var ErrNoLeader error = errors.New("the cluster has no leader")
+
+// NotLeaderError is the error of the writes and leader reads that are sent to a node that isn't the leader.
+type NotLeaderError struct {
+	// The id of the leader
+	ID string
+	// The api address of the leader. Empty if the leader didn't register one
+	Address string
+}
+
+// Error is a method of the NotLeaderError struct that returns the error message.
+// Returns:
+//   - string: The message, with the id of the leader.
+func (e *NotLeaderError) Error() string {
+	return fmt.Sprintf("the node is not the leader, the leader is %s", e.ID)
+}
+
+// commandError is a struct that represents an error of a command, with the kind of the error.
+// Fields:
+//   - err (error): The error of the cache.
+//   - kind (error): ErrNotFound or ErrConflict.
+type commandError struct {
+	err  error
+	kind error
+}
+
+// Error is a method of the commandError struct that returns the message of the error of the cache.
+// Returns:
+//   - string: The message.
+func (e *commandError) Error() string {
+	return e.err.Error()
+}
+
+// Is is a method of the commandError struct that reports whether the error is of a kind, for errors.Is.
+// Parameters:
+//   - target (error): The kind.
+//
+// Returns:
+//   - bool: Whether the error is of the kind.
+func (e *commandError) Is(target error) bool {
+	return target == e.kind
+}
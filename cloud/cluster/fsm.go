@@ -0,0 +1,286 @@
+package cluster
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/hashicorp/raft"
+	hermes "github.com/realTristan/hermes"
+)
+
+// fsm is a struct that applies the commands of the log to the collections.
+// Fields:
+//   - collections (*hermes.Collections): The collections of the node.
+//   - mutex (sync.RWMutex): The mutex that guards the members.
+//   - members (map[string]string): The api addresses of the nodes, mapped by id.
+type fsm struct {
+	collections *hermes.Collections
+	mutex       sync.RWMutex
+	members     map[string]string
+}
+
+// header is a struct that represents the first line of a snapshot.
+// Fields:
+//   - Members (map[string]string): The api addresses of the nodes, mapped by id.
+//   - Collections ([]collection): The collections, in the order their records follow the header.
+type header struct {
+	Members     map[string]string `json:"members"`
+	Collections []collection      `json:"collections"`
+}
+
+// collection is a struct that represents a collection of a snapshot.
+// Fields:
+//   - Name (string): The name of the collection.
+//   - FT (*FTSettings): The full-text settings. Nil if the full-text index isn't initialized.
+//   - Schema (*hermes.Schema): The schema. Nil if no schema is set.
+//   - Size (int): The size of the ndjson records of the collection, in bytes.
+type collection struct {
+	Name   string         `json:"name"`
+	FT     *FTSettings    `json:"ft,omitempty"`
+	Schema *hermes.Schema `json:"schema,omitempty"`
+	Size   int            `json:"size"`
+}
+
+// snapshot is a struct that represents a snapshot of the collections, that is written to the snapshot store.
+// Fields:
+//   - data ([]byte): The header line, followed by the ndjson records of each collection.
+type snapshot struct {
+	data []byte
+}
+
+// newFSM is a function that creates the state machine of the collections.
+// Parameters:
+//   - cs (*hermes.Collections): The collections of the node.
+//
+// Returns:
+//   - *fsm: The state machine.
+func newFSM(cs *hermes.Collections) *fsm {
+	return &fsm{
+		collections: cs,
+		members:     make(map[string]string),
+	}
+}
+
+// Apply is a method of the fsm struct that applies a command of the log.
+// Parameters:
+//   - l (*raft.Log): The log entry of the command.
+//
+// Returns:
+//   - any: The error of the command, or nil.
+func (f *fsm) Apply(l *raft.Log) any {
+	var cmd Command
+	if err := json.Unmarshal(l.Data, &cmd); err != nil {
+		return fmt.Errorf("invalid command: %w", err)
+	}
+	return f.apply(cmd)
+}
+
+// apply is a method of the fsm struct that applies a command to the collections.
+// The commands fail the same way on every node, so a failed command leaves every node unchanged.
+// Parameters:
+//   - cmd (Command): The command.
+//
+// Returns:
+//   - error: The error of the cache, or nil.
+func (f *fsm) apply(cmd Command) error {
+	switch cmd.Op {
+	case OpCreate:
+		if _, err := f.collections.Create(cmd.Collection); errors.Is(err, hermes.ErrCollectionExists) {
+			return &commandError{err: err, kind: ErrConflict}
+		} else if err != nil {
+			return err
+		}
+		return nil
+	case OpDrop:
+		if _, err := f.collections.Get(cmd.Collection); err != nil || len(cmd.Collection) == 0 {
+			return &commandError{err: fmt.Errorf("collection %s does not exist", cmd.Collection), kind: ErrNotFound}
+		}
+		return f.collections.Drop(cmd.Collection)
+	case OpMember:
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		f.members[cmd.ID] = cmd.Address
+		return nil
+	case OpMemberRemove:
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		delete(f.members, cmd.ID)
+		return nil
+	}
+
+	// Get the collection of the command
+	var c, err = f.collections.Get(cmd.Collection)
+	if err != nil {
+		return &commandError{err: err, kind: ErrNotFound}
+	}
+	switch cmd.Op {
+	case OpSet:
+		return c.Set(cmd.Key, cmd.Value)
+	case OpReplace:
+		return c.Replace(cmd.Key, cmd.Value)
+	case OpDelete:
+		c.Delete(cmd.Key)
+	case OpClean:
+		c.Clean()
+	case OpFTInit:
+		if cmd.FT == nil {
+			return errors.New("invalid full-text settings")
+		} else if cmd.Records != nil {
+			return c.FTInitWithMap(cmd.Records, cmd.FT.MaxSize, cmd.FT.MaxBytes, cmd.FT.MinWordLength)
+		}
+		return c.FTInit(cmd.FT.MaxSize, cmd.FT.MaxBytes, cmd.FT.MinWordLength)
+	case OpFTClean:
+		return c.FTClean()
+	case OpFTMaxSize:
+		return c.FTSetMaxSize(cmd.Limit)
+	case OpFTMaxBytes:
+		return c.FTSetMaxBytes(cmd.Limit)
+	case OpFTMinWordLength:
+		return c.FTSetMinWordLength(cmd.Limit)
+	case OpFTSequence:
+		if !c.FTIsInitialized() {
+			return errors.New("full-text is not initialized")
+		}
+		c.FTSequenceIndices()
+	case OpSchema:
+		return c.SetSchema(cmd.Schema)
+	default:
+		return fmt.Errorf("invalid command operation %s", cmd.Op)
+	}
+	return nil
+}
+
+// member is a method of the fsm struct that gets the api address of a node.
+// Parameters:
+//   - id (string): The id of the node.
+//
+// Returns:
+//   - string: The api address. Empty if the node didn't register one.
+func (f *fsm) member(id string) string {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.members[id]
+}
+
+// Snapshot is a method of the fsm struct that exports the collections, so that the log before them can be compacted.
+// The log doesn't apply commands while the collections are exported, and the export is written to the store after.
+// The full-text values that aren't stored in the records are exported with the records, so the restored nodes index them as well.
+// Returns:
+//   - raft.FSMSnapshot: The snapshot.
+//   - error: If a collection can't be exported.
+func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
+	var (
+		h       header = header{Members: make(map[string]string)}
+		records bytes.Buffer
+	)
+	f.mutex.RLock()
+	for id, address := range f.members {
+		h.Members[id] = address
+	}
+	f.mutex.RUnlock()
+
+	// Export the settings and the records of each collection
+	for _, name := range f.collections.List() {
+		var c, err = f.collections.Get(name)
+		if err != nil {
+			continue
+		}
+		var (
+			size int        = records.Len()
+			col  collection = collection{Name: name, Schema: c.GetSchema()}
+		)
+		if maxSize, maxBytes, minWordLength, err := c.FTSettings(); err == nil {
+			col.FT = &FTSettings{MaxSize: maxSize, MaxBytes: maxBytes, MinWordLength: minWordLength}
+		}
+		if err := c.ExportJSON(&records, hermes.ExportNDJSON); err != nil {
+			return nil, fmt.Errorf("collection %s: %w", name, err)
+		}
+		col.Size = records.Len() - size
+		h.Collections = append(h.Collections, col)
+	}
+
+	// Write the header before the records
+	var data, err = json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{data: append(append(data, '\n'), records.Bytes()...)}, nil
+}
+
+// Restore is a method of the fsm struct that replaces the collections with the ones of a snapshot.
+// Each collection is loaded into a new cache, which replaces the cache of the collection once it's complete.
+// Parameters:
+//   - rc (io.ReadCloser): The reader of the snapshot.
+//
+// Returns:
+//   - error: If the snapshot is invalid.
+func (f *fsm) Restore(rc io.ReadCloser) error {
+	defer rc.Close()
+	var (
+		r *bufio.Reader = bufio.NewReader(rc)
+		h header
+	)
+	if line, err := r.ReadBytes('\n'); err != nil {
+		return fmt.Errorf("invalid snapshot: %w", err)
+	} else if err := json.Unmarshal(line, &h); err != nil {
+		return fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	// Load the collections
+	var caches map[string]*hermes.Cache = make(map[string]*hermes.Cache, len(h.Collections))
+	for _, col := range h.Collections {
+		var c *hermes.Cache = hermes.InitCache()
+		if err := c.SetSchema(col.Schema); err != nil {
+			return fmt.Errorf("collection %s: %w", col.Name, err)
+		} else if col.FT != nil {
+			if err := c.FTInit(col.FT.MaxSize, col.FT.MaxBytes, col.FT.MinWordLength); err != nil {
+				return fmt.Errorf("collection %s: %w", col.Name, err)
+			}
+		}
+		if err := c.LoadNDJSON(io.LimitReader(r, int64(col.Size)), nil); err != nil {
+			return fmt.Errorf("collection %s: %w", col.Name, err)
+		}
+		caches[col.Name] = c
+	}
+
+	// Replace the collections, and drop the ones that aren't in the snapshot
+	for _, name := range f.collections.List() {
+		if _, ok := caches[name]; !ok && name != hermes.DefaultCollection {
+			_ = f.collections.Drop(name)
+		}
+	}
+	for name, c := range caches {
+		if err := f.collections.Replace(name, c); err != nil {
+			return err
+		}
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.members = h.Members
+	if f.members == nil {
+		f.members = make(map[string]string)
+	}
+	return nil
+}
+
+// Persist is a method of the snapshot struct that writes the snapshot to the store.
+// Parameters:
+//   - sink (raft.SnapshotSink): The sink of the store.
+//
+// Returns:
+//   - error: If the snapshot can't be written.
+func (s *snapshot) Persist(sink raft.SnapshotSink) error {
+	if _, err := sink.Write(s.data); err != nil {
+		_ = sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+// Release is a method of the snapshot struct that is called once the snapshot was written.
+func (s *snapshot) Release() {}
@@ -0,0 +1,345 @@
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/proxy"
+	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/cloud/api"
+	utils "github.com/realTristan/hermes/cloud/api/utils"
+	"github.com/realTristan/hermes/cloud/auth"
+	"github.com/valyala/fasthttp"
+)
+
+// ForwardedHeader is the header of the requests that a node forwards to the leader. A node
+// doesn't forward them again, so a request can't loop between the nodes during an election.
+const ForwardedHeader string = "X-Hermes-Forwarded"
+
+// HandlersConfig is a struct that represents the settings of the REST handlers of a node.
+type HandlersConfig struct {
+	// The consistency of the reads of the REST API. With ReadLeader, the reads are forwarded to the
+	// leader, which serves them once the earlier writes are applied. Defaults to ReadStale
+	Reads Consistency
+	// The tls settings of the requests that are forwarded to the leader. Defaults to the system CAs
+	TLS *tls.Config
+}
+
+// Handlers is a struct that serves a node over the REST API. The writes are applied through the log
+// of the cluster, and the requests that must be served by the leader are forwarded to its api address.
+// Fields:
+//   - node (*Node): The node.
+//   - reads (Consistency): The consistency of the reads.
+//   - client (*fasthttp.Client): The client of the requests that are forwarded to the leader.
+type Handlers struct {
+	node   *Node
+	reads  Consistency
+	client *fasthttp.Client
+}
+
+// request is a function that converts the request of a write route into a command.
+type request func(ctx *fiber.Ctx) (Command, error)
+
+// requests are the commands of the write routes of the api, mapped by path. The routes that
+// aren't in the map respond with 403, since their changes aren't replicated by the cluster.
+var requests map[string]request = map[string]request{
+	"/collections/create": func(ctx *fiber.Ctx) (Command, error) {
+		if name := ctx.Query("name"); len(name) == 0 {
+			return Command{}, errors.New("invalid collection name")
+		} else {
+			return Command{Op: OpCreate, Collection: name}, nil
+		}
+	},
+	"/collections/drop": func(ctx *fiber.Ctx) (Command, error) {
+		if name := ctx.Query("name"); len(name) == 0 {
+			return Command{}, errors.New("invalid collection name")
+		} else if name == hermes.DefaultCollection {
+			return Command{}, errors.New("the default collection can't be dropped")
+		} else {
+			return Command{Op: OpDrop, Collection: name}, nil
+		}
+	},
+	"/cache/clean": func(ctx *fiber.Ctx) (Command, error) {
+		return Command{Op: OpClean}, nil
+	},
+	"/cache/set": func(ctx *fiber.Ctx) (Command, error) {
+		var cmd Command = Command{Op: OpSet, Key: ctx.Query("key")}
+		if len(cmd.Key) == 0 {
+			return cmd, errors.New("invalid key")
+		}
+		return cmd, utils.GetValueParam(ctx, &cmd.Value)
+	},
+	"/cache/delete": func(ctx *fiber.Ctx) (Command, error) {
+		var cmd Command = Command{Op: OpDelete, Key: ctx.Query("key")}
+		if len(cmd.Key) == 0 {
+			return cmd, errors.New("key not provided")
+		}
+		return cmd, nil
+	},
+	"/cache/schema": func(ctx *fiber.Ctx) (Command, error) {
+		var cmd Command = Command{Op: OpSchema}
+		return cmd, utils.GetValueParam(ctx, &cmd.Schema)
+	},
+	"/ft/init": func(ctx *fiber.Ctx) (Command, error) {
+		var settings, err = ftSettings(ctx)
+		return Command{Op: OpFTInit, FT: settings}, err
+	},
+	"/ft/init/json": func(ctx *fiber.Ctx) (Command, error) {
+		var settings, err = ftSettings(ctx)
+		if err != nil {
+			return Command{}, err
+		}
+		var cmd Command = Command{Op: OpFTInit, FT: settings}
+		return cmd, utils.GetJSONParam(ctx, &cmd.Records)
+	},
+	"/ft/clean": func(ctx *fiber.Ctx) (Command, error) {
+		return Command{Op: OpFTClean}, nil
+	},
+	"/ft/maxbytes": func(ctx *fiber.Ctx) (Command, error) {
+		var cmd Command = Command{Op: OpFTMaxBytes}
+		return cmd, utils.GetMaxBytesParam(ctx, &cmd.Limit)
+	},
+	"/ft/maxsize": func(ctx *fiber.Ctx) (Command, error) {
+		var cmd Command = Command{Op: OpFTMaxSize}
+		return cmd, utils.GetMaxSizeParam(ctx, &cmd.Limit)
+	},
+	"/ft/minwordlength": func(ctx *fiber.Ctx) (Command, error) {
+		var cmd Command = Command{Op: OpFTMinWordLength}
+		return cmd, utils.GetMinWordLengthParam(ctx, &cmd.Limit)
+	},
+	"/ft/indices/sequence": func(ctx *fiber.Ctx) (Command, error) {
+		return Command{Op: OpFTSequence}, nil
+	},
+}
+
+// NewHandlers is a function that creates the REST handlers of a node.
+// Parameters:
+//   - n (*Node): The node.
+//   - config (HandlersConfig): The settings of the handlers.
+//
+// Returns:
+//   - *Handlers: The handlers. Its Writes and Reads are set in the api.Config of the routes, and SetRoutes sets the /cluster routes.
+func NewHandlers(n *Node, config HandlersConfig) *Handlers {
+	if len(config.Reads) == 0 {
+		config.Reads = ReadStale
+	}
+	return &Handlers{
+		node:   n,
+		reads:  config.Reads,
+		client: &fasthttp.Client{TLSConfig: config.TLS, NoDefaultUserAgentHeader: true},
+	}
+}
+
+// Writes is a method of the Handlers struct that returns the handler of a write route, which applies the write through the log.
+// Parameters:
+//   - r (api.Route): The route.
+//
+// Returns:
+//   - fiber.Handler: The handler. The routes whose changes aren't replicated, like the registered queries, respond with 403.
+func (h *Handlers) Writes(r api.Route) fiber.Handler {
+	var req, ok = requests[r.Path]
+	if !ok {
+		return unsupported
+	}
+	var status int = r.Status
+	if status == 0 {
+		status = fiber.StatusOK
+	}
+	return func(ctx *fiber.Ctx) error {
+		if !h.node.IsLeader() {
+			return h.forward(ctx)
+		}
+
+		// Convert the request into a command of the collection
+		var cmd, err = req(ctx)
+		if err != nil {
+			return utils.BadRequest(ctx, err)
+		} else if len(cmd.Collection) == 0 {
+			cmd.Collection = ctx.Query("collection")
+		}
+
+		// Apply the command
+		var c, cancel = context.WithTimeout(context.Background(), DefaultTimeout)
+		defer cancel()
+		if err := h.node.Apply(c, cmd); err != nil {
+			return h.failed(ctx, err)
+		}
+		return utils.Success(ctx.Status(status), nil)
+	}
+}
+
+// Reads is a method of the Handlers struct that returns the handler that is called before the read routes.
+// Returns:
+//   - fiber.Handler: The handler that forwards the reads to the leader, which serves them once the earlier
+//     writes are applied. Nil for the stale reads, which are served by every node.
+func (h *Handlers) Reads() fiber.Handler {
+	if h.reads != ReadLeader {
+		return nil
+	}
+	return func(ctx *fiber.Ctx) error {
+		if !h.node.IsLeader() {
+			return h.forward(ctx)
+		}
+		var c, cancel = context.WithTimeout(context.Background(), DefaultTimeout)
+		defer cancel()
+		if err := h.node.Barrier(c); err != nil {
+			return h.failed(ctx, err)
+		}
+		return ctx.Next()
+	}
+}
+
+// SetRoutes is a method of the Handlers struct that sets the routes of the cluster: its status, and its membership changes.
+// Parameters:
+//   - app (*fiber.App): A pointer to a fiber.App struct.
+//   - a (auth.Authenticator): The authenticator of the requests. If nil, authentication is disabled.
+func (h *Handlers) SetRoutes(app *fiber.App, a auth.Authenticator) {
+	app.Get("/cluster/status", auth.Require(a, auth.ScopeRead), h.status)
+	app.Post("/cluster/join", auth.Require(a, auth.ScopeAdmin), h.join)
+	app.Post("/cluster/leave", auth.Require(a, auth.ScopeAdmin), h.leave)
+	app.Post("/cluster/snapshot", auth.Require(a, auth.ScopeAdmin), h.snapshot)
+}
+
+// status is a method of the Handlers struct that responds with the state of the node.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a fiber context.
+//
+// Returns:
+//   - error: The error of the response, if any.
+func (h *Handlers) status(ctx *fiber.Ctx) error {
+	var c, cancel = context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	if status, err := h.node.Status(c); err != nil {
+		return utils.Error(ctx, fiber.StatusServiceUnavailable, err)
+	} else {
+		return utils.Success(ctx, status)
+	}
+}
+
+// join is a method of the Handlers struct that adds the node of the request body to the cluster.
+// The body is a json object with the id, the address and the api address of the node.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a fiber context.
+//
+// Returns:
+//   - error: The error of the response, if any.
+func (h *Handlers) join(ctx *fiber.Ctx) error {
+	if !h.node.IsLeader() {
+		return h.forward(ctx)
+	}
+	var s Server
+	if err := json.Unmarshal(ctx.Body(), &s); err != nil || len(s.ID) == 0 || len(s.Address) == 0 {
+		return utils.BadRequest(ctx, "invalid node, expected a json body with its id, address and api")
+	}
+	var c, cancel = context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	if err := h.node.Join(c, s.ID, s.Address, s.API); err != nil {
+		return h.failed(ctx, err)
+	}
+	return utils.Success(ctx, nil)
+}
+
+// leave is a method of the Handlers struct that removes the node in the "id" query parameter from the cluster.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a fiber context.
+//
+// Returns:
+//   - error: The error of the response, if any.
+func (h *Handlers) leave(ctx *fiber.Ctx) error {
+	if !h.node.IsLeader() {
+		return h.forward(ctx)
+	}
+	var id string = ctx.Query("id")
+	if len(id) == 0 {
+		return utils.BadRequest(ctx, "invalid node id")
+	}
+	var c, cancel = context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	if err := h.node.Leave(c, id); err != nil {
+		return h.failed(ctx, err)
+	}
+	return utils.Success(ctx, nil)
+}
+
+// snapshot is a method of the Handlers struct that takes a snapshot of the node, and compacts its log.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a fiber context.
+//
+// Returns:
+//   - error: The error of the response, if any.
+func (h *Handlers) snapshot(ctx *fiber.Ctx) error {
+	if err := h.node.Snapshot(); err != nil {
+		return utils.Failed(ctx, err)
+	}
+	return utils.Success(ctx, nil)
+}
+
+// forward is a method of the Handlers struct that sends a request to the leader, and responds with its response.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a fiber context.
+//
+// Returns:
+//   - error: The error of the response, if any.
+func (h *Handlers) forward(ctx *fiber.Ctx) error {
+	var _, address = h.node.Leader()
+	if len(ctx.Get(ForwardedHeader)) > 0 || len(address) == 0 {
+		return utils.Error(ctx, fiber.StatusServiceUnavailable, ErrNoLeader)
+	}
+	ctx.Request().Header.Set(ForwardedHeader, h.node.id)
+	if err := proxy.DoTimeout(ctx, address+ctx.OriginalURL(), DefaultTimeout, h.client); err != nil {
+		return utils.Error(ctx, fiber.StatusBadGateway, err)
+	}
+	return nil
+}
+
+// failed is a method of the Handlers struct that responds with the error of a command.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a fiber context.
+//   - err (error): The error.
+//
+// Returns:
+//   - error: The error of the response, if any.
+func (h *Handlers) failed(ctx *fiber.Ctx, err error) error {
+	var notLeader *NotLeaderError
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return utils.NotFound(ctx, err)
+	case errors.Is(err, ErrConflict):
+		return utils.Error(ctx, fiber.StatusConflict, err)
+	case errors.Is(err, ErrNoLeader), errors.As(err, &notLeader), errors.Is(err, context.DeadlineExceeded):
+		return utils.Error(ctx, fiber.StatusServiceUnavailable, err)
+	}
+	return utils.Failed(ctx, err)
+}
+
+// ftSettings is a function that reads the full-text settings in the query parameters of a request.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a fiber context.
+//
+// Returns:
+//   - *FTSettings: The settings.
+//   - error: If a parameter is missing or invalid.
+func ftSettings(ctx *fiber.Ctx) (*FTSettings, error) {
+	var settings FTSettings
+	if err := utils.GetMaxSizeParam(ctx, &settings.MaxSize); err != nil {
+		return nil, err
+	} else if err := utils.GetMaxBytesParam(ctx, &settings.MaxBytes); err != nil {
+		return nil, err
+	} else if err := utils.GetMinWordLengthParam(ctx, &settings.MinWordLength); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// unsupported is a function that responds to the write routes whose changes aren't replicated by the cluster.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a fiber context.
+//
+// Returns:
+//   - error: The error of the 403 response, if any.
+func unsupported(ctx *fiber.Ctx) error {
+	return utils.Error(ctx, fiber.StatusForbidden, "the route changes the node only, it's not supported by the nodes of a cluster")
+}
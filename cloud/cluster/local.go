@@ -0,0 +1,243 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+	hermes "github.com/realTristan/hermes"
+)
+
+// LocalCluster is a struct that represents a cluster whose nodes run in the same process, and
+// are connected in memory. It's meant for tests and examples: nodes can be added, removed, and
+// stopped or disconnected to simulate their loss.
+// Fields:
+//   - mutex (sync.Mutex): The mutex that guards the nodes and the transports.
+//   - config (Config): The settings of the new nodes.
+//   - nodes ([]*Node): The running nodes, in the order they were added.
+//   - transports (map[string]*raft.InmemTransport): The transports of the nodes, mapped by id.
+//   - next (int): The number of the next node.
+type LocalCluster struct {
+	mutex      sync.Mutex
+	config     Config
+	nodes      []*Node
+	transports map[string]*raft.InmemTransport
+	next       int
+}
+
+// NewLocalCluster is a function that starts an in-process cluster. The first node starts the
+// cluster, and the other ones join it once it's the leader.
+// Parameters:
+//   - n (int): The number of nodes.
+//   - config (Config): The settings of the nodes. The ID, Address, Transport, Dir and Bootstrap fields are set for each node.
+//     The election timeout defaults to 200 milliseconds, so that the elections are quick.
+//
+// Returns:
+//   - *LocalCluster: The cluster.
+//   - error: If a node can't be started or added.
+func NewLocalCluster(n int, config Config) (*LocalCluster, error) {
+	if n < 1 {
+		return nil, errors.New("a cluster needs at least one node")
+	} else if config.ElectionTimeout <= 0 {
+		config.ElectionTimeout = 200 * time.Millisecond
+	}
+	var lc *LocalCluster = &LocalCluster{
+		config:     config,
+		transports: make(map[string]*raft.InmemTransport),
+	}
+
+	// Start the first node, and wait until it's the leader
+	if _, err := lc.start(true); err != nil {
+		return nil, err
+	} else if _, err := lc.Leader(50 * config.ElectionTimeout); err != nil {
+		lc.Shutdown()
+		return nil, err
+	}
+
+	// Add the other nodes
+	for i := 1; i < n; i++ {
+		if _, err := lc.Add(context.Background()); err != nil {
+			lc.Shutdown()
+			return nil, err
+		}
+	}
+	return lc, nil
+}
+
+// Nodes is a method of the LocalCluster struct that returns the running nodes.
+// Returns:
+//   - []*Node: The nodes, in the order they were added.
+func (lc *LocalCluster) Nodes() []*Node {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	return append([]*Node(nil), lc.nodes...)
+}
+
+// Leader is a method of the LocalCluster struct that waits until a running node is the leader.
+// Parameters:
+//   - timeout (time.Duration): How long to wait.
+//
+// Returns:
+//   - *Node: The leader.
+//   - error: If no node is the leader before the timeout.
+func (lc *LocalCluster) Leader(timeout time.Duration) (*Node, error) {
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		for _, n := range lc.Nodes() {
+			if n.IsLeader() {
+				return n, nil
+			}
+		}
+	}
+	return nil, ErrNoLeader
+}
+
+// Add is a method of the LocalCluster struct that starts a node, and adds it to the cluster through the leader.
+// Parameters:
+//   - ctx (context.Context): The context of the membership change.
+//
+// Returns:
+//   - *Node: The new node. Its collections are filled from the log and the snapshots of the leader.
+//   - error: If the node can't be started or added.
+func (lc *LocalCluster) Add(ctx context.Context) (*Node, error) {
+	var leader, err = lc.Leader(timeout(ctx))
+	if err != nil {
+		return nil, err
+	}
+	n, err := lc.start(false)
+	if err != nil {
+		return nil, err
+	} else if err := leader.Join(ctx, n.id, string(lc.transport(n.id).LocalAddr()), ""); err != nil {
+		lc.Stop(n)
+		return nil, err
+	}
+	return n, nil
+}
+
+// Remove is a method of the LocalCluster struct that removes a node from the cluster through the leader, and stops it.
+// Parameters:
+//   - ctx (context.Context): The context of the membership change.
+//   - n (*Node): The node.
+//
+// Returns:
+//   - error: If the node can't be removed.
+func (lc *LocalCluster) Remove(ctx context.Context, n *Node) error {
+	var leader, err = lc.Leader(timeout(ctx))
+	if err != nil {
+		return err
+	} else if err := leader.Leave(ctx, n.id); err != nil {
+		return err
+	}
+	lc.Stop(n)
+	return nil
+}
+
+// Stop is a method of the LocalCluster struct that disconnects and stops a node without removing it
+// from the cluster, like a node that crashed. The cluster keeps working while a majority of its nodes run.
+// Parameters:
+//   - n (*Node): The node.
+func (lc *LocalCluster) Stop(n *Node) {
+	lc.Disconnect(n)
+	_ = n.Shutdown()
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	for i, node := range lc.nodes {
+		if node == n {
+			lc.nodes = append(lc.nodes[:i], lc.nodes[i+1:]...)
+			break
+		}
+	}
+	delete(lc.transports, n.id)
+}
+
+// Disconnect is a method of the LocalCluster struct that cuts a node off from the other nodes, like a network partition.
+// Parameters:
+//   - n (*Node): The node.
+func (lc *LocalCluster) Disconnect(n *Node) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	var t *raft.InmemTransport = lc.transports[n.id]
+	if t == nil {
+		return
+	}
+	t.DisconnectAll()
+	for id, other := range lc.transports {
+		if id != n.id {
+			other.Disconnect(t.LocalAddr())
+		}
+	}
+}
+
+// Reconnect is a method of the LocalCluster struct that connects a node to the other nodes again.
+// Parameters:
+//   - n (*Node): The node.
+func (lc *LocalCluster) Reconnect(n *Node) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	var t *raft.InmemTransport = lc.transports[n.id]
+	if t == nil {
+		return
+	}
+	for id, other := range lc.transports {
+		if id != n.id {
+			t.Connect(other.LocalAddr(), other)
+			other.Connect(t.LocalAddr(), t)
+		}
+	}
+}
+
+// Shutdown is a method of the LocalCluster struct that stops every node.
+func (lc *LocalCluster) Shutdown() {
+	for _, n := range lc.Nodes() {
+		lc.Stop(n)
+	}
+}
+
+// start is a method of the LocalCluster struct that starts a node and connects it to the other nodes.
+// Parameters:
+//   - bootstrap (bool): Whether the node starts the cluster.
+//
+// Returns:
+//   - *Node: The node.
+//   - error: If the node can't be started.
+func (lc *LocalCluster) start(bootstrap bool) (*Node, error) {
+	lc.mutex.Lock()
+	var (
+		id     string = fmt.Sprintf("node-%d", lc.next)
+		config Config = lc.config
+		_, t          = raft.NewInmemTransport("")
+	)
+	lc.next++
+	for _, other := range lc.transports {
+		t.Connect(other.LocalAddr(), other)
+		other.Connect(t.LocalAddr(), t)
+	}
+	lc.transports[id] = t
+	lc.mutex.Unlock()
+
+	// Start the node with its transport
+	config.ID, config.Address, config.Transport, config.Dir, config.Bootstrap = id, "", t, "", bootstrap
+	var n, err = NewNode(hermes.InitCollections(), config)
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	if err != nil {
+		delete(lc.transports, id)
+		return nil, err
+	}
+	lc.nodes = append(lc.nodes, n)
+	return n, nil
+}
+
+// transport is a method of the LocalCluster struct that gets the transport of a node.
+// Parameters:
+//   - id (string): The id of the node.
+//
+// Returns:
+//   - *raft.InmemTransport: The transport. Nil if the node was stopped.
+func (lc *LocalCluster) transport(id string) *raft.InmemTransport {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	return lc.transports[id]
+}
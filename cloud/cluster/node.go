@@ -0,0 +1,623 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb"
+	hermes "github.com/realTristan/hermes"
+)
+
+// DefaultTimeout is how long the writes, reads and membership changes wait when their context has no deadline.
+const DefaultTimeout time.Duration = 10 * time.Second
+
+// Config is a struct that represents the settings of a node.
+type Config struct {
+	// The id of the node. It must be unique in the cluster, and stay the same when the node restarts
+	ID string
+	// The address that the node listens on for the other nodes, as host:port. Ignored if Transport is set
+	Address string
+	// The address that the other nodes connect to, if it's not the one that the node listens on
+	Advertise string
+	// The transport between the nodes, for example a raft.InmemTransport for an in-process cluster.
+	// If nil, the nodes connect over tcp
+	Transport raft.Transport
+	// The api address of the node, that the other nodes forward the requests to when it's the leader
+	API string
+	// The directory of the log and the snapshots. If empty, they're kept in memory, so the
+	// node starts empty when it restarts, and gets the collections from the other nodes
+	Dir string
+	// Whether to start a new cluster with this node as its only member. Ignored if the node already has a log
+	Bootstrap bool
+	// How long a follower waits for the leader before it starts an election. Defaults to 1 second
+	ElectionTimeout time.Duration
+	// The number of new log entries after which a snapshot is taken. Defaults to 8192
+	SnapshotThreshold uint64
+	// How often the node checks whether to take a snapshot. Defaults to 2 minutes
+	SnapshotInterval time.Duration
+	// The number of snapshots that are kept in Dir. Defaults to 2
+	SnapshotRetain int
+	// The number of log entries that are kept after a snapshot, so that a slow follower
+	// can catch up without the snapshot. Defaults to 10240
+	TrailingLogs uint64
+	// The writer of the raft logs. If nil, they're discarded
+	LogOutput io.Writer
+	// The level of the raft logs: trace, debug, info, warn or error. Defaults to warn
+	LogLevel string
+}
+
+// Server is a struct that represents a member of the cluster.
+type Server struct {
+	// The id of the node
+	ID string `json:"id"`
+	// The address that the other nodes connect to
+	Address string `json:"address"`
+	// The api address of the node. Empty if it didn't register one
+	API string `json:"api,omitempty"`
+	// Whether the node votes in the elections
+	Voter bool `json:"voter"`
+	// Whether the node is the leader
+	Leader bool `json:"leader"`
+}
+
+// Status is a struct that represents the state of a node.
+type Status struct {
+	// The id of the node
+	ID string `json:"id"`
+	// The state of the node: Leader, Follower, Candidate or Shutdown
+	State string `json:"state"`
+	// The id of the leader. Empty if the cluster has no leader
+	Leader string `json:"leader"`
+	// The term of the current election
+	Term uint64 `json:"term"`
+	// The index of the last log entry
+	LastIndex uint64 `json:"last_index"`
+	// The index of the last committed log entry
+	CommitIndex uint64 `json:"commit_index"`
+	// The index of the last log entry that was applied to the collections
+	AppliedIndex uint64 `json:"applied_index"`
+	// The index of the last log entry of the last snapshot
+	SnapshotIndex uint64 `json:"snapshot_index"`
+	// The members of the cluster
+	Servers []Server `json:"servers"`
+}
+
+// Node is a struct that represents a member of a cluster. The writes to its collections go through the log
+// of the cluster, so that they're applied on every node in the same order once a majority has stored them.
+// Fields:
+//   - id (string): The id of the node.
+//   - api (string): The api address of the node.
+//   - collections (*hermes.Collections): The collections of the node.
+//   - fsm (*fsm): The state machine that applies the log to the collections.
+//   - raft (*raft.Raft): The raft instance.
+//   - closers ([]io.Closer): The stores and the transport that are closed by Shutdown.
+//   - done (chan struct{}): A channel that's closed by Shutdown.
+//   - once (sync.Once): Ensures that the node is only shut down once.
+type Node struct {
+	id          string
+	api         string
+	collections *hermes.Collections
+	fsm         *fsm
+	raft        *raft.Raft
+	closers     []io.Closer
+	done        chan struct{}
+	once        sync.Once
+}
+
+// NewNode is a function that starts a node of a cluster.
+// The collections must be empty: they're filled from the snapshots and the log of the cluster,
+// and must only be changed through the node afterwards.
+// Parameters:
+//   - cs (*hermes.Collections): The collections of the node.
+//   - config (Config): The settings of the node.
+//
+// Returns:
+//   - *Node: The node.
+//   - error: If the settings are invalid, or the stores or the transport can't be opened.
+func NewNode(cs *hermes.Collections, config Config) (*Node, error) {
+	if len(config.ID) == 0 {
+		return nil, errors.New("invalid node id")
+	} else if config.Transport == nil && len(config.Address) == 0 {
+		return nil, errors.New("invalid node address")
+	}
+	var n *Node = &Node{
+		id:          config.ID,
+		api:         config.API,
+		collections: cs,
+		fsm:         newFSM(cs),
+		done:        make(chan struct{}),
+	}
+
+	// Create the raft settings
+	var logOutput io.Writer = config.LogOutput
+	if logOutput == nil {
+		logOutput = io.Discard
+	}
+	var rc *raft.Config = raft.DefaultConfig()
+	rc.LocalID = raft.ServerID(config.ID)
+	rc.Logger = hclog.New(&hclog.LoggerOptions{Name: "raft", Output: logOutput, Level: logLevel(config.LogLevel)})
+	if config.ElectionTimeout > 0 {
+		rc.HeartbeatTimeout = config.ElectionTimeout
+		rc.ElectionTimeout = config.ElectionTimeout
+		rc.LeaderLeaseTimeout = config.ElectionTimeout / 2
+	}
+	if config.SnapshotThreshold > 0 {
+		rc.SnapshotThreshold = config.SnapshotThreshold
+	}
+	if config.SnapshotInterval > 0 {
+		rc.SnapshotInterval = config.SnapshotInterval
+	}
+	if config.TrailingLogs > 0 {
+		rc.TrailingLogs = config.TrailingLogs
+	}
+	if config.SnapshotRetain <= 0 {
+		config.SnapshotRetain = 2
+	}
+
+	// Open the stores and the transport
+	var (
+		logs      raft.LogStore
+		stable    raft.StableStore
+		snapshots raft.SnapshotStore
+		transport raft.Transport = config.Transport
+	)
+	if len(config.Dir) == 0 {
+		var store *raft.InmemStore = raft.NewInmemStore()
+		logs, stable, snapshots = store, store, raft.NewInmemSnapshotStore()
+	} else {
+		if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+			return nil, err
+		}
+		var store, err = raftboltdb.NewBoltStore(filepath.Join(config.Dir, "raft.db"))
+		if err != nil {
+			return nil, err
+		}
+		n.closers = append(n.closers, store)
+		if logs, err = raft.NewLogCache(512, store); err != nil {
+			n.close()
+			return nil, err
+		} else if snapshots, err = raft.NewFileSnapshotStoreWithLogger(config.Dir, config.SnapshotRetain, rc.Logger); err != nil {
+			n.close()
+			return nil, err
+		}
+		stable = store
+	}
+	if transport == nil {
+		var advertise net.Addr
+		if len(config.Advertise) > 0 {
+			var err error
+			if advertise, err = net.ResolveTCPAddr("tcp", config.Advertise); err != nil {
+				n.close()
+				return nil, err
+			}
+		}
+		var t, err = raft.NewTCPTransportWithLogger(config.Address, advertise, 3, DefaultTimeout, rc.Logger)
+		if err != nil {
+			n.close()
+			return nil, err
+		}
+		n.closers = append(n.closers, t)
+		transport = t
+	}
+
+	// Start the node, and start a new cluster if it's the first node
+	var r, err = raft.NewRaft(rc, n.fsm, logs, stable, snapshots, transport)
+	if err != nil {
+		n.close()
+		return nil, err
+	}
+	n.raft = r
+	if config.Bootstrap {
+		var servers []raft.Server = []raft.Server{{ID: rc.LocalID, Address: transport.LocalAddr()}}
+		if err := r.BootstrapCluster(raft.Configuration{Servers: servers}).Error(); err != nil && err != raft.ErrCantBootstrap {
+			_ = n.Shutdown()
+			return nil, err
+		}
+	}
+	go n.register()
+	return n, nil
+}
+
+// ID is a method of the Node struct that returns the id of the node.
+// Returns:
+//   - string: The id.
+func (n *Node) ID() string {
+	return n.id
+}
+
+// Collections is a method of the Node struct that returns the collections of the node, for the stale reads.
+// Returns:
+//   - *hermes.Collections: The collections.
+func (n *Node) Collections() *hermes.Collections {
+	return n.collections
+}
+
+// IsLeader is a method of the Node struct that reports whether the node is the leader.
+// Returns:
+//   - bool: Whether the node is the leader.
+func (n *Node) IsLeader() bool {
+	return n.raft.State() == raft.Leader
+}
+
+// Leader is a method of the Node struct that returns the leader of the cluster.
+// Returns:
+//   - string: The id of the leader. Empty if the cluster has no leader.
+//   - string: The api address of the leader. Empty if the cluster has no leader, or the leader didn't register one.
+func (n *Node) Leader() (string, string) {
+	var _, id = n.raft.LeaderWithID()
+	if len(id) == 0 {
+		return "", ""
+	}
+	return string(id), n.fsm.member(string(id))
+}
+
+// Apply is a method of the Node struct that writes a command to the log, and waits until it's applied on the node.
+// Parameters:
+//   - ctx (context.Context): The context of the write.
+//   - cmd (Command): The command.
+//
+// Returns:
+//   - error: A *NotLeaderError or ErrNoLeader if the node isn't the leader, or the error of the command.
+func (n *Node) Apply(ctx context.Context, cmd Command) error {
+	if err := n.leader(); err != nil {
+		return err
+	}
+	var data, err = json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Wait until the command is committed and applied
+	var f raft.ApplyFuture = n.raft.Apply(data, timeout(ctx))
+	if err := wait(ctx, f); err != nil {
+		return n.raftError(err)
+	} else if err, ok := f.Response().(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Set is a method of the Node struct that sets a record in a collection. The key must not exist.
+// Parameters:
+//   - ctx (context.Context): The context of the write.
+//   - collection (string): The name of the collection. Empty for the default collection.
+//   - key (string): The key of the record.
+//   - value (map[string]any): The record. The values created with WithFT are full-text values.
+//
+// Returns:
+//   - error: The error of the write.
+func (n *Node) Set(ctx context.Context, collection, key string, value map[string]any) error {
+	return n.Apply(ctx, Command{Op: OpSet, Collection: collection, Key: key, Value: value})
+}
+
+// Replace is a method of the Node struct that sets a record in a collection, whether the key exists or not.
+// Parameters:
+//   - ctx (context.Context): The context of the write.
+//   - collection (string): The name of the collection. Empty for the default collection.
+//   - key (string): The key of the record.
+//   - value (map[string]any): The record. The values created with WithFT are full-text values.
+//
+// Returns:
+//   - error: The error of the write.
+func (n *Node) Replace(ctx context.Context, collection, key string, value map[string]any) error {
+	return n.Apply(ctx, Command{Op: OpReplace, Collection: collection, Key: key, Value: value})
+}
+
+// Delete is a method of the Node struct that deletes a record of a collection.
+// Parameters:
+//   - ctx (context.Context): The context of the write.
+//   - collection (string): The name of the collection. Empty for the default collection.
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - error: The error of the write.
+func (n *Node) Delete(ctx context.Context, collection, key string) error {
+	return n.Apply(ctx, Command{Op: OpDelete, Collection: collection, Key: key})
+}
+
+// FTInit is a method of the Node struct that initializes the full-text index of a collection.
+// Parameters:
+//   - ctx (context.Context): The context of the write.
+//   - collection (string): The name of the collection. Empty for the default collection.
+//   - settings (FTSettings): The settings of the full-text index.
+//
+// Returns:
+//   - error: The error of the write.
+func (n *Node) FTInit(ctx context.Context, collection string, settings FTSettings) error {
+	return n.Apply(ctx, Command{Op: OpFTInit, Collection: collection, FT: &settings})
+}
+
+// Read is a method of the Node struct that reads the collections with a consistency.
+// Parameters:
+//   - ctx (context.Context): The context of the read.
+//   - consistency (Consistency): ReadLeader to read the latest writes on the leader, or ReadStale to read the local collections.
+//   - fn (func(*hermes.Collections) error): The function that reads the collections.
+//
+// Returns:
+//   - error: A *NotLeaderError or ErrNoLeader for a leader read on a node that isn't the leader, or the error of the function.
+func (n *Node) Read(ctx context.Context, consistency Consistency, fn func(cs *hermes.Collections) error) error {
+	if consistency == ReadLeader {
+		if err := n.Barrier(ctx); err != nil {
+			return err
+		}
+	}
+	return fn(n.collections)
+}
+
+// Barrier is a method of the Node struct that waits until the writes that were committed before it are applied on the leader.
+// Parameters:
+//   - ctx (context.Context): The context of the read.
+//
+// Returns:
+//   - error: A *NotLeaderError or ErrNoLeader if the node isn't the leader, or if it lost the leadership.
+func (n *Node) Barrier(ctx context.Context) error {
+	if err := n.leader(); err != nil {
+		return err
+	} else if err := wait(ctx, n.raft.Barrier(timeout(ctx))); err != nil {
+		return n.raftError(err)
+	}
+	return nil
+}
+
+// Join is a method of the Node struct that adds a node to the cluster. It must be called on the leader.
+// Parameters:
+//   - ctx (context.Context): The context of the change.
+//   - id (string): The id of the new node.
+//   - address (string): The address that the other nodes connect to.
+//   - api (string): The api address of the new node. Can be empty.
+//
+// Returns:
+//   - error: A *NotLeaderError or ErrNoLeader if the node isn't the leader, or the error of the change.
+func (n *Node) Join(ctx context.Context, id, address, api string) error {
+	if len(id) == 0 || len(address) == 0 {
+		return errors.New("invalid node id or address")
+	} else if err := n.leader(); err != nil {
+		return err
+	}
+
+	// Add the node, unless it's already a voter with the same address
+	var servers, err = n.configuration(ctx)
+	if err != nil {
+		return err
+	}
+	var exists bool
+	for _, s := range servers {
+		if s.ID == raft.ServerID(id) && s.Address == raft.ServerAddress(address) && s.Suffrage == raft.Voter {
+			exists = true
+		}
+	}
+	if !exists {
+		if err := wait(ctx, n.raft.AddVoter(raft.ServerID(id), raft.ServerAddress(address), 0, timeout(ctx))); err != nil {
+			return n.raftError(err)
+		}
+	}
+	if len(api) > 0 {
+		return n.Apply(ctx, Command{Op: OpMember, ID: id, Address: api})
+	}
+	return nil
+}
+
+// Leave is a method of the Node struct that removes a node from the cluster. It must be called on the leader.
+// Parameters:
+//   - ctx (context.Context): The context of the change.
+//   - id (string): The id of the node.
+//
+// Returns:
+//   - error: A *NotLeaderError or ErrNoLeader if the node isn't the leader, or the error of the change.
+func (n *Node) Leave(ctx context.Context, id string) error {
+	if err := n.leader(); err != nil {
+		return err
+	} else if err := wait(ctx, n.raft.RemoveServer(raft.ServerID(id), 0, timeout(ctx))); err != nil {
+		return n.raftError(err)
+	} else if id == n.id {
+		// The leader can't write after it removed itself
+		return nil
+	}
+	return n.Apply(ctx, Command{Op: OpMemberRemove, ID: id})
+}
+
+// Servers is a method of the Node struct that returns the members of the cluster.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - []Server: The members.
+//   - error: If the configuration of the cluster can't be read.
+func (n *Node) Servers(ctx context.Context) ([]Server, error) {
+	var servers, err = n.configuration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var leader, _ = n.Leader()
+	var result []Server = make([]Server, 0, len(servers))
+	for _, s := range servers {
+		result = append(result, Server{
+			ID:      string(s.ID),
+			Address: string(s.Address),
+			API:     n.fsm.member(string(s.ID)),
+			Voter:   s.Suffrage == raft.Voter,
+			Leader:  string(s.ID) == leader,
+		})
+	}
+	return result, nil
+}
+
+// Status is a method of the Node struct that returns the state of the node.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - Status: The state of the node.
+//   - error: If the members of the cluster can't be read.
+func (n *Node) Status(ctx context.Context) (Status, error) {
+	var (
+		stats     map[string]string = n.raft.Stats()
+		leader, _                   = n.Leader()
+		number                      = func(name string) uint64 {
+			var v, _ = strconv.ParseUint(stats[name], 10, 64)
+			return v
+		}
+	)
+	var servers, err = n.Servers(ctx)
+	return Status{
+		ID:            n.id,
+		State:         n.raft.State().String(),
+		Leader:        leader,
+		Term:          number("term"),
+		LastIndex:     n.raft.LastIndex(),
+		CommitIndex:   number("commit_index"),
+		AppliedIndex:  n.raft.AppliedIndex(),
+		SnapshotIndex: number("last_snapshot_index"),
+		Servers:       servers,
+	}, err
+}
+
+// Snapshot is a method of the Node struct that takes a snapshot of the collections, and compacts the log before it.
+// Returns:
+//   - error: If the snapshot can't be taken, for example because there are no new log entries.
+func (n *Node) Snapshot() error {
+	return n.raft.Snapshot().Error()
+}
+
+// Shutdown is a method of the Node struct that stops the node, and closes its stores.
+// The node stays a member of the cluster, so it can restart with the same id and directory.
+// Returns:
+//   - error: If the node can't be stopped.
+func (n *Node) Shutdown() error {
+	var err error
+	n.once.Do(func() {
+		close(n.done)
+		err = n.raft.Shutdown().Error()
+		n.close()
+	})
+	return err
+}
+
+// close is a method of the Node struct that closes the stores and the transport.
+func (n *Node) close() {
+	for _, c := range n.closers {
+		_ = c.Close()
+	}
+}
+
+// leader is a method of the Node struct that checks whether the node is the leader.
+// Returns:
+//   - error: A *NotLeaderError or ErrNoLeader if it's not, or nil.
+func (n *Node) leader() error {
+	if n.IsLeader() {
+		return nil
+	} else if id, api := n.Leader(); len(id) > 0 {
+		return &NotLeaderError{ID: id, Address: api}
+	}
+	return ErrNoLeader
+}
+
+// raftError is a method of the Node struct that converts the errors of the leadership into the errors of the package.
+// Parameters:
+//   - err (error): The error of a future.
+//
+// Returns:
+//   - error: A *NotLeaderError or ErrNoLeader if the node lost the leadership, or the error.
+func (n *Node) raftError(err error) error {
+	if errors.Is(err, raft.ErrNotLeader) || errors.Is(err, raft.ErrLeadershipLost) || errors.Is(err, raft.ErrLeadershipTransferInProgress) {
+		if err := n.leader(); err != nil {
+			return err
+		}
+		return ErrNoLeader
+	}
+	return err
+}
+
+// configuration is a method of the Node struct that reads the members of the cluster.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - []raft.Server: The members.
+//   - error: If the configuration can't be read.
+func (n *Node) configuration(ctx context.Context) ([]raft.Server, error) {
+	var f raft.ConfigurationFuture = n.raft.GetConfiguration()
+	if err := wait(ctx, f); err != nil {
+		return nil, err
+	}
+	return f.Configuration().Servers, nil
+}
+
+// register is a method of the Node struct that registers the api address of the node each time it becomes
+// the leader, so that the other nodes can forward the requests to it. It stops once the node is shut down.
+func (n *Node) register() {
+	if len(n.api) == 0 {
+		return
+	}
+	for {
+		select {
+		case leader := <-n.raft.LeaderCh():
+			if leader && n.fsm.member(n.id) != n.api {
+				var ctx, cancel = context.WithTimeout(context.Background(), DefaultTimeout)
+				_ = n.Apply(ctx, Command{Op: OpMember, ID: n.id, Address: n.api})
+				cancel()
+			}
+		case <-n.done:
+			return
+		}
+	}
+}
+
+// timeout is a function that gets how long a future can wait to be started.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - time.Duration: The time until the deadline of the context, or DefaultTimeout.
+func timeout(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+	return DefaultTimeout
+}
+
+// wait is a function that waits for a future, until the context is done.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - f (raft.Future): The future.
+//
+// Returns:
+//   - error: The error of the future, or of the context.
+func wait(ctx context.Context, f raft.Future) error {
+	var done chan error = make(chan error, 1)
+	go func() {
+		done <- f.Error()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("the request was not completed: %w", ctx.Err())
+	}
+}
+
+// logLevel is a function that converts the name of a log level.
+// Parameters:
+//   - name (string): The name of the level. Empty for warn.
+//
+// Returns:
+//   - hclog.Level: The level.
+func logLevel(name string) hclog.Level {
+	if len(name) == 0 {
+		return hclog.Warn
+	} else if level := hclog.LevelFromString(name); level != hclog.NoLevel {
+		return level
+	}
+	return hclog.Warn
+}
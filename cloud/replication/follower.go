@@ -0,0 +1,427 @@
+package replication
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// Config is a struct that represents the settings of a follower.
+type Config struct {
+	// The url of the leader, like http://localhost:3000
+	URL string
+	// The credential that is sent to the leader as a bearer token. It needs the admin scope. Empty if the leader has no authentication
+	Token string
+	// The tls settings of the connection to the leader, for example its CAs. If nil, the default settings are used
+	TLS *tls.Config
+	// How long the follower waits before it connects to the leader again. Defaults to a second
+	Retry time.Duration
+	// How long the follower waits for an entry before it gives up on the connection. Defaults to five heartbeats
+	Timeout time.Duration
+	// A function called when the connection to the leader fails or ends. If nil, the errors aren't reported
+	OnError func(err error)
+}
+
+// Stats is a struct that represents the state of the replication of a follower.
+type Stats struct {
+	// Whether the follower is connected to the leader
+	Connected bool
+	// Whether every collection of the leader was received once
+	Synced bool
+	// When the last heartbeat of the leader was received. Zero if none was received
+	LastHeartbeat time.Time
+	// The number of changes of each collection that the leader has made and the follower hasn't applied yet
+	Lag map[string]uint64
+	// The number of snapshots of collections that were applied
+	Snapshots uint64
+	// The number of changes that were applied
+	Changes uint64
+	// The number of times the follower connected to the leader
+	Connects uint64
+}
+
+// Follower is a struct that applies the replication stream of a leader to the collections.
+// Fields:
+//   - collections (*hermes.Collections): The collections that the changes are applied to.
+//   - config (Config): The settings of the follower.
+//   - endpoint (string): The url of the replication stream of the leader.
+//   - client (*http.Client): The client of the leader.
+//   - mutex (sync.Mutex): The mutex that guards the positions and the stats.
+//   - positions (map[string]Position): The positions of the collections.
+//   - pending (map[string]*hermes.Cache): The snapshots that are being received, mapped by collection name.
+//   - seqs (map[string]uint64): The sequence numbers of the collections in the last heartbeat.
+//   - leader ([]string): The names of the collections of the leader in the hello entry, to know when the follower is synced.
+//   - stats (Stats): The stats of the follower, without the lag.
+type Follower struct {
+	collections *hermes.Collections
+	config      Config
+	endpoint    string
+	client      *http.Client
+	mutex       sync.Mutex
+	positions   map[string]Position
+	pending     map[string]*hermes.Cache
+	seqs        map[string]uint64
+	leader      []string
+	stats       Stats
+}
+
+// NewFollower is a function that creates a follower of a leader.
+// Parameters:
+//   - cs (*hermes.Collections): The collections that the changes of the leader are applied to.
+//     Their contents are replaced with the collections of the leader.
+//   - config (Config): The settings of the follower.
+//
+// Returns:
+//   - *Follower: The follower. Its Run method follows the leader.
+//   - error: If the url of the leader is invalid.
+func NewFollower(cs *hermes.Collections, config Config) (*Follower, error) {
+	var u, err = url.Parse(config.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return nil, fmt.Errorf("invalid leader url %q", config.URL)
+	}
+	if config.Retry <= 0 {
+		config.Retry = time.Second
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * HeartbeatInterval
+	}
+	var transport *http.Transport = http.DefaultTransport.(*http.Transport).Clone()
+	if config.TLS != nil {
+		transport.TLSClientConfig = config.TLS
+	}
+	return &Follower{
+		collections: cs,
+		config:      config,
+		endpoint:    strings.TrimSuffix(config.URL, "/") + Path,
+		client:      &http.Client{Transport: transport},
+		positions:   make(map[string]Position),
+		pending:     make(map[string]*hermes.Cache),
+		seqs:        make(map[string]uint64),
+	}, nil
+}
+
+// Run is a method of the Follower struct that follows the leader until the context is done.
+// When the connection fails or ends, the follower connects again, and resumes from its positions.
+// Parameters:
+//   - ctx (context.Context): The context of the follower.
+func (f *Follower) Run(ctx context.Context) {
+	for {
+		var err error = f.follow(ctx)
+		f.mutex.Lock()
+		f.stats.Connected = false
+		f.pending = make(map[string]*hermes.Cache)
+		f.mutex.Unlock()
+		if ctx.Err() != nil {
+			return
+		} else if f.config.OnError != nil {
+			f.config.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.config.Retry):
+		}
+	}
+}
+
+// Stats is a method of the Follower struct that returns the state of the replication.
+// Returns:
+//   - Stats: The stats, with the lag of each collection of the last heartbeat.
+func (f *Follower) Stats() Stats {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	var stats Stats = f.stats
+	stats.Lag = make(map[string]uint64, len(f.seqs))
+	for name, seq := range f.seqs {
+		if p, ok := f.positions[name]; !ok {
+			stats.Lag[name] = seq
+		} else if seq > p.Seq {
+			stats.Lag[name] = seq - p.Seq
+		} else {
+			stats.Lag[name] = 0
+		}
+	}
+	return stats
+}
+
+// Synced is a method of the Follower struct that returns whether every collection of the leader was received once.
+// Returns:
+//   - bool: Whether the follower has the data of the leader.
+func (f *Follower) Synced() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.stats.Synced
+}
+
+// follow is a method of the Follower struct that connects to the leader and applies its stream, until it ends.
+// Parameters:
+//   - ctx (context.Context): The context of the follower.
+//
+// Returns:
+//   - error: The reason the stream ended.
+func (f *Follower) follow(ctx context.Context) error {
+	f.mutex.Lock()
+	var req Request = Request{Positions: make(map[string]Position, len(f.positions))}
+	for name, p := range f.positions {
+		req.Positions[name] = p
+	}
+	f.mutex.Unlock()
+	var body, err = json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	// Send the request. The connection is closed if no entry is received for the timeout
+	var rctx, cancel = context.WithCancel(ctx)
+	defer cancel()
+	var watchdog *time.Timer = time.AfterFunc(f.config.Timeout, cancel)
+	defer watchdog.Stop()
+	var r *http.Request
+	if r, err = http.NewRequestWithContext(rctx, http.MethodPost, f.endpoint, bytes.NewReader(body)); err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	if len(f.config.Token) > 0 {
+		r.Header.Set("Authorization", "Bearer "+f.config.Token)
+	}
+	var resp *http.Response
+	if resp, err = f.client.Do(r); err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("the leader responded with %s: %s", resp.Status, e.Error)
+	}
+	f.mutex.Lock()
+	f.stats.Connected = true
+	f.stats.Connects++
+	f.mutex.Unlock()
+
+	// Apply the entries
+	var dec *json.Decoder = json.NewDecoder(resp.Body)
+	for {
+		var e Entry
+		if err := dec.Decode(&e); err != nil {
+			if rctx.Err() != nil && ctx.Err() == nil {
+				return errors.New("the leader stopped responding")
+			}
+			return err
+		}
+		watchdog.Reset(f.config.Timeout)
+		if err := f.apply(e); err != nil {
+			// Send the collection as a snapshot again when the follower connects again
+			f.mutex.Lock()
+			delete(f.positions, e.Collection)
+			f.mutex.Unlock()
+			return fmt.Errorf("collection %s: %w", e.Collection, err)
+		}
+	}
+}
+
+// apply is a method of the Follower struct that applies an entry of the stream.
+// Parameters:
+//   - e (Entry): The entry.
+//
+// Returns:
+//   - error: If the change could not be applied, so that the collection is different from the collection of the leader.
+func (f *Follower) apply(e Entry) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	switch e.Op {
+	case OpHello:
+		return f.hello(e.Collections)
+	case OpHeartbeat:
+		f.seqs = e.Seqs
+		f.stats.LastHeartbeat = time.Now()
+		return nil
+	case OpDrop:
+		delete(f.positions, e.Collection)
+		delete(f.pending, e.Collection)
+		if e.Collection != hermes.DefaultCollection {
+			_ = f.collections.Drop(e.Collection)
+		}
+		return nil
+	case OpSnapshot:
+		return f.begin(e)
+	case OpRecord:
+		if c, ok := f.pending[e.Collection]; ok {
+			return c.Set(e.Key, e.Value)
+		}
+		return nil
+	case OpSynced:
+		return f.commit(e)
+	}
+
+	// Apply the change to the collection
+	var p, ok = f.positions[e.Collection]
+	if !ok {
+		return nil
+	}
+	var c, err = f.collections.Get(e.Collection)
+	if err != nil {
+		return err
+	} else if err = replay(c, e); err != nil {
+		return err
+	}
+	p.Seq = e.Seq
+	f.positions[e.Collection] = p
+	f.stats.Changes++
+	return nil
+}
+
+// hello is a method of the Follower struct that drops the collections that the leader doesn't have.
+// This method is not thread-safe, and should only be called while the follower is locked.
+// Parameters:
+//   - names ([]string): The names of the collections of the leader.
+//
+// Returns:
+//   - error: Always nil.
+func (f *Follower) hello(names []string) error {
+	var exists map[string]bool = make(map[string]bool, len(names))
+	for _, name := range names {
+		exists[name] = true
+	}
+	for _, name := range f.collections.List() {
+		if !exists[name] && name != hermes.DefaultCollection {
+			_ = f.collections.Drop(name)
+		}
+	}
+	for name := range f.positions {
+		if !exists[name] {
+			delete(f.positions, name)
+		}
+	}
+	f.leader = names
+	return nil
+}
+
+// begin is a method of the Follower struct that starts to receive the snapshot of a collection in a new cache.
+// This method is not thread-safe, and should only be called while the follower is locked.
+// Parameters:
+//   - e (Entry): The snapshot entry, with the full-text settings and the schema of the collection.
+//
+// Returns:
+//   - error: If the full-text index could not be initialized, or the schema is invalid.
+func (f *Follower) begin(e Entry) error {
+	var c *hermes.Cache = hermes.InitCache()
+	if e.Value != nil {
+		var maxSize, maxBytes, minWordLength int = settings(e.Value)
+		if err := c.FTInit(maxSize, maxBytes, minWordLength); err != nil {
+			return err
+		}
+	}
+	if e.Schema != nil {
+		if err := c.SetSchema(e.Schema); err != nil {
+			return err
+		}
+	}
+	f.pending[e.Collection] = c
+	f.positions[e.Collection] = Position{ID: e.ID}
+	return nil
+}
+
+// commit is a method of the Follower struct that replaces a collection with its snapshot, once it's received.
+// This method is not thread-safe, and should only be called while the follower is locked.
+// Parameters:
+//   - e (Entry): The synced entry, with the sequence number of the snapshot.
+//
+// Returns:
+//   - error: If the collection could not be replaced.
+func (f *Follower) commit(e Entry) error {
+	var c, ok = f.pending[e.Collection]
+	if !ok {
+		return nil
+	}
+	delete(f.pending, e.Collection)
+	if err := f.collections.Replace(e.Collection, c); err != nil {
+		return err
+	}
+	f.positions[e.Collection] = Position{ID: f.positions[e.Collection].ID, Seq: e.Seq}
+	f.stats.Snapshots++
+
+	// The follower is synced once it has every collection of the leader
+	if !f.stats.Synced {
+		f.stats.Synced = true
+		for _, name := range f.leader {
+			var _, received = f.positions[name]
+			if _, pending := f.pending[name]; pending || !received {
+				f.stats.Synced = false
+			}
+		}
+	}
+	return nil
+}
+
+// replay is a function that applies a change of the leader to a collection.
+// The changes that were made while the snapshot of the collection was sent are applied again,
+// so the records are replaced instead of set, and the full-text index is only initialized once.
+// Parameters:
+//   - c (*hermes.Cache): The collection.
+//   - e (Entry): The change.
+//
+// Returns:
+//   - error: If the change could not be applied.
+func replay(c *hermes.Cache, e Entry) error {
+	switch e.Op {
+	case OpSet:
+		return c.Replace(e.Key, e.Value)
+	case OpDelete:
+		c.Delete(e.Key)
+	case OpClean:
+		c.Clean()
+	case OpFTClean:
+		if c.FTIsInitialized() {
+			return c.FTClean()
+		}
+	case OpFTInit, OpFTSettings:
+		var maxSize, maxBytes, minWordLength int = settings(e.Value)
+		if !c.FTIsInitialized() {
+			return c.FTInit(maxSize, maxBytes, minWordLength)
+		}
+		return ftSettings(c, maxSize, maxBytes, minWordLength)
+	case OpSchema:
+		return c.SetSchema(e.Schema)
+	}
+	return nil
+}
+
+// ftSettings is a function that sets the full-text settings of a collection. The limits are raised before
+// the minimum word length is lowered, so that the shorter words fit, and lowered after it's raised.
+// Parameters:
+//   - c (*hermes.Cache): The collection.
+//   - maxSize (int): The maximum number of words.
+//   - maxBytes (int): The maximum size, in bytes.
+//   - minWordLength (int): The minimum word length.
+//
+// Returns:
+//   - error: If a setting could not be set.
+func ftSettings(c *hermes.Cache, maxSize, maxBytes, minWordLength int) error {
+	var _, _, current, err = c.FTSettings()
+	if err != nil {
+		return err
+	} else if minWordLength > current {
+		if err := c.FTSetMinWordLength(minWordLength); err != nil {
+			return err
+		}
+	}
+	if err := c.FTSetMaxSize(maxSize); err != nil {
+		return err
+	} else if err := c.FTSetMaxBytes(maxBytes); err != nil {
+		return err
+	}
+	return c.FTSetMinWordLength(minWordLength)
+}
@@ -0,0 +1,364 @@
+package replication
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	hermes "github.com/realTristan/hermes"
+)
+
+// watchBufferSize is the size of the channel of the changes of each collection of a stream.
+// If a follower falls further behind, its changes are read again from the buffered events
+// of the cache, or the collection is sent as a snapshot again.
+const watchBufferSize int = 16384
+
+// Leader is a struct that streams the changes of the collections to the followers.
+// Fields:
+//   - collections (*hermes.Collections): The collections that are replicated.
+//   - mutex (sync.Mutex): The mutex that guards the ids and the followers.
+//   - ids (map[*hermes.Cache]string): The ids of the caches of the collections, given when they're first streamed.
+//   - followers (int): The number of followers that are streamed to.
+//   - done (chan struct{}): A channel that's closed by Close, to end the streams.
+//   - closed (bool): Whether Close was called.
+type Leader struct {
+	collections *hermes.Collections
+	mutex       sync.Mutex
+	ids         map[*hermes.Cache]string
+	followers   int
+	done        chan struct{}
+	closed      bool
+}
+
+// NewLeader is a function that creates the leader of the collections.
+// Parameters:
+//   - cs (*hermes.Collections): The collections that are replicated.
+//
+// Returns:
+//   - *Leader: The leader. Its Handler serves the replication stream.
+func NewLeader(cs *hermes.Collections) *Leader {
+	return &Leader{
+		collections: cs,
+		ids:         make(map[*hermes.Cache]string),
+		done:        make(chan struct{}),
+	}
+}
+
+// Handler is a method of the Leader struct that serves the replication stream of a follower.
+// The request body is a Request, and the response is a stream of newline-delimited json Entries
+// that lasts until the follower disconnects or the leader is closed.
+// Parameters:
+//   - ctx (*fiber.Ctx): A pointer to a fiber context.
+//
+// Returns:
+//   - error: The error of the response, if any.
+func (l *Leader) Handler(ctx *fiber.Ctx) error {
+	var req Request
+	if len(ctx.Body()) > 0 {
+		if err := json.Unmarshal(ctx.Body(), &req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"success": false, "error": "invalid request body"})
+		}
+	}
+	ctx.Set(fiber.HeaderContentType, "application/x-ndjson")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		l.stream(req.Positions, w)
+	})
+	return nil
+}
+
+// Followers is a method of the Leader struct that returns the number of followers that are streamed to.
+// Returns:
+//   - int: The number of open streams.
+func (l *Leader) Followers() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.followers
+}
+
+// Close is a method of the Leader struct that ends the streams, so that the server can shut down.
+// The followers reconnect once the server is back.
+func (l *Leader) Close() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.closed {
+		l.closed = true
+		close(l.done)
+	}
+}
+
+// id is a method of the Leader struct that gets the id of the cache of a collection.
+// Parameters:
+//   - c (*hermes.Cache): The cache.
+//
+// Returns:
+//   - string: The id of the cache. A random id is given to the caches that weren't streamed before.
+func (l *Leader) id(c *hermes.Cache) string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if id, ok := l.ids[c]; ok {
+		return id
+	}
+	var b []byte = make([]byte, 8)
+	_, _ = rand.Read(b)
+	l.ids[c] = hex.EncodeToString(b)
+	return l.ids[c]
+}
+
+// prune is a method of the Leader struct that forgets the ids of the caches that were dropped or replaced.
+func (l *Leader) prune() {
+	var current map[*hermes.Cache]bool = make(map[*hermes.Cache]bool)
+	for _, name := range l.collections.List() {
+		if c, err := l.collections.Get(name); err == nil {
+			current[c] = true
+		}
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for c := range l.ids {
+		if !current[c] {
+			delete(l.ids, c)
+		}
+	}
+}
+
+// count is a method of the Leader struct that adds or removes a follower.
+// Parameters:
+//   - delta (int): 1 when a stream starts, and -1 when it ends.
+func (l *Leader) count(delta int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.followers += delta
+}
+
+// follower is a struct that represents the stream of a collection to a follower.
+// Fields:
+//   - cache (*hermes.Cache): The cache of the collection.
+//   - cancel (context.CancelFunc): The function that stops the stream of the collection.
+//   - done (chan struct{}): A channel that's closed when the stream of the collection has stopped.
+type follower struct {
+	cache  *hermes.Cache
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// message is a struct that represents an entry that is written to the stream, with the stream of the collection it comes from.
+// Fields:
+//   - from (*follower): The stream of the collection. The entries of the collections that were dropped are skipped.
+//   - entry (Entry): The entry.
+type message struct {
+	from  *follower
+	entry Entry
+}
+
+// stream is a method of the Leader struct that writes the replication stream of a follower, until a write fails or the leader is closed.
+// Each collection is streamed by its own goroutine, and the collections that are created or dropped are noticed at each heartbeat.
+// Parameters:
+//   - positions (map[string]Position): The positions of the collections of the follower.
+//   - w (*bufio.Writer): The writer of the response body.
+func (l *Leader) stream(positions map[string]Position, w *bufio.Writer) {
+	var ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-l.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	l.count(1)
+	defer l.count(-1)
+
+	// Stop the streams of the collections when the stream ends
+	var (
+		enc       *json.Encoder        = json.NewEncoder(w)
+		messages  chan message         = make(chan message, 1024)
+		followers map[string]*follower = make(map[string]*follower)
+	)
+	defer func() {
+		for _, f := range followers {
+			f.cancel()
+			<-f.done
+		}
+	}()
+
+	// Start the streams of the new collections, and stop the streams of the dropped ones
+	var reconcile = func() error {
+		var names []string = l.collections.List()
+		var current map[string]*hermes.Cache = make(map[string]*hermes.Cache, len(names))
+		for _, name := range names {
+			if c, err := l.collections.Get(name); err == nil {
+				current[name] = c
+			}
+		}
+		for name, f := range followers {
+			if c, ok := current[name]; !ok || c != f.cache {
+				f.cancel()
+				<-f.done
+				delete(followers, name)
+				if !ok {
+					if err := enc.Encode(Entry{Op: OpDrop, Collection: name}); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		for name, c := range current {
+			if _, ok := followers[name]; !ok {
+				var fctx, fcancel = context.WithCancel(ctx)
+				var f *follower = &follower{cache: c, cancel: fcancel, done: make(chan struct{})}
+				followers[name] = f
+				go func(name string, from Position) {
+					defer close(f.done)
+					l.follow(fctx, name, f, from, messages)
+				}(name, positions[name])
+				delete(positions, name)
+			}
+		}
+		return nil
+	}
+
+	// Write the hello entry, then the entries of the collections and the heartbeats
+	if err := enc.Encode(Entry{Op: OpHello, Collections: l.collections.List()}); err != nil {
+		return
+	} else if err := reconcile(); err != nil {
+		return
+	} else if err := w.Flush(); err != nil {
+		return
+	}
+	var ticker *time.Ticker = time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case m := <-messages:
+			if followers[m.entry.Collection] == m.from {
+				err = enc.Encode(m.entry)
+			}
+			if err == nil && len(messages) == 0 {
+				err = w.Flush()
+			}
+		case <-ticker.C:
+			l.prune()
+			if err = reconcile(); err == nil {
+				var seqs map[string]uint64 = make(map[string]uint64, len(followers))
+				for name, f := range followers {
+					seqs[name] = f.cache.Seq()
+				}
+				if err = enc.Encode(Entry{Op: OpHeartbeat, Seqs: seqs, Time: time.Now().UnixMilli()}); err == nil {
+					err = w.Flush()
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// follow is a method of the Leader struct that streams the changes of a collection, until the context is done.
+// The collection is sent as a snapshot first, unless the changes after the position of the follower are still
+// buffered by the cache. The changes made while the snapshot is sent are streamed after it, so some of them can
+// already be in the snapshot, and applying them again leaves the records of the follower unchanged.
+// Parameters:
+//   - ctx (context.Context): The context of the stream of the collection.
+//   - name (string): The name of the collection.
+//   - f (*follower): The stream of the collection.
+//   - from (Position): The position of the follower.
+//   - messages (chan<- message): The channel of the entries that are written to the stream.
+func (l *Leader) follow(ctx context.Context, name string, f *follower, from Position, messages chan<- message) {
+	var send = func(e Entry) bool {
+		select {
+		case messages <- message{from: f, entry: e}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	var (
+		id       string = l.id(f.cache)
+		seq      uint64 = from.Seq
+		snapshot bool   = from.ID != id || from.Seq == 0 || from.Seq > f.cache.Seq()
+	)
+	for ctx.Err() == nil {
+		if snapshot {
+			seq = f.cache.Seq()
+		}
+
+		// Watch the changes before the snapshot is read, so that none are missed
+		var wctx, wcancel = context.WithCancel(ctx)
+		var events <-chan hermes.Event = f.cache.Watch(wctx, hermes.WatchFilter{From: seq, Buffer: watchBufferSize})
+		if snapshot && !l.snapshot(name, id, f.cache, seq, send) {
+			wcancel()
+			return
+		}
+
+		// Stream the changes. If the changes after the position are no longer buffered, the
+		// collection is sent as a snapshot again. If the channel was closed because it was
+		// full, the changes are watched again from the last one that was sent
+		snapshot = false
+		for e := range events {
+			if e.Type == hermes.EventLost {
+				snapshot = true
+				break
+			} else if !send(change(name, e)) {
+				break
+			}
+			seq = e.Seq
+		}
+		wcancel()
+	}
+}
+
+// snapshot is a method of the Leader struct that sends the full-text settings, the schema and the records of a collection.
+// Parameters:
+//   - name (string): The name of the collection.
+//   - id (string): The id of the cache of the collection.
+//   - c (*hermes.Cache): The cache of the collection.
+//   - seq (uint64): The sequence number of the last change before the snapshot.
+//   - send (func(Entry) bool): The function that sends the entries. It returns false once the stream has stopped.
+//
+// Returns:
+//   - bool: Whether the snapshot was sent.
+func (l *Leader) snapshot(name, id string, c *hermes.Cache, seq uint64, send func(Entry) bool) bool {
+	var ft map[string]any
+	if maxSize, maxBytes, minWordLength, err := c.FTSettings(); err == nil {
+		ft = map[string]any{"max_size": maxSize, "max_bytes": maxBytes, "min_word_length": minWordLength}
+	}
+	if !send(Entry{Op: OpSnapshot, Collection: name, ID: id, Seq: seq, Value: ft, Schema: c.GetSchema()}) {
+		return false
+	}
+	for _, key := range c.Keys() {
+		if record := c.Get(key); record != nil && !send(Entry{Op: OpRecord, Collection: name, Key: key, Value: hermes.WrapRecord(record, c.Fields(key))}) {
+			return false
+		}
+	}
+	return send(Entry{Op: OpSynced, Collection: name, Seq: seq})
+}
+
+// change is a function that converts a change event of a cache into an entry.
+// Parameters:
+//   - name (string): The name of the collection.
+//   - e (hermes.Event): The event.
+//
+// Returns:
+//   - Entry: The entry of the change.
+func change(name string, e hermes.Event) Entry {
+	var entry Entry = Entry{Op: Op(e.Type), Collection: name, Seq: e.Seq, Key: e.Key}
+	switch e.Type {
+	case hermes.EventSet:
+		entry.Value = hermes.WrapRecord(e.Value, e.Fields)
+	case hermes.EventFTInit, hermes.EventFTSettings:
+		entry.Value = e.Value
+	case hermes.EventSchema:
+		entry.Schema, _ = e.Value["schema"].(*hermes.Schema)
+	}
+	return entry
+}
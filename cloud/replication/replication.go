@@ -0,0 +1,110 @@
+package replication
+
+import (
+	"time"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// Path is the path of the replication stream on the leader. The follower sends a Request
+// in a POST body, and the leader responds with a stream of newline-delimited json Entries.
+const Path string = "/replication/sync"
+
+// HeartbeatInterval is how often the leader sends a heartbeat, so that the followers
+// can measure their lag and notice that the leader is gone.
+const HeartbeatInterval time.Duration = time.Second
+
+// Op is the operation of an entry of the replication stream.
+type Op string
+
+// The operations of the entries. The change operations have the names of the event types of the cache.
+const (
+	// The first entry of the stream. It lists the collections of the leader, so that the
+	// follower can drop the collections that were dropped while it was disconnected
+	OpHello Op = "hello"
+	// The start of the snapshot of a collection, with its full-text settings and schema
+	OpSnapshot Op = "snapshot"
+	// A record of the snapshot of a collection, with its full-text fields wrapped, including the ones that aren't stored
+	OpRecord Op = "record"
+	// The end of the snapshot of a collection. The follower replaces its collection with the snapshot
+	OpSynced Op = "synced"
+	// A record was set, with its full-text fields wrapped, including the ones that aren't stored
+	OpSet Op = Op(hermes.EventSet)
+	// A record was deleted
+	OpDelete Op = Op(hermes.EventDelete)
+	// The collection was cleaned
+	OpClean Op = Op(hermes.EventClean)
+	// The full-text index was initialized, with its settings
+	OpFTInit Op = Op(hermes.EventFTInit)
+	// The full-text index was cleaned
+	OpFTClean Op = Op(hermes.EventFTClean)
+	// The full-text settings were changed
+	OpFTSettings Op = Op(hermes.EventFTSettings)
+	// The schema was set or removed
+	OpSchema Op = Op(hermes.EventSchema)
+	// The collection was dropped
+	OpDrop Op = "drop"
+	// The latest sequence numbers of the collections of the leader, sent every HeartbeatInterval
+	OpHeartbeat Op = "heartbeat"
+)
+
+// Position is a struct that represents how far a follower has applied the changes of a collection.
+type Position struct {
+	// The id that the leader gave to the cache of the collection. A collection that was dropped
+	// and created again, or a leader that restarted, has a new id, so the position is reset
+	ID string `json:"id"`
+	// The sequence number of the last change that was applied
+	Seq uint64 `json:"seq"`
+}
+
+// Request is a struct that represents the body of the request of a follower.
+type Request struct {
+	// The positions of the collections of the follower, mapped by name. The collections that have
+	// no position, or whose position is no longer buffered by the leader, are sent as snapshots
+	Positions map[string]Position `json:"positions"`
+}
+
+// Entry is a struct that represents an entry of the replication stream.
+type Entry struct {
+	// The operation of the entry
+	Op Op `json:"op"`
+	// The name of the collection. Empty for the hello and heartbeat entries
+	Collection string `json:"collection,omitempty"`
+	// The id of the cache of the collection, for the snapshot entries
+	ID string `json:"id,omitempty"`
+	// The sequence number of the change, or the sequence number that a snapshot was started at
+	Seq uint64 `json:"seq,omitempty"`
+	// The key of the record, for the record, set and delete entries
+	Key string `json:"key,omitempty"`
+	// The record, or the full-text settings of the ft.init, ft.settings and snapshot entries
+	Value map[string]any `json:"value,omitempty"`
+	// The schema, for the snapshot and schema entries
+	Schema *hermes.Schema `json:"schema,omitempty"`
+	// The names of the collections of the leader, for the hello entries
+	Collections []string `json:"collections,omitempty"`
+	// The latest sequence numbers of the collections, for the heartbeat entries
+	Seqs map[string]uint64 `json:"seqs,omitempty"`
+	// When the heartbeat was sent, in unix milliseconds
+	Time int64 `json:"time,omitempty"`
+}
+
+// settings is a function that reads the full-text settings of an entry.
+// Parameters:
+//   - value (map[string]any): The settings, as they're published in the ft.init and ft.settings events.
+//
+// Returns:
+//   - int: The maximum number of words.
+//   - int: The maximum size, in bytes.
+//   - int: The minimum word length.
+func settings(value map[string]any) (int, int, int) {
+	var number = func(name string) int {
+		switch n := value[name].(type) {
+		case int:
+			return n
+		case float64:
+			return int(n)
+		}
+		return -1
+	}
+	return number("max_size"), number("max_bytes"), number("min_word_length")
+}
@@ -0,0 +1,488 @@
+package resp
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/cloud/auth"
+)
+
+// command is a struct that represents a command of the server.
+// Fields:
+//   - run (func(*conn, [][]byte) *Error): The implementation of the command. It's called with the arguments after the name.
+//   - arity (int): The number of arguments, with the name, like in redis. Negative for a minimum number of arguments.
+//   - scope (auth.Scope): The scope that is required to run the command. Empty if it doesn't require authentication.
+type command struct {
+	run   func(c *conn, args [][]byte) *Error
+	arity int
+	scope auth.Scope
+}
+
+// commands is the map of the commands of the server, by lowercase name.
+var commands = map[string]command{
+	// Connection
+	"ping":    {ping, -1, ""},
+	"echo":    {echo, 2, auth.ScopeRead},
+	"quit":    {quit, -1, ""},
+	"auth":    {authenticate, -2, ""},
+	"select":  {selectCollection, 2, auth.ScopeRead},
+	"client":  {client, -2, ""},
+	"command": {commandInfo, -1, ""},
+
+	// Keys
+	"get":      {get, 2, auth.ScopeRead},
+	"set":      {set, -3, auth.ScopeWrite},
+	"del":      {del, -2, auth.ScopeWrite},
+	"exists":   {exists, -2, auth.ScopeRead},
+	"type":     {keyType, 2, auth.ScopeRead},
+	"keys":     {keys, 2, auth.ScopeRead},
+	"scan":     {scan, -2, auth.ScopeRead},
+	"dbsize":   {dbsize, 1, auth.ScopeRead},
+	"flushdb":  {flushdb, -1, auth.ScopeAdmin},
+	"flushall": {flushall, -1, auth.ScopeAdmin},
+
+	// Hashes
+	"hset":    {hset, -4, auth.ScopeWrite},
+	"hget":    {hget, 3, auth.ScopeRead},
+	"hgetall": {hgetall, 2, auth.ScopeRead},
+	"hdel":    {hdel, -3, auth.ScopeWrite},
+
+	// Full-text
+	"ft.create": {ftCreate, -2, auth.ScopeAdmin},
+	"ft.search": {ftSearch, -3, auth.ScopeRead},
+}
+
+// errSyntax is the error of the invalid options of a command.
+var errSyntax = errorf("syntax error")
+
+// ping is a function that implements PING [message].
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply, or nil if successful.
+func ping(c *conn, args [][]byte) *Error {
+	switch len(args) {
+	case 0:
+		c.w.simple("PONG")
+	case 1:
+		c.w.bulk(string(args[0]))
+	default:
+		return errorf("wrong number of arguments for 'ping' command")
+	}
+	return nil
+}
+
+// echo is a function that implements ECHO message.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply, or nil if successful.
+func echo(c *conn, args [][]byte) *Error {
+	c.w.bulk(string(args[0]))
+	return nil
+}
+
+// quit is a function that implements QUIT. The connection is closed after the reply.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: Always nil.
+func quit(c *conn, args [][]byte) *Error {
+	c.quit = true
+	c.w.simple("OK")
+	return nil
+}
+
+// authenticate is a function that implements AUTH [username] credential. The username is ignored,
+// and the credential is an api key or a token of the authenticator of the server.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply if authentication is disabled or the credential is invalid, or nil if successful.
+func authenticate(c *conn, args [][]byte) *Error {
+	if len(args) > 2 {
+		return errSyntax
+	} else if c.server.config.Auth == nil {
+		return errorf("AUTH called without any authentication configured")
+	}
+	var id, err = c.server.config.Auth.Authenticate(string(args[len(args)-1]))
+	if err != nil {
+		return &Error{Code: "WRONGPASS", Message: "invalid username-password pair or user is disabled."}
+	}
+	c.identity = id
+	c.w.simple("OK")
+	return nil
+}
+
+// selectCollection is a function that implements SELECT collection. The databases are the
+// collections, by name, and database 0 is the default collection.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply if the collection does not exist, or nil if successful.
+func selectCollection(c *conn, args [][]byte) *Error {
+	var name string = string(args[0])
+	if name == "0" {
+		name = hermes.DefaultCollection
+	}
+	if _, err := c.server.collections.Get(name); err != nil {
+		return errorf(err.Error())
+	}
+	c.collection = name
+	c.w.simple("OK")
+	return nil
+}
+
+// client is a function that implements the CLIENT SETNAME and CLIENT SETINFO commands that the client libraries
+// send when they connect. The names aren't kept.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply for the other subcommands, or nil if successful.
+func client(c *conn, args [][]byte) *Error {
+	switch strings.ToLower(string(args[0])) {
+	case "setname", "setinfo":
+		c.w.simple("OK")
+	case "getname":
+		c.w.null()
+	default:
+		return errorf("unknown subcommand '" + truncate(string(args[0])) + "'")
+	}
+	return nil
+}
+
+// commandInfo is a function that implements COMMAND, which redis-cli sends when it starts.
+// The commands aren't described, so the reply is empty.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: Always nil.
+func commandInfo(c *conn, args [][]byte) *Error {
+	c.w.array(0)
+	return nil
+}
+
+// get is a function that implements GET key. The record is replied as JSON.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply, or nil if successful.
+func get(c *conn, args [][]byte) *Error {
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+	var value map[string]any = cache.Get(string(args[0]))
+	if value == nil {
+		c.w.null()
+		return nil
+	}
+	if data, err := json.Marshal(value); err != nil {
+		return errorf(err.Error())
+	} else {
+		c.w.bulk(string(data))
+	}
+	return nil
+}
+
+// set is a function that implements SET key value [NX | XX]. The value must be a JSON object, which
+// can have full-text fields. The record of the key is replaced, unlike with Cache.Set.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply if the value or the options are invalid, or the record could not be set, or nil if successful.
+func set(c *conn, args [][]byte) *Error {
+	var nx, xx bool
+	for _, opt := range args[2:] {
+		switch strings.ToLower(string(opt)) {
+		case "nx":
+			nx = true
+		case "xx":
+			xx = true
+		case "ex", "px", "exat", "pxat", "keepttl":
+			return errorf("expiration is not supported")
+		default:
+			return errSyntax
+		}
+	}
+	if nx && xx {
+		return errSyntax
+	}
+
+	// Decode the record
+	var value map[string]any
+	if err := json.Unmarshal(args[1], &value); err != nil || value == nil {
+		return errorf("the value must be a JSON object")
+	}
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+
+	// Set the record, unless the condition isn't met
+	var key string = string(args[0])
+	var err error
+	switch {
+	case (nx || xx) && cache.Exists(key) == nx:
+		c.w.null()
+		return nil
+	case nx:
+		err = cache.Set(key, value)
+	default:
+		err = cache.Replace(key, value)
+	}
+	if err != nil {
+		return errorf(err.Error())
+	}
+	c.w.simple("OK")
+	return nil
+}
+
+// del is a function that implements DEL key [key ...].
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply, or nil if successful.
+func del(c *conn, args [][]byte) *Error {
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+	var deleted int
+	for _, key := range args {
+		if cache.Exists(string(key)) {
+			cache.Delete(string(key))
+			deleted++
+		}
+	}
+	c.w.integer(deleted)
+	return nil
+}
+
+// exists is a function that implements EXISTS key [key ...].
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply, or nil if successful.
+func exists(c *conn, args [][]byte) *Error {
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+	var found int
+	for _, key := range args {
+		if cache.Exists(string(key)) {
+			found++
+		}
+	}
+	c.w.integer(found)
+	return nil
+}
+
+// keyType is a function that implements TYPE key. The records are hashes.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply, or nil if successful.
+func keyType(c *conn, args [][]byte) *Error {
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+	if cache.Exists(string(args[0])) {
+		c.w.simple("hash")
+	} else {
+		c.w.simple("none")
+	}
+	return nil
+}
+
+// keys is a function that implements KEYS pattern, with the glob-style patterns of redis.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply, or nil if successful.
+func keys(c *conn, args [][]byte) *Error {
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+	var pattern string = string(args[0])
+	var result []string = []string{}
+	for _, key := range cache.Keys() {
+		if match(pattern, key) {
+			result = append(result, key)
+		}
+	}
+	c.w.strings(result)
+	return nil
+}
+
+// scan is a function that implements SCAN cursor [MATCH pattern] [COUNT count] [TYPE type].
+// The cursor is the position in the sorted keys, so the keys that are set during a scan may be missed.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply if the cursor or the options are invalid, or nil if successful.
+func scan(c *conn, args [][]byte) *Error {
+	var cursor, err = strconv.Atoi(string(args[0]))
+	if err != nil || cursor < 0 {
+		return errorf("invalid cursor")
+	}
+
+	// Read the options
+	var (
+		pattern string = "*"
+		count   int    = 10
+		hashes  bool   = true
+	)
+	for i := 1; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return errSyntax
+		}
+		switch strings.ToLower(string(args[i])) {
+		case "match":
+			pattern = string(args[i+1])
+		case "count":
+			if count, err = strconv.Atoi(string(args[i+1])); err != nil || count < 1 {
+				return errSyntax
+			}
+		case "type":
+			hashes = strings.EqualFold(string(args[i+1]), "hash")
+		default:
+			return errSyntax
+		}
+	}
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+
+	// Get the next keys
+	var (
+		all    []string = cache.Keys()
+		result []string = []string{}
+		next   int      = 0
+	)
+	sort.Strings(all)
+	if cursor < len(all) && hashes {
+		var end int = cursor + count
+		if end < len(all) {
+			next = end
+		} else {
+			end = len(all)
+		}
+		for _, key := range all[cursor:end] {
+			if match(pattern, key) {
+				result = append(result, key)
+			}
+		}
+	}
+	c.w.array(2)
+	c.w.bulk(strconv.Itoa(next))
+	c.w.strings(result)
+	return nil
+}
+
+// dbsize is a function that implements DBSIZE.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply, or nil if successful.
+func dbsize(c *conn, args [][]byte) *Error {
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+	c.w.integer(cache.Length())
+	return nil
+}
+
+// flushdb is a function that implements FLUSHDB [ASYNC | SYNC]. The records are always deleted synchronously.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply, or nil if successful.
+func flushdb(c *conn, args [][]byte) *Error {
+	if !flushMode(args) {
+		return errSyntax
+	}
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+	cache.Clean()
+	c.w.simple("OK")
+	return nil
+}
+
+// flushall is a function that implements FLUSHALL [ASYNC | SYNC]. The records of every collection are deleted.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply, or nil if successful.
+func flushall(c *conn, args [][]byte) *Error {
+	if !flushMode(args) {
+		return errSyntax
+	}
+	for _, name := range c.server.collections.List() {
+		if cache, err := c.server.collections.Get(name); err == nil {
+			cache.Clean()
+		}
+	}
+	c.w.simple("OK")
+	return nil
+}
+
+// flushMode is a function that checks the options of FLUSHDB and FLUSHALL.
+// Parameters:
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - bool: Whether the arguments are empty, or ASYNC or SYNC.
+func flushMode(args [][]byte) bool {
+	switch {
+	case len(args) == 0:
+		return true
+	case len(args) == 1:
+		return strings.EqualFold(string(args[0]), "async") || strings.EqualFold(string(args[0]), "sync")
+	}
+	return false
+}
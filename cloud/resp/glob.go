@@ -0,0 +1,89 @@
+package resp
+
+// match is a function that returns whether a key matches a glob-style pattern, like the KEYS command of redis:
+// * matches any string, ? matches any character, [abc], [^abc] and [a-z] match the characters of the set,
+// and \ escapes the next character.
+// Parameters:
+//   - pattern (string): The pattern.
+//   - s (string): The key.
+//
+// Returns:
+//   - bool: Whether the key matches the pattern.
+func match(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if match(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+			var ok bool
+			if ok, pattern = matchSet(pattern[1:], s[0]); !ok {
+				return false
+			}
+			s = s[1:]
+		default:
+			if pattern[0] == '\\' && len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+		}
+	}
+	return len(s) == 0
+}
+
+// matchSet is a function that matches a character against a set of a pattern, like [abc], [^abc] or [a-z].
+// Parameters:
+//   - pattern (string): The pattern after the opening bracket.
+//   - c (byte): The character.
+//
+// Returns:
+//   - bool: Whether the character is in the set.
+//   - string: The pattern after the closing bracket. An unclosed set ends with the pattern.
+func matchSet(pattern string, c byte) (bool, string) {
+	var negate, found bool
+	if len(pattern) > 0 && pattern[0] == '^' {
+		negate, pattern = true, pattern[1:]
+	}
+	for len(pattern) > 0 && pattern[0] != ']' {
+		switch {
+		case pattern[0] == '\\' && len(pattern) > 1:
+			found = found || pattern[1] == c
+			pattern = pattern[2:]
+		case len(pattern) > 2 && pattern[1] == '-' && pattern[2] != ']':
+			var lo, hi byte = pattern[0], pattern[2]
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			found = found || (c >= lo && c <= hi)
+			pattern = pattern[3:]
+		default:
+			found = found || pattern[0] == c
+			pattern = pattern[1:]
+		}
+	}
+	if len(pattern) > 0 {
+		pattern = pattern[1:]
+	}
+	return found != negate, pattern
+}
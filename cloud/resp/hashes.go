@@ -0,0 +1,183 @@
+package resp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// hset is a function that implements HSET key field value [field value ...]. The fields are set in the record,
+// which is created if it does not exist. The full-text fields stay full-text fields with their new values.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply if the record could not be set, or nil if successful.
+func hset(c *conn, args [][]byte) *Error {
+	if len(args)%2 != 1 {
+		return errorf("wrong number of arguments for 'hset' command")
+	}
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+
+	// Set the fields in a copy of the record
+	var (
+		key   string         = string(args[0])
+		value map[string]any = editable(cache, key)
+		added int
+	)
+	for i := 1; i < len(args); i += 2 {
+		var name string = string(args[i])
+		if wft, ok := value[name].(*hermes.WFT); ok && len(args[i+1]) > 0 {
+			wft.Set(string(args[i+1]))
+		} else if _, ok := value[name]; ok {
+			value[name] = string(args[i+1])
+		} else {
+			value[name] = string(args[i+1])
+			added++
+		}
+	}
+	if err := cache.Replace(key, value); err != nil {
+		return errorf(err.Error())
+	}
+	c.w.integer(added)
+	return nil
+}
+
+// hget is a function that implements HGET key field.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply, or nil if successful.
+func hget(c *conn, args [][]byte) *Error {
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+	if v, ok := cache.Get(string(args[0]))[string(args[1])]; ok {
+		c.w.bulk(fieldValue(v))
+	} else {
+		c.w.null()
+	}
+	return nil
+}
+
+// hgetall is a function that implements HGETALL key. The fields are sorted by name.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply, or nil if successful.
+func hgetall(c *conn, args [][]byte) *Error {
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+	c.w.strings(pairs(cache.Get(string(args[0]))))
+	return nil
+}
+
+// hdel is a function that implements HDEL key field [field ...]. The record is deleted when it has no fields left, like in redis.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply if the record could not be set, or nil if successful.
+func hdel(c *conn, args [][]byte) *Error {
+	var cache, e = c.cache()
+	if e != nil {
+		return e
+	}
+	var (
+		key     string         = string(args[0])
+		value   map[string]any = editable(cache, key)
+		deleted int
+	)
+	for _, name := range args[1:] {
+		if _, ok := value[string(name)]; ok {
+			delete(value, string(name))
+			deleted++
+		}
+	}
+
+	// Set the record without the fields
+	switch {
+	case deleted == 0:
+	case len(value) == 0:
+		cache.Delete(key)
+	default:
+		if err := cache.Replace(key, value); err != nil {
+			return errorf(err.Error())
+		}
+	}
+	c.w.integer(deleted)
+	return nil
+}
+
+// editable is a function that gets a copy of a record, with its full-text fields wrapped, so that it
+// can be modified and set again. The record is read and set separately, so concurrent edits of a record
+// can be lost, and its fields that are indexed but not stored are dropped.
+// Parameters:
+//   - cache (*hermes.Cache): The collection.
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - map[string]any: The copy of the record. Empty if the record does not exist.
+func editable(cache *hermes.Cache, key string) map[string]any {
+	var (
+		record map[string]any          = cache.Get(key)
+		fields map[string]hermes.Field = cache.Fields(key)
+		value  map[string]any          = make(map[string]any, len(record))
+	)
+	for k, v := range record {
+		if s, ok := v.(string); ok && fields[k].FullText {
+			value[k] = cache.WithFT(s).WithAnalyzer(fields[k].Analyzer)
+		} else {
+			value[k] = v
+		}
+	}
+	return value
+}
+
+// pairs is a function that flattens a record into its field names and values, sorted by name.
+// Parameters:
+//   - record (map[string]any): The record. Can be nil.
+//
+// Returns:
+//   - []string: The names and values.
+func pairs(record map[string]any) []string {
+	var names []string = make([]string, 0, len(record))
+	for name := range record {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var result []string = make([]string, 0, 2*len(names))
+	for _, name := range names {
+		result = append(result, name, fieldValue(record[name]))
+	}
+	return result
+}
+
+// fieldValue is a function that formats the value of a field as a string.
+// Parameters:
+//   - v (any): The value.
+//
+// Returns:
+//   - string: The string values as they are, and the other values as JSON.
+func fieldValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	} else if data, err := json.Marshal(v); err == nil {
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,172 @@
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// The limits of the commands, so that a client can't make the server allocate without bounds
+const (
+	maxArgs       int = 1024 * 1024
+	maxBulkLength int = 64 * 1024 * 1024
+	maxInline     int = 16 * 1024
+)
+
+// errProtocol is the error of a malformed command. The connection is closed after it's reported.
+var errProtocol = errors.New("Protocol error")
+
+// reader is a struct that reads the commands of a connection.
+// Fields:
+//   - r (*bufio.Reader): The buffered connection.
+type reader struct {
+	r *bufio.Reader
+}
+
+// read is a method of the reader struct that reads a command, sent as an array of bulk
+// strings like the redis clients do, or as an inline command like from telnet.
+// Returns:
+//   - [][]byte: The arguments of the command, with its name first. Empty for an empty inline command.
+//   - error: An error wrapping errProtocol if the command is malformed, or the error of the connection.
+func (r *reader) read() ([][]byte, error) {
+	var line, err = r.line()
+	if err != nil {
+		return nil, err
+	} else if len(line) == 0 || line[0] != '*' {
+		return bytes.Fields(line), nil
+	}
+
+	// Read the array of bulk strings
+	var n int
+	if n, err = strconv.Atoi(string(line[1:])); err != nil || n > maxArgs {
+		return nil, fmt.Errorf("%w: invalid multibulk length", errProtocol)
+	}
+	var args [][]byte = make([][]byte, 0, 8)
+	for i := 0; i < n; i++ {
+		if line, err = r.line(); err != nil {
+			return nil, err
+		} else if len(line) == 0 || line[0] != '$' {
+			return nil, fmt.Errorf("%w: expected '$'", errProtocol)
+		}
+		var size int
+		if size, err = strconv.Atoi(string(line[1:])); err != nil || size < 0 || size > maxBulkLength {
+			return nil, fmt.Errorf("%w: invalid bulk length", errProtocol)
+		}
+
+		// Read the string and its line ending
+		var arg []byte = make([]byte, size+2)
+		if _, err = io.ReadFull(r.r, arg); err != nil {
+			return nil, err
+		} else if arg[size] != '\r' || arg[size+1] != '\n' {
+			return nil, fmt.Errorf("%w: invalid bulk string ending", errProtocol)
+		}
+		args = append(args, arg[:size])
+	}
+	return args, nil
+}
+
+// line is a method of the reader struct that reads a line, without its line ending.
+// Returns:
+//   - []byte: The line. It's only valid until the next read.
+//   - error: An error wrapping errProtocol if the line is too long, or the error of the connection.
+func (r *reader) line() ([]byte, error) {
+	var line, err = r.r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return nil, fmt.Errorf("%w: too big inline request", errProtocol)
+	} else if err != nil {
+		return nil, err
+	}
+	line = line[:len(line)-1]
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+	return line, nil
+}
+
+// buffered is a method of the reader struct that returns whether more commands have already been received.
+// The replies are flushed once the pipelined commands have been run.
+// Returns:
+//   - bool: Whether more bytes are buffered.
+func (r *reader) buffered() bool {
+	return r.r.Buffered() > 0
+}
+
+// writer is a struct that writes the replies of a connection. The write errors are
+// kept by the buffered writer, and returned by the flush.
+// Fields:
+//   - w (*bufio.Writer): The buffered connection.
+type writer struct {
+	w *bufio.Writer
+}
+
+// simple is a method of the writer struct that writes a simple string, like OK.
+// Parameters:
+//   - s (string): The string. It must not contain a line ending.
+func (w *writer) simple(s string) {
+	w.w.WriteByte('+')
+	w.w.WriteString(s)
+	w.w.WriteString("\r\n")
+}
+
+// error is a method of the writer struct that writes an error.
+// Parameters:
+//   - e (*Error): The error.
+func (w *writer) error(e *Error) {
+	w.w.WriteByte('-')
+	w.w.WriteString(e.Error())
+	w.w.WriteString("\r\n")
+}
+
+// integer is a method of the writer struct that writes an integer.
+// Parameters:
+//   - n (int): The integer.
+func (w *writer) integer(n int) {
+	w.w.WriteByte(':')
+	w.w.WriteString(strconv.Itoa(n))
+	w.w.WriteString("\r\n")
+}
+
+// bulk is a method of the writer struct that writes a bulk string.
+// Parameters:
+//   - s (string): The string.
+func (w *writer) bulk(s string) {
+	w.w.WriteByte('$')
+	w.w.WriteString(strconv.Itoa(len(s)))
+	w.w.WriteString("\r\n")
+	w.w.WriteString(s)
+	w.w.WriteString("\r\n")
+}
+
+// null is a method of the writer struct that writes a null bulk string, for a missing key or field.
+func (w *writer) null() {
+	w.w.WriteString("$-1\r\n")
+}
+
+// array is a method of the writer struct that writes the header of an array. The elements are written after it.
+// Parameters:
+//   - n (int): The number of elements.
+func (w *writer) array(n int) {
+	w.w.WriteByte('*')
+	w.w.WriteString(strconv.Itoa(n))
+	w.w.WriteString("\r\n")
+}
+
+// strings is a method of the writer struct that writes an array of bulk strings.
+// Parameters:
+//   - values ([]string): The strings.
+func (w *writer) strings(values []string) {
+	w.array(len(values))
+	for _, v := range values {
+		w.bulk(v)
+	}
+}
+
+// flush is a method of the writer struct that sends the buffered replies.
+// Returns:
+//   - error: The first error of the connection, or nil if successful.
+func (w *writer) flush() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,139 @@
+package resp
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// ftCreate is a function that implements FT.CREATE collection [MAXSIZE n] [MAXBYTES n] [MINWORDLENGTH n].
+// The index of a collection is its full-text index, which is initialized with the limits.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply if the options are invalid, the collection does not exist, or the index could not be initialized.
+func ftCreate(c *conn, args [][]byte) *Error {
+	var maxSize, maxBytes, minWordLength int = -1, -1, 3
+	for i := 1; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return errSyntax
+		}
+		var n, err = strconv.Atoi(string(args[i+1]))
+		if err != nil {
+			return errSyntax
+		}
+		switch strings.ToLower(string(args[i])) {
+		case "maxsize":
+			maxSize = n
+		case "maxbytes":
+			maxBytes = n
+		case "minwordlength":
+			minWordLength = n
+		default:
+			return errSyntax
+		}
+	}
+	var cache, err = c.server.collections.Get(string(args[0]))
+	if err != nil {
+		return errorf(err.Error())
+	} else if err = cache.FTInit(maxSize, maxBytes, minWordLength); err != nil {
+		return errorf(err.Error())
+	}
+	c.w.simple("OK")
+	return nil
+}
+
+// ftSearch is a function that implements FT.SEARCH collection query [STRICT] [INFIELDS n field ...] [LIMIT offset num].
+// With INFIELDS, the values of the fields are searched like Cache.SearchValues, and otherwise the full-text index is
+// searched like Cache.Search. The reply is the number of results, followed by each record as a field-value array.
+// The search results don't have their keys, so unlike RediSearch there are no document ids.
+// Parameters:
+//   - c (*conn): The connection.
+//   - args ([][]byte): The arguments of the command.
+//
+// Returns:
+//   - *Error: An error reply if the options are invalid, the collection does not exist, or the search failed.
+func ftSearch(c *conn, args [][]byte) *Error {
+	var (
+		sp          hermes.SearchParams = hermes.SearchParams{Query: string(args[1])}
+		offset, num int                 = 0, 10
+		err         error
+	)
+	for i := 2; i < len(args); i++ {
+		switch strings.ToLower(string(args[i])) {
+		case "strict":
+			sp.Strict = true
+		case "infields":
+			var n int
+			if i+1 >= len(args) {
+				return errSyntax
+			} else if n, err = strconv.Atoi(string(args[i+1])); err != nil || n < 1 || i+1+n >= len(args) {
+				return errSyntax
+			}
+			sp.Schema = make(map[string]bool, n)
+			for _, field := range args[i+2 : i+2+n] {
+				sp.Schema[string(field)] = true
+			}
+			i += 1 + n
+		case "limit":
+			if i+2 >= len(args) {
+				return errSyntax
+			} else if offset, err = strconv.Atoi(string(args[i+1])); err != nil || offset < 0 {
+				return errSyntax
+			} else if num, err = strconv.Atoi(string(args[i+2])); err != nil || num < 0 {
+				return errSyntax
+			}
+			i += 2
+		default:
+			return errSyntax
+		}
+	}
+
+	// Search the collection. LIMIT 0 0 only counts the results, without a limit
+	var cache *hermes.Cache
+	if cache, err = c.server.collections.Get(string(args[0])); err != nil {
+		return errorf(err.Error())
+	}
+	if sp.Limit = offset + num; num == 0 {
+		sp.Limit = math.MaxInt
+	}
+	var results []map[string]any
+	if sp.Schema != nil {
+		results, err = cache.SearchValues(sp)
+	} else {
+		results, err = cache.Search(sp)
+	}
+	if err != nil {
+		return errorf(err.Error())
+	}
+
+	// Reply with the page of the results
+	var page []map[string]any
+	if num > 0 && offset < len(results) {
+		page = results[offset:min(len(results), offset+num)]
+	}
+	c.w.array(1 + len(page))
+	c.w.integer(len(results))
+	for _, record := range page {
+		c.w.strings(pairs(record))
+	}
+	return nil
+}
+
+// min is a function that returns the smaller of two integers.
+// Parameters:
+//   - a (int): The first integer.
+//   - b (int): The second integer.
+//
+// Returns:
+//   - int: The smaller integer.
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
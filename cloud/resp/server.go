@@ -0,0 +1,316 @@
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/cloud/auth"
+)
+
+// ErrServerClosed is returned by Serve after the server is closed.
+var ErrServerClosed = errors.New("resp: server closed")
+
+// Config is a struct that represents the settings of the resp server.
+type Config struct {
+	// The authenticator of the AUTH command. If nil, authentication is disabled
+	Auth auth.Authenticator
+	// Whether the commands that require the write or admin scope are rejected with a READONLY error, like on a replica
+	ReadOnly bool
+	// A function called after each command, with the lowercase name of the command,
+	// how long it took, and its error. If nil, the commands aren't observed
+	Observe func(command string, duration time.Duration, err error)
+}
+
+// Error is a struct that represents an error reply, like "ERR wrong number of arguments".
+type Error struct {
+	// The error code, the first word of the reply: ERR, WRONGTYPE, NOAUTH, NOPERM...
+	Code string
+	// The message of the error
+	Message string
+}
+
+// Error is a method of the Error struct that returns the error reply, without its line ending.
+// Returns:
+//   - string: The code and the message.
+func (e *Error) Error() string {
+	return e.Code + " " + e.Message
+}
+
+// errorf is a function that creates an ERR error reply.
+// Parameters:
+//   - msg (string): The message of the error. Line endings are replaced with spaces.
+//
+// Returns:
+//   - *Error: The error.
+func errorf(msg string) *Error {
+	return &Error{Code: "ERR", Message: strings.NewReplacer("\r", " ", "\n", " ").Replace(msg)}
+}
+
+// Server is a struct that serves the collections over the redis protocol.
+// Fields:
+//   - collections (*hermes.Collections): The collections. The redis databases are the collections.
+//   - config (Config): The settings of the server.
+//   - mutex (sync.Mutex): The mutex that guards the listeners and the connections.
+//   - listeners (map[net.Listener]bool): The listeners that are served.
+//   - conns (map[net.Conn]bool): The open connections.
+//   - closed (bool): Whether the server is closed.
+//   - wg (sync.WaitGroup): The wait group of the connections.
+type Server struct {
+	collections *hermes.Collections
+	config      Config
+	mutex       sync.Mutex
+	listeners   map[net.Listener]bool
+	conns       map[net.Conn]bool
+	closed      bool
+	wg          sync.WaitGroup
+}
+
+// NewServer is a function that creates a resp server of the collections.
+// Parameters:
+//   - cs (*hermes.Collections): The collections that the commands are run on.
+//   - config (Config): The settings of the server.
+//
+// Returns:
+//   - *Server: The server. Its Serve method serves the connections of a listener.
+func NewServer(cs *hermes.Collections, config Config) *Server {
+	return &Server{
+		collections: cs,
+		config:      config,
+		listeners:   make(map[net.Listener]bool),
+		conns:       make(map[net.Conn]bool),
+	}
+}
+
+// Serve is a method of the Server struct that serves the connections of a listener, until the listener
+// fails or the server is closed. The listener is closed when Serve returns.
+// Parameters:
+//   - ln (net.Listener): The listener, for example from net.Listen or tls.Listen.
+//
+// Returns:
+//   - error: ErrServerClosed after Close, or the error of the listener.
+func (s *Server) Serve(ln net.Listener) error {
+	if !s.track(ln, nil, true) {
+		ln.Close()
+		return ErrServerClosed
+	}
+	defer s.track(ln, nil, false)
+	defer ln.Close()
+
+	// Serve each connection in its own goroutine
+	for {
+		var conn, err = ln.Accept()
+		if err != nil {
+			var ne net.Error
+			if s.isClosed() {
+				return ErrServerClosed
+			} else if errors.As(err, &ne) && ne.Timeout() {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+		if !s.track(nil, conn, true) {
+			conn.Close()
+			return ErrServerClosed
+		}
+		go func() {
+			defer s.wg.Done()
+			defer s.track(nil, conn, false)
+			defer conn.Close()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+// Close is a method of the Server struct that closes the listeners and the connections,
+// and waits for the commands that are running.
+// Returns:
+//   - error: Always nil.
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	s.closed = true
+	for ln := range s.listeners {
+		ln.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mutex.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+// track is a method of the Server struct that adds or removes a listener or a connection.
+// Parameters:
+//   - ln (net.Listener): The listener, or nil.
+//   - conn (net.Conn): The connection, or nil.
+//   - add (bool): Whether it's added or removed.
+//
+// Returns:
+//   - bool: False if it can't be added because the server is closed.
+func (s *Server) track(ln net.Listener, conn net.Conn, add bool) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	switch {
+	case add && s.closed:
+		return false
+	case add && ln != nil:
+		s.listeners[ln] = true
+	case add:
+		s.conns[conn] = true
+		s.wg.Add(1)
+	case ln != nil:
+		delete(s.listeners, ln)
+	default:
+		delete(s.conns, conn)
+	}
+	return true
+}
+
+// isClosed is a method of the Server struct that returns whether the server is closed.
+// Returns:
+//   - bool: Whether Close was called.
+func (s *Server) isClosed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.closed
+}
+
+// conn is a struct that represents the state of a connection.
+// Fields:
+//   - server (*Server): The server of the connection.
+//   - r (*reader): The reader of the commands.
+//   - w (*writer): The writer of the replies.
+//   - collection (string): The name of the selected collection.
+//   - identity (*auth.Identity): The identity of the AUTH command. Nil before the connection is authenticated.
+//   - quit (bool): Whether the connection is closed after the replies are flushed.
+type conn struct {
+	server     *Server
+	r          *reader
+	w          *writer
+	collection string
+	identity   *auth.Identity
+	quit       bool
+}
+
+// serveConn is a method of the Server struct that runs the commands of a connection until it's closed.
+// The replies of pipelined commands are flushed together.
+// Parameters:
+//   - nc (net.Conn): The connection.
+func (s *Server) serveConn(nc net.Conn) {
+	var c *conn = &conn{
+		server:     s,
+		r:          &reader{r: bufio.NewReaderSize(nc, maxInline)},
+		w:          &writer{w: bufio.NewWriter(nc)},
+		collection: hermes.DefaultCollection,
+	}
+	for !c.quit {
+		var args, err = c.r.read()
+		if errors.Is(err, errProtocol) {
+			c.w.error(errorf(err.Error()))
+			c.w.flush()
+			return
+		} else if err != nil {
+			return
+		} else if len(args) > 0 {
+			c.run(args)
+		}
+
+		// Flush the replies once the pipelined commands have been run
+		if !c.r.buffered() || c.quit {
+			if err := c.w.flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// run is a method of the conn struct that runs a command and writes its reply.
+// Parameters:
+//   - args ([][]byte): The arguments of the command, with its name first.
+func (c *conn) run(args [][]byte) {
+	var (
+		start time.Time = time.Now()
+		name  string    = strings.ToLower(string(args[0]))
+		err   *Error
+	)
+	var cmd, ok = commands[name]
+	switch {
+	case !ok:
+		err = errorf("unknown command '" + truncate(string(args[0])) + "'")
+		name = "unknown"
+	case (cmd.arity > 0 && len(args) != cmd.arity) || (cmd.arity < 0 && len(args) < -cmd.arity):
+		err = errorf("wrong number of arguments for '" + name + "' command")
+	default:
+		if err = c.authorize(name, cmd.scope); err == nil {
+			err = cmd.run(c, args[1:])
+		}
+	}
+	if err == nil {
+		c.observe(name, start, nil)
+	} else {
+		c.w.error(err)
+		c.observe(name, start, err)
+	}
+}
+
+// observe is a method of the conn struct that reports a command to the Observe function of the config.
+// Parameters:
+//   - name (string): The name of the command.
+//   - start (time.Time): When the command started.
+//   - err (error): The error of the command, or nil. It's not a typed nil, so that it can be compared to nil.
+func (c *conn) observe(name string, start time.Time, err error) {
+	if c.server.config.Observe != nil {
+		c.server.config.Observe(name, time.Since(start), err)
+	}
+}
+
+// authorize is a method of the conn struct that checks that the identity of the connection has the scope of a command.
+// Parameters:
+//   - name (string): The name of the command.
+//   - scope (auth.Scope): The scope of the command. Empty for the commands that don't require authentication.
+//
+// Returns:
+//   - *Error: A NOAUTH, NOPERM or READONLY error, or nil if the command is allowed.
+func (c *conn) authorize(name string, scope auth.Scope) *Error {
+	if len(scope) == 0 {
+		return nil
+	} else if c.server.config.Auth != nil && c.identity == nil {
+		return &Error{Code: "NOAUTH", Message: "Authentication required."}
+	} else if c.server.config.Auth != nil && !c.identity.Scope.Allows(scope) {
+		return &Error{Code: "NOPERM", Message: "the " + string(scope) + " scope is required to run the '" + name + "' command"}
+	} else if c.server.config.ReadOnly && scope.Writes() {
+		return &Error{Code: "READONLY", Message: "You can't write against a read only replica."}
+	}
+	return nil
+}
+
+// cache is a method of the conn struct that gets the selected collection.
+// Returns:
+//   - *hermes.Cache: The collection.
+//   - *Error: An error if the collection was dropped after it was selected, or nil if successful.
+func (c *conn) cache() (*hermes.Cache, *Error) {
+	if cache, err := c.server.collections.Get(c.collection); err != nil {
+		return nil, errorf(err.Error())
+	} else {
+		return cache, nil
+	}
+}
+
+// truncate is a function that shortens an argument that is quoted in an error reply.
+// Parameters:
+//   - s (string): The argument.
+//
+// Returns:
+//   - string: The first 128 bytes of the argument.
+func truncate(s string) string {
+	if len(s) > 128 {
+		return s[:128]
+	}
+	return s
+}
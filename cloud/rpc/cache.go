@@ -0,0 +1,159 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// set is a method of the server struct that implements Cache.Set.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*SetRequest): The key and the JSON-encoded record.
+//
+// Returns:
+//   - *Empty: An empty response.
+//   - error: A status error if the params are invalid, the collection does not exist, or the record could not be set.
+func (s *server) set(ctx context.Context, req *SetRequest) (*Empty, error) {
+	var value map[string]any
+	if len(req.Key) == 0 {
+		return nil, invalid("key not provided")
+	} else if err := json.Unmarshal(req.Value, &value); err != nil || value == nil {
+		return nil, invalid("the value must be a JSON object")
+	}
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	return &Empty{}, failed(c.Set(req.Key, value))
+}
+
+// get is a method of the server struct that implements Cache.Get.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*KeyRequest): The key of the record.
+//
+// Returns:
+//   - *Record: The record.
+//   - error: A NotFound status error if the key or collection does not exist, or another status error if the params are invalid.
+func (s *server) get(ctx context.Context, req *KeyRequest) (*Record, error) {
+	if len(req.Key) == 0 {
+		return nil, invalid("key not provided")
+	}
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	var value map[string]any = c.Get(req.Key)
+	if value == nil {
+		return nil, status.Errorf(codes.NotFound, "key %s does not exist", req.Key)
+	}
+	return record(req.Key, value)
+}
+
+// delete is a method of the server struct that implements Cache.Delete.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*KeyRequest): The key of the record.
+//
+// Returns:
+//   - *Empty: An empty response.
+//   - error: A status error if the params are invalid or the collection does not exist.
+func (s *server) delete(ctx context.Context, req *KeyRequest) (*Empty, error) {
+	if len(req.Key) == 0 {
+		return nil, invalid("key not provided")
+	}
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	c.Delete(req.Key)
+	return &Empty{}, nil
+}
+
+// exists is a method of the server struct that implements Cache.Exists.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*KeyRequest): The key.
+//
+// Returns:
+//   - *BoolResponse: Whether the key exists.
+//   - error: A status error if the params are invalid or the collection does not exist.
+func (s *server) exists(ctx context.Context, req *KeyRequest) (*BoolResponse, error) {
+	if len(req.Key) == 0 {
+		return nil, invalid("key not provided")
+	}
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	return &BoolResponse{Value: c.Exists(req.Key)}, nil
+}
+
+// keys is a method of the server struct that implements Cache.Keys.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*CollectionRequest): The collection.
+//
+// Returns:
+//   - *KeysResponse: The keys of the collection.
+//   - error: A NotFound status error if the collection does not exist.
+func (s *server) keys(ctx context.Context, req *CollectionRequest) (*KeysResponse, error) {
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	return &KeysResponse{Keys: c.Keys()}, nil
+}
+
+// length is a method of the server struct that implements Cache.Length.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*CollectionRequest): The collection.
+//
+// Returns:
+//   - *IntResponse: The number of records of the collection.
+//   - error: A NotFound status error if the collection does not exist.
+func (s *server) length(ctx context.Context, req *CollectionRequest) (*IntResponse, error) {
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	return &IntResponse{Value: int64(c.Length())}, nil
+}
+
+// clean is a method of the server struct that implements Cache.Clean.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*CollectionRequest): The collection.
+//
+// Returns:
+//   - *Empty: An empty response.
+//   - error: A NotFound status error if the collection does not exist.
+func (s *server) clean(ctx context.Context, req *CollectionRequest) (*Empty, error) {
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	c.Clean()
+	return &Empty{}, nil
+}
+
+// record is a function that encodes a record of the cache.
+// Parameters:
+//   - key (string): The key of the record. Empty for the search results.
+//   - value (map[string]any): The record.
+//
+// Returns:
+//   - *Record: The encoded record.
+//   - error: An Internal status error if the record could not be encoded, or nil if successful.
+func record(key string, value map[string]any) (*Record, error) {
+	if data, err := json.Marshal(value); err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to encode the record: %v", err))
+	} else {
+		return &Record{Key: key, Value: data}, nil
+	}
+}
@@ -0,0 +1,221 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// CacheClient is a struct that represents a client of the hermes.v1.Cache service.
+type CacheClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewCacheClient is a function that creates a client of the hermes.v1.Cache service.
+// Parameters:
+//   - cc (grpc.ClientConnInterface): The connection to the server, for example from grpc.Dial.
+//
+// Returns:
+//   - *CacheClient: A pointer to the client.
+func NewCacheClient(cc grpc.ClientConnInterface) *CacheClient {
+	return &CacheClient{cc: cc}
+}
+
+// invoke is a function that calls a unary method with Codec.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - cc (grpc.ClientConnInterface): The connection to the server.
+//   - method (string): The full name of the method.
+//   - req (message): The request.
+//   - resp (R): The response, which the reply is decoded into.
+//   - opts ([]grpc.CallOption): The options of the call.
+//
+// Returns:
+//   - R: The response.
+//   - error: A status error if the call failed, or nil if successful.
+func invoke[R message](ctx context.Context, cc grpc.ClientConnInterface, method string, req message, resp R, opts []grpc.CallOption) (R, error) {
+	var err error = cc.Invoke(ctx, method, req, resp, append(opts, grpc.ForceCodec(Codec))...)
+	return resp, err
+}
+
+// Set is a method of the CacheClient struct that sets a record. The key must not exist.
+func (c *CacheClient) Set(ctx context.Context, req *SetRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.Cache/Set", req, &Empty{}, opts)
+}
+
+// Get is a method of the CacheClient struct that gets a record. NotFound if the key does not exist.
+func (c *CacheClient) Get(ctx context.Context, req *KeyRequest, opts ...grpc.CallOption) (*Record, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.Cache/Get", req, &Record{}, opts)
+}
+
+// Delete is a method of the CacheClient struct that deletes a record.
+func (c *CacheClient) Delete(ctx context.Context, req *KeyRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.Cache/Delete", req, &Empty{}, opts)
+}
+
+// Exists is a method of the CacheClient struct that checks whether a key exists.
+func (c *CacheClient) Exists(ctx context.Context, req *KeyRequest, opts ...grpc.CallOption) (*BoolResponse, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.Cache/Exists", req, &BoolResponse{}, opts)
+}
+
+// Keys is a method of the CacheClient struct that gets the keys of a collection.
+func (c *CacheClient) Keys(ctx context.Context, req *CollectionRequest, opts ...grpc.CallOption) (*KeysResponse, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.Cache/Keys", req, &KeysResponse{}, opts)
+}
+
+// Length is a method of the CacheClient struct that gets the number of records of a collection.
+func (c *CacheClient) Length(ctx context.Context, req *CollectionRequest, opts ...grpc.CallOption) (*IntResponse, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.Cache/Length", req, &IntResponse{}, opts)
+}
+
+// Clean is a method of the CacheClient struct that deletes every record of a collection.
+func (c *CacheClient) Clean(ctx context.Context, req *CollectionRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.Cache/Clean", req, &Empty{}, opts)
+}
+
+// Watch is a method of the CacheClient struct that opens a watch stream. The stream ends when the context is cancelled.
+// Parameters:
+//   - ctx (context.Context): The context of the stream.
+//   - opts (...grpc.CallOption): The options of the call.
+//
+// Returns:
+//   - *WatchClient: The stream, to start and cancel subscriptions and receive their events.
+//   - error: An error if the stream could not be opened, or nil if successful.
+func (c *CacheClient) Watch(ctx context.Context, opts ...grpc.CallOption) (*WatchClient, error) {
+	var stream, err = c.cc.NewStream(ctx, &CacheServiceDesc.Streams[0], "/hermes.v1.Cache/Watch", append(opts, grpc.ForceCodec(Codec))...)
+	if err != nil {
+		return nil, err
+	}
+	return &WatchClient{stream: stream}, nil
+}
+
+// WatchClient is a struct that represents the client side of a watch stream.
+type WatchClient struct {
+	stream grpc.ClientStream
+}
+
+// Send is a method of the WatchClient struct that starts or cancels a subscription.
+func (w *WatchClient) Send(req *WatchRequest) error {
+	return w.stream.SendMsg(req)
+}
+
+// Recv is a method of the WatchClient struct that receives the next event of the subscriptions.
+func (w *WatchClient) Recv() (*Event, error) {
+	var e *Event = &Event{}
+	return e, w.stream.RecvMsg(e)
+}
+
+// CloseSend is a method of the WatchClient struct that closes the client side of the stream.
+// The events of the running subscriptions are still received.
+func (w *WatchClient) CloseSend() error {
+	return w.stream.CloseSend()
+}
+
+// FullTextClient is a struct that represents a client of the hermes.v1.FullText service.
+type FullTextClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewFullTextClient is a function that creates a client of the hermes.v1.FullText service.
+// Parameters:
+//   - cc (grpc.ClientConnInterface): The connection to the server, for example from grpc.Dial.
+//
+// Returns:
+//   - *FullTextClient: A pointer to the client.
+func NewFullTextClient(cc grpc.ClientConnInterface) *FullTextClient {
+	return &FullTextClient{cc: cc}
+}
+
+// Init is a method of the FullTextClient struct that initializes the full-text index of a collection.
+func (c *FullTextClient) Init(ctx context.Context, req *InitRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.FullText/Init", req, &Empty{}, opts)
+}
+
+// Clean is a method of the FullTextClient struct that clears the full-text index of a collection.
+func (c *FullTextClient) Clean(ctx context.Context, req *CollectionRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.FullText/Clean", req, &Empty{}, opts)
+}
+
+// SetMaxBytes is a method of the FullTextClient struct that sets the maximum size of the full-text index.
+func (c *FullTextClient) SetMaxBytes(ctx context.Context, req *IntRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.FullText/SetMaxBytes", req, &Empty{}, opts)
+}
+
+// SetMaxSize is a method of the FullTextClient struct that sets the maximum number of words in the full-text index.
+func (c *FullTextClient) SetMaxSize(ctx context.Context, req *IntRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.FullText/SetMaxSize", req, &Empty{}, opts)
+}
+
+// SetMinWordLength is a method of the FullTextClient struct that sets the minimum length of the indexed words.
+func (c *FullTextClient) SetMinWordLength(ctx context.Context, req *IntRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.FullText/SetMinWordLength", req, &Empty{}, opts)
+}
+
+// IsInitialized is a method of the FullTextClient struct that checks whether the full-text index is initialized.
+func (c *FullTextClient) IsInitialized(ctx context.Context, req *CollectionRequest, opts ...grpc.CallOption) (*BoolResponse, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.FullText/IsInitialized", req, &BoolResponse{}, opts)
+}
+
+// StorageSize is a method of the FullTextClient struct that gets the size of the full-text index, in bytes.
+func (c *FullTextClient) StorageSize(ctx context.Context, req *CollectionRequest, opts ...grpc.CallOption) (*IntResponse, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.FullText/StorageSize", req, &IntResponse{}, opts)
+}
+
+// StorageLength is a method of the FullTextClient struct that gets the number of words in the full-text index.
+func (c *FullTextClient) StorageLength(ctx context.Context, req *CollectionRequest, opts ...grpc.CallOption) (*IntResponse, error) {
+	return invoke(ctx, c.cc, "/hermes.v1.FullText/StorageLength", req, &IntResponse{}, opts)
+}
+
+// Search is a method of the FullTextClient struct that searches the full-text index. The records are streamed.
+func (c *FullTextClient) Search(ctx context.Context, req *SearchRequest, opts ...grpc.CallOption) (*SearchClient, error) {
+	return c.search(ctx, 0, "/hermes.v1.FullText/Search", req, opts)
+}
+
+// SearchOneWord is a method of the FullTextClient struct that searches the full-text index for a single word.
+func (c *FullTextClient) SearchOneWord(ctx context.Context, req *SearchRequest, opts ...grpc.CallOption) (*SearchClient, error) {
+	return c.search(ctx, 1, "/hermes.v1.FullText/SearchOneWord", req, opts)
+}
+
+// SearchValues is a method of the FullTextClient struct that searches the values of the fields of the schema.
+func (c *FullTextClient) SearchValues(ctx context.Context, req *SearchRequest, opts ...grpc.CallOption) (*SearchClient, error) {
+	return c.search(ctx, 2, "/hermes.v1.FullText/SearchValues", req, opts)
+}
+
+// SearchWithKey is a method of the FullTextClient struct that searches the values of the key field.
+func (c *FullTextClient) SearchWithKey(ctx context.Context, req *SearchRequest, opts ...grpc.CallOption) (*SearchClient, error) {
+	return c.search(ctx, 3, "/hermes.v1.FullText/SearchWithKey", req, opts)
+}
+
+// search is a method of the FullTextClient struct that opens the stream of a search.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - i (int): The index of the stream in the description of the service.
+//   - method (string): The full name of the method.
+//   - req (*SearchRequest): The search request.
+//   - opts ([]grpc.CallOption): The options of the call.
+//
+// Returns:
+//   - *SearchClient: The stream of the records.
+//   - error: An error if the stream could not be opened, or nil if successful.
+func (c *FullTextClient) search(ctx context.Context, i int, method string, req *SearchRequest, opts []grpc.CallOption) (*SearchClient, error) {
+	var stream, err = c.cc.NewStream(ctx, &FullTextServiceDesc.Streams[i], method, append(opts, grpc.ForceCodec(Codec))...)
+	if err != nil {
+		return nil, err
+	} else if err := stream.SendMsg(req); err != nil {
+		return nil, err
+	} else if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return &SearchClient{stream: stream}, nil
+}
+
+// SearchClient is a struct that represents the stream of the records of a search.
+type SearchClient struct {
+	stream grpc.ClientStream
+}
+
+// Recv is a method of the SearchClient struct that receives the next record. io.EOF is returned after the last record.
+func (s *SearchClient) Recv() (*Record, error) {
+	var r *Record = &Record{}
+	return r, s.stream.RecvMsg(r)
+}
@@ -0,0 +1,58 @@
+package rpc
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec is the grpc codec of the messages of the services. It's named "proto", since the messages are
+// encoded in the protobuf wire format, so the clients that are generated from hermes.proto can call the services.
+// The generated protobuf messages, for example of the health service, are encoded with the protobuf library.
+// It's set on the server by NewServer, and on the calls of the clients in this package with grpc.ForceCodec.
+var Codec encoding.Codec = codec{}
+
+// codec is the implementation of Codec.
+type codec struct{}
+
+// Marshal is a method of the codec struct that encodes a message.
+// Parameters:
+//   - v (any): The message.
+//
+// Returns:
+//   - []byte: The encoded message.
+//   - error: An error if the value is not a message, or nil if successful.
+func (codec) Marshal(v any) ([]byte, error) {
+	switch m := v.(type) {
+	case message:
+		return m.marshal(nil), nil
+	case proto.Message:
+		return proto.Marshal(m)
+	}
+	return nil, fmt.Errorf("rpc: %T is not a message", v)
+}
+
+// Unmarshal is a method of the codec struct that decodes a message.
+// Parameters:
+//   - data ([]byte): The encoded message.
+//   - v (any): A pointer to the message.
+//
+// Returns:
+//   - error: An error if the message is malformed or the value is not a message, or nil if successful.
+func (codec) Unmarshal(data []byte, v any) error {
+	switch m := v.(type) {
+	case message:
+		return m.unmarshal(data)
+	case proto.Message:
+		return proto.Unmarshal(data, m)
+	}
+	return fmt.Errorf("rpc: %T is not a message", v)
+}
+
+// Name is a method of the codec struct that returns the name of the codec.
+// Returns:
+//   - string: "proto".
+func (codec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,261 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+
+	hermes "github.com/realTristan/hermes"
+	"google.golang.org/grpc"
+)
+
+// ftInit is a method of the server struct that implements FullText.Init.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*InitRequest): The limits of the index, and the records to initialize it with.
+//
+// Returns:
+//   - *Empty: An empty response.
+//   - error: A status error if the records are invalid, the collection does not exist, or the index could not be initialized.
+func (s *server) ftInit(ctx context.Context, req *InitRequest) (*Empty, error) {
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	var maxSize, maxBytes, minWordLength int = int(req.MaxSize), int(req.MaxBytes), int(req.MinWordLength)
+	if len(req.JSON) == 0 {
+		return &Empty{}, failed(c.FTInit(maxSize, maxBytes, minWordLength))
+	}
+
+	// Initialize the index with the records
+	var data map[string]map[string]any
+	if err := json.Unmarshal(req.JSON, &data); err != nil {
+		return nil, invalid("json must map the keys to the records")
+	}
+	return &Empty{}, failed(c.FTInitWithMap(data, maxSize, maxBytes, minWordLength))
+}
+
+// ftClean is a method of the server struct that implements FullText.Clean.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*CollectionRequest): The collection.
+//
+// Returns:
+//   - *Empty: An empty response.
+//   - error: A status error if the collection does not exist or the index is not initialized.
+func (s *server) ftClean(ctx context.Context, req *CollectionRequest) (*Empty, error) {
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	return &Empty{}, failed(c.FTClean())
+}
+
+// ftSetMaxBytes is a method of the server struct that implements FullText.SetMaxBytes.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*IntRequest): The maximum size of the index, in bytes. -1 for no limit.
+//
+// Returns:
+//   - *Empty: An empty response.
+//   - error: A status error if the collection does not exist or the size could not be set.
+func (s *server) ftSetMaxBytes(ctx context.Context, req *IntRequest) (*Empty, error) {
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	return &Empty{}, failed(c.FTSetMaxBytes(int(req.Value)))
+}
+
+// ftSetMaxSize is a method of the server struct that implements FullText.SetMaxSize.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*IntRequest): The maximum number of words in the index. -1 for no limit.
+//
+// Returns:
+//   - *Empty: An empty response.
+//   - error: A status error if the collection does not exist or the size could not be set.
+func (s *server) ftSetMaxSize(ctx context.Context, req *IntRequest) (*Empty, error) {
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	return &Empty{}, failed(c.FTSetMaxSize(int(req.Value)))
+}
+
+// ftSetMinWordLength is a method of the server struct that implements FullText.SetMinWordLength.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*IntRequest): The minimum length of the indexed words.
+//
+// Returns:
+//   - *Empty: An empty response.
+//   - error: A status error if the collection does not exist or the length could not be set.
+func (s *server) ftSetMinWordLength(ctx context.Context, req *IntRequest) (*Empty, error) {
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	return &Empty{}, failed(c.FTSetMinWordLength(int(req.Value)))
+}
+
+// ftIsInitialized is a method of the server struct that implements FullText.IsInitialized.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*CollectionRequest): The collection.
+//
+// Returns:
+//   - *BoolResponse: Whether the index is initialized.
+//   - error: A NotFound status error if the collection does not exist.
+func (s *server) ftIsInitialized(ctx context.Context, req *CollectionRequest) (*BoolResponse, error) {
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	return &BoolResponse{Value: c.FTIsInitialized()}, nil
+}
+
+// ftStorageSize is a method of the server struct that implements FullText.StorageSize.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*CollectionRequest): The collection.
+//
+// Returns:
+//   - *IntResponse: The size of the index, in bytes.
+//   - error: A status error if the collection does not exist or the index is not initialized.
+func (s *server) ftStorageSize(ctx context.Context, req *CollectionRequest) (*IntResponse, error) {
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	var size int
+	if size, err = c.FTStorageSize(); err != nil {
+		return nil, failed(err)
+	}
+	return &IntResponse{Value: int64(size)}, nil
+}
+
+// ftStorageLength is a method of the server struct that implements FullText.StorageLength.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (*CollectionRequest): The collection.
+//
+// Returns:
+//   - *IntResponse: The number of words in the index.
+//   - error: A status error if the collection does not exist or the index is not initialized.
+func (s *server) ftStorageLength(ctx context.Context, req *CollectionRequest) (*IntResponse, error) {
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	var length int
+	if length, err = c.FTStorageLength(); err != nil {
+		return nil, failed(err)
+	}
+	return &IntResponse{Value: int64(length)}, nil
+}
+
+// search is a method of the server struct that implements FullText.Search.
+// Parameters:
+//   - req (*SearchRequest): The query, limit and strict params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: A status error if the params are invalid, the collection does not exist, or the search failed.
+func (s *server) search(req *SearchRequest) ([]map[string]any, error) {
+	if len(req.Query) == 0 {
+		return nil, invalid("query not provided")
+	}
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	var results []map[string]any
+	results, err = c.Search(hermes.SearchParams{Query: req.Query, Limit: int(req.Limit), Strict: req.Strict})
+	return results, failed(err)
+}
+
+// searchOneWord is a method of the server struct that implements FullText.SearchOneWord.
+// Parameters:
+//   - req (*SearchRequest): The query, limit and strict params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: A status error if the params are invalid, the collection does not exist, or the search failed.
+func (s *server) searchOneWord(req *SearchRequest) ([]map[string]any, error) {
+	if len(req.Query) == 0 {
+		return nil, invalid("query not provided")
+	}
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	var results []map[string]any
+	results, err = c.SearchOneWord(hermes.SearchParams{Query: req.Query, Limit: int(req.Limit), Strict: req.Strict})
+	return results, failed(err)
+}
+
+// searchValues is a method of the server struct that implements FullText.SearchValues.
+// Parameters:
+//   - req (*SearchRequest): The query, limit and schema params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: A status error if the params are invalid, the collection does not exist, or the search failed.
+func (s *server) searchValues(req *SearchRequest) ([]map[string]any, error) {
+	if len(req.Query) == 0 {
+		return nil, invalid("query not provided")
+	}
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+
+	// Search the fields of the schema
+	var schema map[string]bool = make(map[string]bool, len(req.Schema))
+	for _, name := range req.Schema {
+		schema[name] = true
+	}
+	var results []map[string]any
+	results, err = c.SearchValues(hermes.SearchParams{Query: req.Query, Limit: int(req.Limit), Schema: schema})
+	return results, failed(err)
+}
+
+// searchWithKey is a method of the server struct that implements FullText.SearchWithKey.
+// Parameters:
+//   - req (*SearchRequest): The query, key and limit params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: A status error if the params are invalid, the collection does not exist, or the search failed.
+func (s *server) searchWithKey(req *SearchRequest) ([]map[string]any, error) {
+	if len(req.Query) == 0 {
+		return nil, invalid("query not provided")
+	} else if len(req.Key) == 0 {
+		return nil, invalid("key not provided")
+	}
+	var c, err = s.cache(req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	var results []map[string]any
+	results, err = c.SearchWithKey(hermes.SearchParams{Query: req.Query, Key: req.Key, Limit: int(req.Limit)})
+	return results, failed(err)
+}
+
+// sendRecords is a function that streams the results of a search, one record per message.
+// Parameters:
+//   - stream (grpc.ServerStream): The stream of the call.
+//   - results ([]map[string]any): The records.
+//
+// Returns:
+//   - error: An error if a record could not be encoded or sent, or nil if successful.
+func sendRecords(stream grpc.ServerStream, results []map[string]any) error {
+	for _, value := range results {
+		if r, err := record("", value); err != nil {
+			return err
+		} else if err := stream.SendMsg(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
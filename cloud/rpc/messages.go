@@ -0,0 +1,334 @@
+package rpc
+
+// The messages of hermes.proto. Each message is encoded and decoded
+// by hand, in the protobuf wire format, with the numbers of its fields.
+
+// Empty is the message of the calls that return nothing.
+type Empty struct{}
+
+func (m *Empty) marshal(b []byte) []byte  { return b }
+func (m *Empty) unmarshal(b []byte) error { return decode(b, func(field) {}) }
+
+// CollectionRequest is the request of the calls on a whole collection.
+type CollectionRequest struct {
+	// The name of the collection. Empty for the default collection
+	Collection string
+}
+
+func (m *CollectionRequest) marshal(b []byte) []byte {
+	return appendString(b, 1, m.Collection)
+}
+
+func (m *CollectionRequest) unmarshal(b []byte) error {
+	return decode(b, func(f field) {
+		if f.num == 1 {
+			m.Collection = f.String()
+		}
+	})
+}
+
+// KeyRequest is the request of the calls on a record.
+type KeyRequest struct {
+	Collection string
+	Key        string
+}
+
+func (m *KeyRequest) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Collection)
+	return appendString(b, 2, m.Key)
+}
+
+func (m *KeyRequest) unmarshal(b []byte) error {
+	return decode(b, func(f field) {
+		switch f.num {
+		case 1:
+			m.Collection = f.String()
+		case 2:
+			m.Key = f.String()
+		}
+	})
+}
+
+// SetRequest is the request of Cache.Set.
+type SetRequest struct {
+	Collection string
+	Key        string
+	// The JSON-encoded record
+	Value []byte
+}
+
+func (m *SetRequest) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Collection)
+	b = appendString(b, 2, m.Key)
+	return appendBytes(b, 3, m.Value)
+}
+
+func (m *SetRequest) unmarshal(b []byte) error {
+	return decode(b, func(f field) {
+		switch f.num {
+		case 1:
+			m.Collection = f.String()
+		case 2:
+			m.Key = f.String()
+		case 3:
+			m.Value = f.Bytes()
+		}
+	})
+}
+
+// Record is a record of a collection, as returned by Cache.Get and the searches.
+type Record struct {
+	// The key of the record. Empty in the search results
+	Key string
+	// The JSON-encoded record
+	Value []byte
+}
+
+func (m *Record) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Key)
+	return appendBytes(b, 2, m.Value)
+}
+
+func (m *Record) unmarshal(b []byte) error {
+	return decode(b, func(f field) {
+		switch f.num {
+		case 1:
+			m.Key = f.String()
+		case 2:
+			m.Value = f.Bytes()
+		}
+	})
+}
+
+// BoolResponse is the response of the checks.
+type BoolResponse struct {
+	Value bool
+}
+
+func (m *BoolResponse) marshal(b []byte) []byte {
+	return appendBool(b, 1, m.Value)
+}
+
+func (m *BoolResponse) unmarshal(b []byte) error {
+	return decode(b, func(f field) {
+		if f.num == 1 {
+			m.Value = f.Bool()
+		}
+	})
+}
+
+// IntRequest is the request of the calls that set a limit of the full-text index.
+type IntRequest struct {
+	Collection string
+	Value      int64
+}
+
+func (m *IntRequest) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Collection)
+	return appendUint(b, 2, uint64(m.Value))
+}
+
+func (m *IntRequest) unmarshal(b []byte) error {
+	return decode(b, func(f field) {
+		switch f.num {
+		case 1:
+			m.Collection = f.String()
+		case 2:
+			m.Value = f.Int()
+		}
+	})
+}
+
+// IntResponse is the response of the counts.
+type IntResponse struct {
+	Value int64
+}
+
+func (m *IntResponse) marshal(b []byte) []byte {
+	return appendUint(b, 1, uint64(m.Value))
+}
+
+func (m *IntResponse) unmarshal(b []byte) error {
+	return decode(b, func(f field) {
+		if f.num == 1 {
+			m.Value = f.Int()
+		}
+	})
+}
+
+// KeysResponse is the response of Cache.Keys.
+type KeysResponse struct {
+	Keys []string
+}
+
+func (m *KeysResponse) marshal(b []byte) []byte {
+	return appendStrings(b, 1, m.Keys)
+}
+
+func (m *KeysResponse) unmarshal(b []byte) error {
+	return decode(b, func(f field) {
+		if f.num == 1 {
+			m.Keys = append(m.Keys, f.String())
+		}
+	})
+}
+
+// WatchRequest starts or cancels a subscription of a Cache.Watch stream.
+type WatchRequest struct {
+	// The id of the subscription, chosen by the client
+	ID int64
+	// Whether the subscription is cancelled, instead of started
+	Cancel     bool
+	Collection string
+	// Only send the set and delete events of the keys with this prefix
+	Prefix string
+	// Send the buffered events after this sequence number first
+	From uint64
+}
+
+func (m *WatchRequest) marshal(b []byte) []byte {
+	b = appendUint(b, 1, uint64(m.ID))
+	b = appendBool(b, 2, m.Cancel)
+	b = appendString(b, 3, m.Collection)
+	b = appendString(b, 4, m.Prefix)
+	return appendUint(b, 5, m.From)
+}
+
+func (m *WatchRequest) unmarshal(b []byte) error {
+	return decode(b, func(f field) {
+		switch f.num {
+		case 1:
+			m.ID = f.Int()
+		case 2:
+			m.Cancel = f.Bool()
+		case 3:
+			m.Collection = f.String()
+		case 4:
+			m.Prefix = f.String()
+		case 5:
+			m.From = f.Uint()
+		}
+	})
+}
+
+// Event is a change of a collection, sent by a Cache.Watch stream.
+type Event struct {
+	// The id of the subscription
+	ID   int64
+	Seq  uint64
+	Type string
+	Key  string
+	// The JSON-encoded record of a set event, or settings of an ft.init event
+	Value []byte
+	// The ids of the registered queries that the record matches
+	Queries []string
+	// Set when the subscription is dropped, because its request is invalid or
+	// the client didn't read the events fast enough
+	Error string
+}
+
+func (m *Event) marshal(b []byte) []byte {
+	b = appendUint(b, 1, uint64(m.ID))
+	b = appendUint(b, 2, m.Seq)
+	b = appendString(b, 3, m.Type)
+	b = appendString(b, 4, m.Key)
+	b = appendBytes(b, 5, m.Value)
+	b = appendStrings(b, 6, m.Queries)
+	return appendString(b, 7, m.Error)
+}
+
+func (m *Event) unmarshal(b []byte) error {
+	return decode(b, func(f field) {
+		switch f.num {
+		case 1:
+			m.ID = f.Int()
+		case 2:
+			m.Seq = f.Uint()
+		case 3:
+			m.Type = f.String()
+		case 4:
+			m.Key = f.String()
+		case 5:
+			m.Value = f.Bytes()
+		case 6:
+			m.Queries = append(m.Queries, f.String())
+		case 7:
+			m.Error = f.String()
+		}
+	})
+}
+
+// InitRequest is the request of FullText.Init.
+type InitRequest struct {
+	Collection    string
+	MaxSize       int64
+	MaxBytes      int64
+	MinWordLength int64
+	// The JSON-encoded records to initialize the index with, mapped by key. Optional
+	JSON []byte
+}
+
+func (m *InitRequest) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Collection)
+	b = appendUint(b, 2, uint64(m.MaxSize))
+	b = appendUint(b, 3, uint64(m.MaxBytes))
+	b = appendUint(b, 4, uint64(m.MinWordLength))
+	return appendBytes(b, 5, m.JSON)
+}
+
+func (m *InitRequest) unmarshal(b []byte) error {
+	return decode(b, func(f field) {
+		switch f.num {
+		case 1:
+			m.Collection = f.String()
+		case 2:
+			m.MaxSize = f.Int()
+		case 3:
+			m.MaxBytes = f.Int()
+		case 4:
+			m.MinWordLength = f.Int()
+		case 5:
+			m.JSON = f.Bytes()
+		}
+	})
+}
+
+// SearchRequest is the request of the searches of the FullText service.
+type SearchRequest struct {
+	Collection string
+	Query      string
+	Limit      int64
+	Strict     bool
+	// The field of SearchWithKey
+	Key string
+	// The fields of SearchValues
+	Schema []string
+}
+
+func (m *SearchRequest) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Collection)
+	b = appendString(b, 2, m.Query)
+	b = appendUint(b, 3, uint64(m.Limit))
+	b = appendBool(b, 4, m.Strict)
+	b = appendString(b, 5, m.Key)
+	return appendStrings(b, 6, m.Schema)
+}
+
+func (m *SearchRequest) unmarshal(b []byte) error {
+	return decode(b, func(f field) {
+		switch f.num {
+		case 1:
+			m.Collection = f.String()
+		case 2:
+			m.Query = f.String()
+		case 3:
+			m.Limit = f.Int()
+		case 4:
+			m.Strict = f.Bool()
+		case 5:
+			m.Key = f.String()
+		case 6:
+			m.Schema = append(m.Schema, f.String())
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/cloud/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Config is a struct that represents the settings of the grpc server.
+type Config struct {
+	// The authenticator of the calls. If nil, authentication is disabled
+	Auth auth.Authenticator
+	// Whether the methods that require the write or admin scope are rejected, like on a replication follower
+	ReadOnly bool
+	// A function called after each call, with the full name of the method,
+	// how long the call took, and its error. If nil, the calls aren't observed
+	Observe func(method string, duration time.Duration, err error)
+}
+
+// Scopes is the map of the scopes required to call the methods, when authentication is enabled.
+// The methods that aren't listed can't be called.
+var Scopes = map[string]auth.Scope{
+	"/hermes.v1.Cache/Set":                 auth.ScopeWrite,
+	"/hermes.v1.Cache/Get":                 auth.ScopeRead,
+	"/hermes.v1.Cache/Delete":              auth.ScopeWrite,
+	"/hermes.v1.Cache/Exists":              auth.ScopeRead,
+	"/hermes.v1.Cache/Keys":                auth.ScopeRead,
+	"/hermes.v1.Cache/Length":              auth.ScopeRead,
+	"/hermes.v1.Cache/Clean":               auth.ScopeAdmin,
+	"/hermes.v1.Cache/Watch":               auth.ScopeRead,
+	"/hermes.v1.FullText/Init":             auth.ScopeAdmin,
+	"/hermes.v1.FullText/Clean":            auth.ScopeAdmin,
+	"/hermes.v1.FullText/Search":           auth.ScopeRead,
+	"/hermes.v1.FullText/SearchOneWord":    auth.ScopeRead,
+	"/hermes.v1.FullText/SearchValues":     auth.ScopeRead,
+	"/hermes.v1.FullText/SearchWithKey":    auth.ScopeRead,
+	"/hermes.v1.FullText/SetMaxBytes":      auth.ScopeAdmin,
+	"/hermes.v1.FullText/SetMaxSize":       auth.ScopeAdmin,
+	"/hermes.v1.FullText/SetMinWordLength": auth.ScopeAdmin,
+	"/hermes.v1.FullText/IsInitialized":    auth.ScopeRead,
+	"/hermes.v1.FullText/StorageSize":      auth.ScopeRead,
+	"/hermes.v1.FullText/StorageLength":    auth.ScopeRead,
+}
+
+// server is a struct that implements the services, with the collections that they're called on.
+// Fields:
+//   - collections (*hermes.Collections): The collections.
+//   - config (Config): The settings of the server.
+type server struct {
+	collections *hermes.Collections
+	config      Config
+}
+
+// NewServer is a function that creates a grpc server of the Cache and FullText services.
+// The server uses Codec, and authenticates and observes the calls with the config.
+// Parameters:
+//   - cs (*hermes.Collections): The collections that the services are called on.
+//   - config (Config): The settings of the server.
+//   - opts (...grpc.ServerOption): The other options of the server, for example its tls credentials.
+//
+// Returns:
+//   - *grpc.Server: The server. Its Serve method serves the services on a listener.
+func NewServer(cs *hermes.Collections, config Config, opts ...grpc.ServerOption) *grpc.Server {
+	var s *server = &server{collections: cs, config: config}
+	opts = append([]grpc.ServerOption{
+		grpc.ForceServerCodec(Codec),
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor),
+	}, opts...)
+	var g *grpc.Server = grpc.NewServer(opts...)
+	g.RegisterService(&CacheServiceDesc, s)
+	g.RegisterService(&FullTextServiceDesc, s)
+	return g
+}
+
+// unaryInterceptor is a method of the server struct that authorizes and observes the unary calls.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - req (any): The request.
+//   - info (*grpc.UnaryServerInfo): The info of the method.
+//   - handler (grpc.UnaryHandler): The handler of the method.
+//
+// Returns:
+//   - any: The response.
+//   - error: A status error if the call failed, or nil if successful.
+func (s *server) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var start time.Time = time.Now()
+	var resp, err = any(nil), s.authorize(ctx, info.FullMethod)
+	if err == nil {
+		resp, err = handler(ctx, req)
+	}
+	if s.config.Observe != nil {
+		s.config.Observe(info.FullMethod, time.Since(start), err)
+	}
+	return resp, err
+}
+
+// streamInterceptor is a method of the server struct that authorizes and observes the streaming calls.
+// Parameters:
+//   - srv (any): The implementation of the service.
+//   - stream (grpc.ServerStream): The stream of the call.
+//   - info (*grpc.StreamServerInfo): The info of the method.
+//   - handler (grpc.StreamHandler): The handler of the method.
+//
+// Returns:
+//   - error: A status error if the call failed, or nil if successful.
+func (s *server) streamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	var start time.Time = time.Now()
+	var err error = s.authorize(stream.Context(), info.FullMethod)
+	if err == nil {
+		err = handler(srv, stream)
+	}
+	if s.config.Observe != nil {
+		s.config.Observe(info.FullMethod, time.Since(start), err)
+	}
+	return err
+}
+
+// authorize is a method of the server struct that checks that the credential of a call has the scope of its method.
+// The credential is read from the "authorization: Bearer ..." or the "x-api-key" metadata.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - method (string): The full name of the method.
+//
+// Returns:
+//   - error: An Unauthenticated or PermissionDenied status error, or nil if the call is allowed.
+func (s *server) authorize(ctx context.Context, method string) error {
+	if s.config.Auth == nil {
+		return s.writable(method)
+	}
+
+	// Get the credential
+	var md, _ = metadata.FromIncomingContext(ctx)
+	var credential string
+	if h := md.Get("authorization"); len(h) > 0 && len(h[0]) > 7 && strings.EqualFold(h[0][:7], "bearer ") {
+		credential = strings.TrimSpace(h[0][7:])
+	} else if key := md.Get("x-api-key"); len(key) > 0 {
+		credential = key[0]
+	}
+	if len(credential) == 0 {
+		return status.Error(codes.Unauthenticated, auth.ErrUnauthorized.Error())
+	}
+
+	// Check the scope of the identity
+	var id, err = s.config.Auth.Authenticate(credential)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, auth.ErrUnauthorized.Error())
+	} else if scope, ok := Scopes[method]; !ok {
+		return status.Errorf(codes.PermissionDenied, "%s can't be called", method)
+	} else if !id.Scope.Allows(scope) {
+		return status.Errorf(codes.PermissionDenied, "the %s scope is required", scope)
+	}
+	return s.writable(method)
+}
+
+// writable is a method of the server struct that rejects the methods that write to a read-only server.
+// Parameters:
+//   - method (string): The full name of the method.
+//
+// Returns:
+//   - error: A PermissionDenied status error if the server is read-only and the method writes, or nil.
+func (s *server) writable(method string) error {
+	if s.config.ReadOnly && Scopes[method].Writes() {
+		return status.Error(codes.PermissionDenied, auth.ErrReadOnly.Error())
+	}
+	return nil
+}
+
+// cache is a method of the server struct that gets the collection of a request.
+// Parameters:
+//   - name (string): The name of the collection. Empty for the default collection.
+//
+// Returns:
+//   - *hermes.Cache: The collection.
+//   - error: A NotFound status error if the collection does not exist, or nil if successful.
+func (s *server) cache(name string) (*hermes.Cache, error) {
+	if len(name) == 0 {
+		name = hermes.DefaultCollection
+	}
+	if c, err := s.collections.Get(name); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else {
+		return c, nil
+	}
+}
+
+// failed is a function that converts an error of the cache to a FailedPrecondition status error,
+// for example when the full-text index is not initialized.
+// Parameters:
+//   - err (error): The error. Can be nil.
+//
+// Returns:
+//   - error: The status error, or nil if the error is nil.
+func failed(err error) error {
+	if err == nil {
+		return nil
+	}
+	return status.Error(codes.FailedPrecondition, err.Error())
+}
+
+// invalid is a function that returns an InvalidArgument status error.
+// Parameters:
+//   - msg (string): The error message.
+//
+// Returns:
+//   - error: The status error.
+func invalid(msg string) error {
+	return status.Error(codes.InvalidArgument, msg)
+}
@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// CacheServiceDesc is the description of the hermes.v1.Cache service.
+var CacheServiceDesc grpc.ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hermes.v1.Cache",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		unary("hermes.v1.Cache", "Set", (*server).set),
+		unary("hermes.v1.Cache", "Get", (*server).get),
+		unary("hermes.v1.Cache", "Delete", (*server).delete),
+		unary("hermes.v1.Cache", "Exists", (*server).exists),
+		unary("hermes.v1.Cache", "Keys", (*server).keys),
+		unary("hermes.v1.Cache", "Length", (*server).length),
+		unary("hermes.v1.Cache", "Clean", (*server).clean),
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       func(srv any, stream grpc.ServerStream) error { return srv.(*server).watch(stream) },
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "hermes.proto",
+}
+
+// FullTextServiceDesc is the description of the hermes.v1.FullText service.
+var FullTextServiceDesc grpc.ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hermes.v1.FullText",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		unary("hermes.v1.FullText", "Init", (*server).ftInit),
+		unary("hermes.v1.FullText", "Clean", (*server).ftClean),
+		unary("hermes.v1.FullText", "SetMaxBytes", (*server).ftSetMaxBytes),
+		unary("hermes.v1.FullText", "SetMaxSize", (*server).ftSetMaxSize),
+		unary("hermes.v1.FullText", "SetMinWordLength", (*server).ftSetMinWordLength),
+		unary("hermes.v1.FullText", "IsInitialized", (*server).ftIsInitialized),
+		unary("hermes.v1.FullText", "StorageSize", (*server).ftStorageSize),
+		unary("hermes.v1.FullText", "StorageLength", (*server).ftStorageLength),
+	},
+	Streams: []grpc.StreamDesc{
+		searchStream("Search", (*server).search),
+		searchStream("SearchOneWord", (*server).searchOneWord),
+		searchStream("SearchValues", (*server).searchValues),
+		searchStream("SearchWithKey", (*server).searchWithKey),
+	},
+	Metadata: "hermes.proto",
+}
+
+// unary is a function that returns the description of a unary method, which decodes
+// the request and calls the implementation of the method through the interceptor.
+// Parameters:
+//   - service (string): The full name of the service.
+//   - name (string): The name of the method.
+//   - call (func(*server, context.Context, *Req) (Resp, error)): The implementation of the method.
+//
+// Returns:
+//   - grpc.MethodDesc: The description of the method.
+func unary[Req any, Resp message, PReq interface {
+	*Req
+	message
+}](service, name string, call func(*server, context.Context, PReq) (Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			var req PReq = new(Req)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			var handler grpc.UnaryHandler = func(ctx context.Context, req any) (any, error) {
+				return call(srv.(*server), ctx, req.(PReq))
+			}
+			if interceptor == nil {
+				return handler(ctx, req)
+			}
+			return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + service + "/" + name}, handler)
+		},
+	}
+}
+
+// searchStream is a function that returns the description of a search method, which
+// receives the search request and streams the records that match it.
+// Parameters:
+//   - name (string): The name of the method.
+//   - call (func(*server, *SearchRequest) ([]map[string]any, error)): The search.
+//
+// Returns:
+//   - grpc.StreamDesc: The description of the method.
+func searchStream(name string, call func(*server, *SearchRequest) ([]map[string]any, error)) grpc.StreamDesc {
+	return grpc.StreamDesc{
+		StreamName: name,
+		Handler: func(srv any, stream grpc.ServerStream) error {
+			var req *SearchRequest = &SearchRequest{}
+			if err := stream.RecvMsg(req); err != nil {
+				return err
+			}
+			var results, err = call(srv.(*server), req)
+			if err != nil {
+				return err
+			}
+			return sendRecords(stream, results)
+		},
+		ServerStreams: true,
+	}
+}
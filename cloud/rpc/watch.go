@@ -0,0 +1,182 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	hermes "github.com/realTristan/hermes"
+	"google.golang.org/grpc"
+)
+
+// watchStream is a struct that represents a Cache.Watch stream, and its subscriptions.
+// Fields:
+//   - server (*server): The server of the stream.
+//   - stream (grpc.ServerStream): The stream of the call.
+//   - write (sync.Mutex): The mutex that serializes the events that are sent by the subscriptions.
+//   - mutex (sync.Mutex): The mutex that guards the subscriptions.
+//   - subs (map[int64]*subscription): The subscriptions, mapped by id.
+//   - wg (sync.WaitGroup): The wait group of the goroutines of the subscriptions.
+type watchStream struct {
+	server *server
+	stream grpc.ServerStream
+	write  sync.Mutex
+	mutex  sync.Mutex
+	subs   map[int64]*subscription
+	wg     sync.WaitGroup
+}
+
+// subscription is a struct that represents a subscription of a Cache.Watch stream.
+// Fields:
+//   - cancel (context.CancelFunc): The function that cancels the subscription.
+type subscription struct {
+	cancel context.CancelFunc
+}
+
+// watch is a method of the server struct that implements Cache.Watch. The requests of the client start and cancel
+// subscriptions, whose events are sent until they're cancelled, the client reads them too slowly, or the call ends.
+// Once the client closes its side of the stream, the call ends when its subscriptions end.
+// Parameters:
+//   - stream (grpc.ServerStream): The stream of the call.
+//
+// Returns:
+//   - error: The error of the stream, or nil if the client closed it.
+func (s *server) watch(stream grpc.ServerStream) error {
+	var w *watchStream = &watchStream{
+		server: s,
+		stream: stream,
+		subs:   make(map[int64]*subscription),
+	}
+	defer w.wg.Wait()
+
+	// Start and cancel the subscriptions of the requests
+	for {
+		var req *WatchRequest = &WatchRequest{}
+		if err := stream.RecvMsg(req); err == io.EOF {
+			return nil
+		} else if err != nil {
+			w.cancelAll()
+			return err
+		}
+		if req.Cancel {
+			w.cancel(req.ID)
+		} else {
+			w.subscribe(req)
+		}
+	}
+}
+
+// subscribe is a method of the watchStream struct that starts a subscription.
+// Parameters:
+//   - req (*WatchRequest): The request of the subscription.
+func (w *watchStream) subscribe(req *WatchRequest) {
+	var c, err = w.server.cache(req.Collection)
+	if err != nil {
+		w.send(&Event{ID: req.ID, Error: err.Error()})
+		return
+	}
+
+	// Register the subscription
+	var ctx, cancel = context.WithCancel(w.stream.Context())
+	var sub *subscription = &subscription{cancel: cancel}
+	w.mutex.Lock()
+	if _, ok := w.subs[req.ID]; ok {
+		w.mutex.Unlock()
+		cancel()
+		w.send(&Event{ID: req.ID, Error: fmt.Sprintf("subscription %d already exists", req.ID)})
+		return
+	}
+	w.subs[req.ID] = sub
+	w.mutex.Unlock()
+
+	// Send the events of the subscription
+	var events <-chan hermes.Event = c.Watch(ctx, hermes.WatchFilter{Prefix: req.Prefix, From: req.From})
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer w.remove(req.ID, sub)
+		var last uint64 = req.From
+		for e := range events {
+			if ctx.Err() != nil {
+				return
+			} else if err := w.send(event(req.ID, e)); err != nil {
+				return
+			}
+			last = e.Seq
+		}
+
+		// The channel is closed when the context is done, or the client is too slow
+		if ctx.Err() == nil {
+			w.send(&Event{ID: req.ID, Error: fmt.Sprintf("subscription dropped, resume from %d", last)})
+		}
+	}()
+}
+
+// cancel is a method of the watchStream struct that cancels a subscription.
+// Parameters:
+//   - id (int64): The id of the subscription.
+func (w *watchStream) cancel(id int64) {
+	w.mutex.Lock()
+	var sub, ok = w.subs[id]
+	w.mutex.Unlock()
+	if !ok {
+		w.send(&Event{ID: id, Error: fmt.Sprintf("subscription %d not found", id)})
+		return
+	}
+	w.remove(id, sub)
+}
+
+// cancelAll is a method of the watchStream struct that cancels every subscription.
+func (w *watchStream) cancelAll() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for _, sub := range w.subs {
+		sub.cancel()
+	}
+}
+
+// remove is a method of the watchStream struct that cancels and removes a subscription, so that its id can be used again.
+// Parameters:
+//   - id (int64): The id of the subscription.
+//   - sub (*subscription): The subscription. A new subscription with the same id is not removed.
+func (w *watchStream) remove(id int64, sub *subscription) {
+	sub.cancel()
+	w.mutex.Lock()
+	if w.subs[id] == sub {
+		delete(w.subs, id)
+	}
+	w.mutex.Unlock()
+}
+
+// send is a method of the watchStream struct that sends an event to the client.
+// Parameters:
+//   - e (*Event): The event.
+//
+// Returns:
+//   - error: The error of the stream, or nil if successful.
+func (w *watchStream) send(e *Event) error {
+	w.write.Lock()
+	defer w.write.Unlock()
+	return w.stream.SendMsg(e)
+}
+
+// event is a function that converts an event of the cache to an event of a subscription.
+// Parameters:
+//   - id (int64): The id of the subscription.
+//   - e (hermes.Event): The event of the cache.
+//
+// Returns:
+//   - *Event: The event of the subscription.
+func event(id int64, e hermes.Event) *Event {
+	var ev *Event = &Event{ID: id, Seq: e.Seq, Type: string(e.Type), Key: e.Key, Queries: e.Queries}
+	if e.Value != nil {
+		if data, err := json.Marshal(e.Value); err != nil {
+			ev.Error = fmt.Sprintf("failed to encode the record: %v", err)
+		} else {
+			ev.Value = data
+		}
+	}
+	return ev
+}
@@ -0,0 +1,181 @@
+package rpc
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// message is the interface of the messages of the services, which are encoded in the protobuf wire format.
+type message interface {
+	// marshal appends the encoded message to a buffer.
+	marshal(b []byte) []byte
+
+	// unmarshal decodes the message. The fields that are not in the message are skipped.
+	unmarshal(b []byte) error
+}
+
+// field is a struct that represents a decoded field of a message.
+// Fields:
+//   - num (protowire.Number): The number of the field.
+//   - typ (protowire.Type): The wire type of the field.
+//   - varint (uint64): The value of a varint field.
+//   - bytes ([]byte): The value of a bytes field. It's only valid until the next field is decoded.
+type field struct {
+	num    protowire.Number
+	typ    protowire.Type
+	varint uint64
+	bytes  []byte
+}
+
+// String is a method of the field struct that returns the value of a string field.
+// Returns:
+//   - string: The string, or an empty string if the field is not a bytes field.
+func (f field) String() string {
+	if f.typ != protowire.BytesType {
+		return ""
+	}
+	return string(f.bytes)
+}
+
+// Bytes is a method of the field struct that returns a copy of the value of a bytes field.
+// Returns:
+//   - []byte: The bytes, or nil if the field is not a bytes field.
+func (f field) Bytes() []byte {
+	if f.typ != protowire.BytesType {
+		return nil
+	}
+	return append([]byte{}, f.bytes...)
+}
+
+// Int is a method of the field struct that returns the value of an int64 field.
+// Returns:
+//   - int64: The int, or 0 if the field is not a varint field.
+func (f field) Int() int64 {
+	return int64(f.Uint())
+}
+
+// Uint is a method of the field struct that returns the value of a uint64 field.
+// Returns:
+//   - uint64: The uint, or 0 if the field is not a varint field.
+func (f field) Uint() uint64 {
+	if f.typ != protowire.VarintType {
+		return 0
+	}
+	return f.varint
+}
+
+// Bool is a method of the field struct that returns the value of a bool field.
+// Returns:
+//   - bool: The bool, or false if the field is not a varint field.
+func (f field) Bool() bool {
+	return f.Uint() != 0
+}
+
+// decode is a function that decodes the fields of a message, and passes them to a function.
+// Parameters:
+//   - b ([]byte): The encoded message.
+//   - set (func(field)): The function that sets the field in the message.
+//
+// Returns:
+//   - error: An error if the message is malformed, or nil if successful.
+func decode(b []byte, set func(f field)) error {
+	for len(b) > 0 {
+		var num, typ, n = protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		// Decode the value
+		var f field = field{num: num, typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			f.varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			f.bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		set(f)
+	}
+	return nil
+}
+
+// appendString is a function that appends a string field, unless it's empty.
+// Parameters:
+//   - b ([]byte): The buffer.
+//   - num (protowire.Number): The number of the field.
+//   - s (string): The value of the field.
+//
+// Returns:
+//   - []byte: The buffer.
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if len(s) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+// appendBytes is a function that appends a bytes field, unless it's empty.
+// Parameters:
+//   - b ([]byte): The buffer.
+//   - num (protowire.Number): The number of the field.
+//   - v ([]byte): The value of the field.
+//
+// Returns:
+//   - []byte: The buffer.
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// appendUint is a function that appends a varint field, unless it's 0.
+// Parameters:
+//   - b ([]byte): The buffer.
+//   - num (protowire.Number): The number of the field.
+//   - v (uint64): The value of the field. The int64 and bool fields are converted to uint64.
+//
+// Returns:
+//   - []byte: The buffer.
+func appendUint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// appendBool is a function that appends a bool field, unless it's false.
+// Parameters:
+//   - b ([]byte): The buffer.
+//   - num (protowire.Number): The number of the field.
+//   - v (bool): The value of the field.
+//
+// Returns:
+//   - []byte: The buffer.
+func appendBool(b []byte, num protowire.Number, v bool) []byte {
+	return appendUint(b, num, protowire.EncodeBool(v))
+}
+
+// appendStrings is a function that appends a repeated string field.
+// Parameters:
+//   - b ([]byte): The buffer.
+//   - num (protowire.Number): The number of the field.
+//   - values ([]string): The values of the field.
+//
+// Returns:
+//   - []byte: The buffer.
+func appendStrings(b []byte, num protowire.Number, values []string) []byte {
+	for _, s := range values {
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendString(b, s)
+	}
+	return b
+}
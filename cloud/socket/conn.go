@@ -0,0 +1,145 @@
+package ws
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofiber/websocket/v2"
+	"github.com/realTristan/hermes/cloud/auth"
+)
+
+// Conn struct for a client connection. The writes are guarded by a mutex,
+// so that the connection can be written to from several goroutines
+type Conn struct {
+	ws       *websocket.Conn
+	mutex    *sync.Mutex
+	config   Config
+	done     chan struct{}
+	pending  []func()
+	subs     *subscriptions
+	identity *auth.Identity
+}
+
+// Subscriptions of a connection, mapped by id to the
+// functions that cancel them
+type subscriptions struct {
+	mutex   *sync.Mutex
+	next    int
+	cancels map[int]func()
+}
+
+// Create a new connection
+func newConn(ws *websocket.Conn, config Config) *Conn {
+	var identity, _ = ws.Locals("identity").(*auth.Identity)
+	return &Conn{
+		ws:     ws,
+		mutex:  &sync.Mutex{},
+		config: config,
+		done:   make(chan struct{}),
+		subs: &subscriptions{
+			mutex:   &sync.Mutex{},
+			cancels: make(map[int]func()),
+		},
+		identity: identity,
+	}
+}
+
+// Write a text message to the client
+func (c *Conn) Write(msg []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.ws.SetWriteDeadline(time.Now().Add(c.config.WriteTimeout))
+	return c.ws.WriteMessage(websocket.TextMessage, msg)
+}
+
+// Get a channel that's closed when the connection is closed
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Run a function in a new goroutine once the reply of the
+// current request has been written
+func (c *Conn) AfterReply(fn func()) {
+	c.pending = append(c.pending, fn)
+}
+
+// Start the functions that were waiting for the reply
+func (c *Conn) replied() {
+	for _, fn := range c.pending {
+		go fn()
+	}
+	c.pending = nil
+}
+
+// Register a subscription and get its id
+func (c *Conn) Subscribe(cancel func()) int {
+	c.subs.mutex.Lock()
+	defer c.subs.mutex.Unlock()
+	c.subs.next++
+	c.subs.cancels[c.subs.next] = cancel
+	return c.subs.next
+}
+
+// Cancel a subscription
+func (c *Conn) Unsubscribe(id int) bool {
+	c.subs.mutex.Lock()
+	defer c.subs.mutex.Unlock()
+	if cancel, ok := c.subs.cancels[id]; ok {
+		delete(c.subs.cancels, id)
+		cancel()
+		return true
+	}
+	return false
+}
+
+// Cancel all the subscriptions and pending functions
+// of the connection
+func (c *Conn) unsubscribeAll() {
+	c.subs.mutex.Lock()
+	defer c.subs.mutex.Unlock()
+	for id, cancel := range c.subs.cancels {
+		delete(c.subs.cancels, id)
+		cancel()
+	}
+	c.pending = nil
+}
+
+// Send a ping to the client
+func (c *Conn) ping() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.config.WriteTimeout))
+}
+
+// Extend the read deadline of the connection
+func (c *Conn) touch() error {
+	return c.ws.SetReadDeadline(time.Now().Add(c.config.IdleTimeout))
+}
+
+// Send pings to the client until the connection is closed
+func (c *Conn) heartbeat() {
+	var ticker *time.Ticker = time.NewTicker(c.config.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if c.ping() != nil {
+				// The read loop will fail on the next read deadline
+				return
+			}
+		}
+	}
+}
+
+// Close the connection
+func (c *Conn) close(code int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.ws.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, ""),
+		time.Now().Add(c.config.WriteTimeout),
+	)
+}
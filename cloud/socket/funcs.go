@@ -2,34 +2,99 @@ package ws
 
 import (
 	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/cloud/auth"
 	"github.com/realTristan/hermes/cloud/socket/handlers"
 	utils "github.com/realTristan/hermes/cloud/socket/utils"
 )
 
-// Map of functions that can be called from the client
-var Functions = map[string]func(*utils.Params, *hermes.Cache) []byte{
-	"cache.length":        handlers.Length,
-	"cache.clean":         handlers.Clean,
-	"cache.set":           handlers.Set,
-	"cache.delete":        handlers.Delete,
-	"cache.get":           handlers.Get,
-	"cache.get.all":       handlers.GetAll,
-	"cache.keys":          handlers.Keys,
-	"cache.info":          handlers.Info,
-	"cache.info.testing":  handlers.InfoForTesting,
-	"cache.exists":        handlers.Exists,
-	"ft.init":             handlers.FTInit,
-	"ft.init.json":        handlers.FTInitJson,
-	"ft.clean":            handlers.FTClean,
-	"ft.search":           handlers.Search,
-	"ft.search.oneword":   handlers.SearchOneWord,
-	"ft.search.values":    handlers.SearchValues,
-	"ft.search.withkey":   handlers.SearchWithKey,
-	"ft.maxbytes.set":     handlers.FTSetMaxBytes,
-	"ft.maxsize.set":      handlers.FTSetMaxSize,
-	"ft.storage":          handlers.FTStorage,
-	"ft.storage.size":     handlers.FTStorageSize,
-	"ft.storage.length":   handlers.FTStorageLength,
-	"ft.isinitialized":    handlers.FTIsInitialized,
-	"ft.indices.sequence": handlers.FTSequenceIndices,
+// Map of functions that manage the collections
+var CollectionFunctions = map[string]func(*utils.Params, *hermes.Collections) (any, error){
+	"collections.list":   handlers.ListCollections,
+	"collections.create": handlers.CreateCollection,
+	"collections.drop":   handlers.DropCollection,
+}
+
+// Map of functions that push messages to the client connection,
+// after their reply has been written
+var StreamFunctions = map[string]func(*utils.Request, *hermes.Cache, utils.Stream) (any, error){
+	"cache.subscribe":   handlers.Subscribe,
+	"cache.unsubscribe": handlers.Unsubscribe,
+	"ft.search.watch":   handlers.WatchSearch,
+}
+
+// Map of functions that can be called from the client.
+// The functions are called with the cache of the collection named in
+// the "collection" param, or the default collection if it's not provided.
+var Functions = map[string]func(*utils.Params, *hermes.Cache) (any, error){
+	"cache.length":          handlers.Length,
+	"cache.clean":           handlers.Clean,
+	"cache.set":             handlers.Set,
+	"cache.delete":          handlers.Delete,
+	"cache.get":             handlers.Get,
+	"cache.get.all":         handlers.GetAll,
+	"cache.keys":            handlers.Keys,
+	"cache.info":            handlers.Info,
+	"cache.info.testing":    handlers.InfoForTesting,
+	"cache.exists":          handlers.Exists,
+	"cache.schema.get":      handlers.GetSchema,
+	"cache.schema.set":      handlers.SetSchema,
+	"ft.init":               handlers.FTInit,
+	"ft.init.json":          handlers.FTInitJson,
+	"ft.clean":              handlers.FTClean,
+	"ft.search":             handlers.Search,
+	"ft.search.oneword":     handlers.SearchOneWord,
+	"ft.search.values":      handlers.SearchValues,
+	"ft.search.withkey":     handlers.SearchWithKey,
+	"ft.queries.register":   handlers.RegisterQuery,
+	"ft.queries.unregister": handlers.UnregisterQuery,
+	"ft.percolate":          handlers.Percolate,
+	"ft.maxbytes.set":       handlers.FTSetMaxBytes,
+	"ft.maxsize.set":        handlers.FTSetMaxSize,
+	"ft.minwordlength.set":  handlers.FTSetMinWordLength,
+	"ft.storage":            handlers.FTStorage,
+	"ft.storage.size":       handlers.FTStorageSize,
+	"ft.storage.length":     handlers.FTStorageLength,
+	"ft.isinitialized":      handlers.FTIsInitialized,
+	"ft.indices.sequence":   handlers.FTSequenceIndices,
+}
+
+// Map of the scopes required to call the functions, when authentication
+// is enabled. The functions that aren't listed can't be called
+var Scopes = map[string]auth.Scope{
+	"collections.list":      auth.ScopeRead,
+	"collections.create":    auth.ScopeAdmin,
+	"collections.drop":      auth.ScopeAdmin,
+	"cache.subscribe":       auth.ScopeRead,
+	"cache.unsubscribe":     auth.ScopeRead,
+	"ft.search.watch":       auth.ScopeRead,
+	"cache.length":          auth.ScopeRead,
+	"cache.clean":           auth.ScopeAdmin,
+	"cache.set":             auth.ScopeWrite,
+	"cache.delete":          auth.ScopeWrite,
+	"cache.get":             auth.ScopeRead,
+	"cache.get.all":         auth.ScopeRead,
+	"cache.keys":            auth.ScopeRead,
+	"cache.info":            auth.ScopeRead,
+	"cache.info.testing":    auth.ScopeAdmin,
+	"cache.exists":          auth.ScopeRead,
+	"cache.schema.get":      auth.ScopeRead,
+	"cache.schema.set":      auth.ScopeAdmin,
+	"ft.init":               auth.ScopeAdmin,
+	"ft.init.json":          auth.ScopeAdmin,
+	"ft.clean":              auth.ScopeAdmin,
+	"ft.search":             auth.ScopeRead,
+	"ft.search.oneword":     auth.ScopeRead,
+	"ft.search.values":      auth.ScopeRead,
+	"ft.search.withkey":     auth.ScopeRead,
+	"ft.queries.register":   auth.ScopeWrite,
+	"ft.queries.unregister": auth.ScopeWrite,
+	"ft.percolate":          auth.ScopeRead,
+	"ft.maxbytes.set":       auth.ScopeAdmin,
+	"ft.maxsize.set":        auth.ScopeAdmin,
+	"ft.minwordlength.set":  auth.ScopeAdmin,
+	"ft.storage":            auth.ScopeAdmin,
+	"ft.storage.size":       auth.ScopeRead,
+	"ft.storage.length":     auth.ScopeRead,
+	"ft.isinitialized":      auth.ScopeRead,
+	"ft.indices.sequence":   auth.ScopeAdmin,
 }
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	hermes "github.com/realTristan/hermes"
+	utils "github.com/realTristan/hermes/cloud/socket/utils"
+)
+
+// ListCollections is a handler function for listing the collections.
+// Parameters:
+//   - _ (*utils.Params): A pointer to a utils.Params struct (unused).
+//   - cs (*hermes.Collections): A pointer to a hermes.Collections struct.
+//
+// Returns:
+//   - any: The collection names.
+//   - error: Always nil.
+func ListCollections(_ *utils.Params, cs *hermes.Collections) (any, error) {
+	return cs.List(), nil
+}
+
+// CreateCollection is a handler function for creating a collection.
+// Parameters:
+//   - p (*utils.Params): A pointer to a utils.Params struct.
+//   - cs (*hermes.Collections): A pointer to a hermes.Collections struct.
+//
+// Returns:
+//   - any: A nil result.
+//   - error: An error if the name is invalid or the collection already exists.
+func CreateCollection(p *utils.Params, cs *hermes.Collections) (any, error) {
+	var name, err = utils.GetNameParam(p)
+	if err != nil {
+		return nil, utils.BadRequest(err)
+	}
+
+	// Create the collection
+	_, err = cs.Create(name)
+	return nil, err
+}
+
+// DropCollection is a handler function for dropping a collection.
+// Parameters:
+//   - p (*utils.Params): A pointer to a utils.Params struct.
+//   - cs (*hermes.Collections): A pointer to a hermes.Collections struct.
+//
+// Returns:
+//   - any: A nil result.
+//   - error: An error if the name is invalid or the collection can't be dropped.
+func DropCollection(p *utils.Params, cs *hermes.Collections) (any, error) {
+	var name, err = utils.GetNameParam(p)
+	if err != nil {
+		return nil, utils.BadRequest(err)
+	}
+
+	// Drop the collection
+	return nil, cs.Drop(name)
+}
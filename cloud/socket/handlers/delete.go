@@ -5,24 +5,22 @@ import (
 	utils "github.com/realTristan/hermes/cloud/socket/utils"
 )
 
-// Delete is a handler function that returns a fiber context handler function for deleting a key from the cache.
+// Delete is a handler function for deleting a key from the cache.
 // Parameters:
 //   - p (*utils.Params): A pointer to a utils.Params struct.
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing a success message or an error message if the key is not provided or the deletion fails.
-func Delete(p *utils.Params, c *hermes.Cache) []byte {
+//   - any: A nil result.
+//   - error: An error if the key is not provided.
+func Delete(p *utils.Params, c *hermes.Cache) (any, error) {
 	// Get the key from the query
-	var (
-		key string
-		err error
-	)
-	if key, err = utils.GetKeyParam(p); err != nil {
-		return utils.Error("key not provided")
+	var key, err = utils.GetKeyParam(p)
+	if err != nil {
+		return nil, utils.BadRequest("key not provided")
 	}
 
 	// Delete the key from the cache
 	c.Delete(key)
-	return utils.Success("null")
+	return nil, nil
 }
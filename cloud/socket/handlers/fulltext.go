@@ -1,108 +1,111 @@
 package handlers
 
 import (
-	"encoding/json"
-
 	hermes "github.com/realTristan/hermes"
 	utils "github.com/realTristan/hermes/cloud/socket/utils"
 )
 
-// FTIsInitialized is a handler function that returns a fiber context handler function for checking if the full-text storage is initialized.
+// FTIsInitialized is a handler function for checking if the full-text storage is initialized.
 // Parameters:
 //   - _ (*utils.Params): A pointer to a utils.Params struct (unused).
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing a boolean value indicating whether the full-text storage is initialized.
-func FTIsInitialized(_ *utils.Params, c *hermes.Cache) []byte {
-	return utils.Success(c.FTIsInitialized())
+//   - any: A boolean value indicating whether the full-text storage is initialized.
+//   - error: Always nil.
+func FTIsInitialized(_ *utils.Params, c *hermes.Cache) (any, error) {
+	return c.FTIsInitialized(), nil
 }
 
-// FTSetMaxBytes is a handler function that returns a fiber context handler function for setting the maximum number of bytes for the full-text storage.
+// FTSetMaxBytes is a handler function for setting the maximum number of bytes for the full-text storage.
 // Parameters:
 //   - p (*utils.Params): A pointer to a utils.Params struct.
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing a success message or an error message if the value is invalid or the setting fails.
-func FTSetMaxBytes(p *utils.Params, c *hermes.Cache) []byte {
+//   - any: A nil result.
+//   - error: An error if the value is invalid or the setting fails.
+func FTSetMaxBytes(p *utils.Params, c *hermes.Cache) (any, error) {
 	// Get the value from the query
 	var value int
 	if err := utils.GetMaxBytesParam(p, &value); err != nil {
-		return utils.Error(err)
+		return nil, utils.BadRequest(err)
 	}
 
 	// Set the max bytes
-	if err := c.FTSetMaxBytes(value); err != nil {
-		return utils.Error(err)
-	}
-	return utils.Success("null")
+	return nil, c.FTSetMaxBytes(value)
 }
 
-// FTSetMaxSize is a handler function that returns a fiber context handler function for setting the maximum length for the full-text storage.
+// FTSetMaxSize is a handler function for setting the maximum length for the full-text storage.
 // Parameters:
 //   - p (*utils.Params): A pointer to a utils.Params struct.
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing a success message or an error message if the value is invalid or the setting fails.
-func FTSetMaxSize(p *utils.Params, c *hermes.Cache) []byte {
+//   - any: A nil result.
+//   - error: An error if the value is invalid or the setting fails.
+func FTSetMaxSize(p *utils.Params, c *hermes.Cache) (any, error) {
 	// Get the value from the query
 	var value int
 	if err := utils.GetMaxSizeParam(p, &value); err != nil {
-		return utils.Error(err)
+		return nil, utils.BadRequest(err)
 	}
 
 	// Set the max length
-	if err := c.FTSetMaxSize(value); err != nil {
-		return utils.Error(err)
+	return nil, c.FTSetMaxSize(value)
+}
+
+// FTSetMinWordLength is a handler function for setting the minimum length of the words in the full-text storage.
+// Parameters:
+//   - p (*utils.Params): A pointer to a utils.Params struct.
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//
+// Returns:
+//   - any: A nil result.
+//   - error: An error if the value is invalid or the setting fails.
+func FTSetMinWordLength(p *utils.Params, c *hermes.Cache) (any, error) {
+	// Get the value from the query
+	var value int
+	if err := utils.GetMinWordLengthParam(p, &value); err != nil {
+		return nil, utils.BadRequest(err)
 	}
-	return utils.Success("null")
+
+	// Set the min word length
+	return nil, c.FTSetMinWordLength(value)
 }
 
-// FTStorage is a handler function that returns a fiber context handler function for retrieving the full-text storage.
+// FTStorage is a handler function for retrieving the full-text storage.
 // Parameters:
 //   - _ (*utils.Params): A pointer to a utils.Params struct (unused).
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing the full-text storage or an error message if the retrieval fails.
-func FTStorage(_ *utils.Params, c *hermes.Cache) []byte {
-	if data, err := c.FTStorage(); err != nil {
-		return utils.Error(err)
-	} else if data, err := json.Marshal(data); err != nil {
-		return utils.Error(err)
-	} else {
-		return data
-	}
+//   - any: The full-text storage.
+//   - error: An error if the full-text storage is not initialized.
+func FTStorage(_ *utils.Params, c *hermes.Cache) (any, error) {
+	return c.FTStorage()
 }
 
-// FTStorageLength is a handler function that returns a fiber context handler function for retrieving the length of the full-text storage.
+// FTStorageLength is a handler function for retrieving the length of the full-text storage.
 // Parameters:
 //   - _ (*utils.Params): A pointer to a utils.Params struct (unused).
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing the length of the full-text storage or an error message if the retrieval fails.
-func FTStorageLength(_ *utils.Params, c *hermes.Cache) []byte {
-	if length, err := c.FTStorageLength(); err != nil {
-		return utils.Error(err)
-	} else {
-		return utils.Success(length)
-	}
+//   - any: The length of the full-text storage.
+//   - error: An error if the full-text storage is not initialized.
+func FTStorageLength(_ *utils.Params, c *hermes.Cache) (any, error) {
+	return c.FTStorageLength()
 }
 
-// FTStorageSize is a handler function that returns a fiber context handler function for retrieving the size of the full-text storage.
+// FTStorageSize is a handler function for retrieving the size of the full-text storage.
 // Parameters:
 //   - _ (*utils.Params): A pointer to a utils.Params struct (unused).
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing the size of the full-text storage or an error message if the retrieval fails.
-func FTStorageSize(_ *utils.Params, c *hermes.Cache) []byte {
-	if size, err := c.FTStorageSize(); err != nil {
-		return utils.Error(err)
-	} else {
-		return utils.Success(size)
-	}
+//   - any: The size of the full-text storage, in bytes.
+//   - error: An error if the full-text storage is not initialized.
+func FTStorageSize(_ *utils.Params, c *hermes.Cache) (any, error) {
+	return c.FTStorageSize()
 }
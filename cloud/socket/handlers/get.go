@@ -1,44 +1,43 @@
 package handlers
 
 import (
-	"encoding/json"
-
 	hermes "github.com/realTristan/hermes"
 	utils "github.com/realTristan/hermes/cloud/socket/utils"
 )
 
-// Get is a handler function that returns a fiber context handler function for retrieving a key from the cache.
+// Get is a handler function for retrieving a key from the cache.
 // Parameters:
 //   - p (*utils.Params): A pointer to a utils.Params struct.
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing the value of the key or an error message if the key is not provided or the retrieval fails.
-func Get(p *utils.Params, c *hermes.Cache) []byte {
+//   - any: The value of the key, or nil if the key doesn't exist.
+//   - error: An error if the key is not provided.
+func Get(p *utils.Params, c *hermes.Cache) (any, error) {
 	// Get the key from the query
-	var (
-		key string
-		err error
-	)
-	if key, err = utils.GetKeyParam(p); err != nil {
-		return utils.Error("key not provided")
+	var key, err = utils.GetKeyParam(p)
+	if err != nil {
+		return nil, utils.BadRequest("key not provided")
 	}
 
 	// Get the value from the cache
-	if data, err := json.Marshal(c.Get(key)); err != nil {
-		return utils.Error(err)
-	} else {
-		return data
-	}
+	return c.Get(key), nil
 }
 
-// GetAll is a handler function that returns a fiber context handler function for retrieving all data from the cache.
+// GetAll is a handler function for retrieving all data from the cache.
 // Parameters:
 //   - _ (*utils.Params): A pointer to a utils.Params struct (unused).
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing all data from the cache or an error message if the retrieval fails.
-func GetAll(_ *utils.Params, c *hermes.Cache) []byte {
-	return nil
+//   - any: All the cache records, mapped by key.
+//   - error: Always nil.
+func GetAll(_ *utils.Params, c *hermes.Cache) (any, error) {
+	var data map[string]map[string]any = make(map[string]map[string]any)
+	for _, key := range c.Keys() {
+		if value := c.Get(key); value != nil {
+			data[key] = value
+		}
+	}
+	return data, nil
 }
@@ -12,13 +12,10 @@ import (
 //   - c: A pointer to a hermes.Cache struct representing the cache to get information from.
 //
 // Returns:
-//   - A byte slice representing the information about the cache.
-func Info(_ *utils.Params, c *hermes.Cache) []byte {
-	if info, err := c.Info(); err != nil {
-		return utils.Error(err)
-	} else {
-		return utils.Success(info)
-	}
+//   - The information about the cache.
+//   - An error if the full-text index is not initialized.
+func Info(_ *utils.Params, c *hermes.Cache) (any, error) {
+	return c.Info()
 }
 
 // InfoForTesting is a function that returns information about the cache for testing purposes.
@@ -28,11 +25,8 @@ func Info(_ *utils.Params, c *hermes.Cache) []byte {
 //   - c: A pointer to a hermes.Cache struct representing the cache to get information from.
 //
 // Returns:
-//   - A byte slice representing the information about the cache for testing purposes.
-func InfoForTesting(_ *utils.Params, c *hermes.Cache) []byte {
-	if info, err := c.InfoForTesting(); err != nil {
-		return utils.Error(err)
-	} else {
-		return utils.Success(info)
-	}
+//   - The information about the cache for testing purposes.
+//   - An error if the full-text index is not initialized.
+func InfoForTesting(_ *utils.Params, c *hermes.Cache) (any, error) {
+	return c.InfoForTesting()
 }
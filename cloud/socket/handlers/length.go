@@ -5,13 +5,14 @@ import (
 	utils "github.com/realTristan/hermes/cloud/socket/utils"
 )
 
-// Length is a handler function that returns a fiber context handler function for retrieving the length of the cache.
+// Length is a handler function for retrieving the length of the cache.
 // Parameters:
 //   - _ (*utils.Params): A pointer to a utils.Params struct (unused).
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing the length of the cache.
-func Length(_ *utils.Params, c *hermes.Cache) []byte {
-	return utils.Success(c.Length())
+//   - any: The length of the cache.
+//   - error: Always nil.
+func Length(_ *utils.Params, c *hermes.Cache) (any, error) {
+	return c.Length(), nil
 }
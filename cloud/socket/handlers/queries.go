@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	hermes "github.com/realTristan/hermes"
+	utils "github.com/realTristan/hermes/cloud/socket/utils"
+)
+
+// RegisterQuery is a handler function for registering a search query, so that documents can be percolated against it.
+// Parameters:
+//   - p (*utils.Params): A pointer to a utils.Params struct.
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//
+// Returns:
+//   - any: A nil result.
+//   - error: An error if the params are invalid or the id is already registered.
+func RegisterQuery(p *utils.Params, c *hermes.Cache) (any, error) {
+	var (
+		id     string
+		query  string
+		strict bool
+		err    error
+	)
+
+	// Get the id and query from the params
+	if id, err = utils.GetIDParam(p); err != nil {
+		return nil, utils.BadRequest(err)
+	} else if query, err = utils.GetQueryParam(p); err != nil {
+		return nil, utils.BadRequest("query not provided")
+	}
+
+	// Get the strict from the params
+	if err := utils.GetStrictParam(p, &strict); err != nil {
+		return nil, utils.BadRequest(err)
+	}
+
+	// Register the query
+	return nil, c.RegisterQuery(id, hermes.SearchParams{
+		Query:  query,
+		Strict: strict,
+	})
+}
+
+// UnregisterQuery is a handler function for removing a registered search query.
+// Parameters:
+//   - p (*utils.Params): A pointer to a utils.Params struct.
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//
+// Returns:
+//   - any: A nil result.
+//   - error: An error if the id is invalid or no query is registered with it.
+func UnregisterQuery(p *utils.Params, c *hermes.Cache) (any, error) {
+	if id, err := utils.GetIDParam(p); err != nil {
+		return nil, utils.BadRequest(err)
+	} else if err := c.UnregisterQuery(id); err != nil {
+		return nil, utils.NotFound(err)
+	}
+	return nil, nil
+}
+
+// Percolate is a handler function for finding the registered queries that a document matches.
+// Parameters:
+//   - p (*utils.Params): A pointer to a utils.Params struct.
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//
+// Returns:
+//   - any: The ids of the matching queries.
+//   - error: An error if the document is invalid or the full-text index is not initialized.
+func Percolate(p *utils.Params, c *hermes.Cache) (any, error) {
+	var doc map[string]any
+	if err := utils.GetValueParam(p, &doc); err != nil {
+		return nil, utils.BadRequest(err)
+	}
+	return c.Percolate(doc)
+}
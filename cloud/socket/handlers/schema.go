@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	hermes "github.com/realTristan/hermes"
+	utils "github.com/realTristan/hermes/cloud/socket/utils"
+)
+
+// GetSchema is a handler function for getting the schema of the cache records.
+// Parameters:
+//   - _ (*utils.Params): A pointer to a utils.Params struct (unused).
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//
+// Returns:
+//   - any: The schema, or nil if no schema is set.
+//   - error: Always nil.
+func GetSchema(_ *utils.Params, c *hermes.Cache) (any, error) {
+	return c.GetSchema(), nil
+}
+
+// SetSchema is a handler function for setting the schema of the cache records.
+// Parameters:
+//   - p (*utils.Params): A pointer to a utils.Params struct.
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//
+// Returns:
+//   - any: A nil result.
+//   - error: An error if the schema is invalid or an existing record doesn't match it.
+func SetSchema(p *utils.Params, c *hermes.Cache) (any, error) {
+	var schema *hermes.Schema
+
+	// Get the schema from the params (null removes the schema)
+	if err := utils.GetValueParam(p, &schema); err != nil {
+		return nil, utils.BadRequest(err)
+	}
+
+	// Set the schema
+	return nil, c.SetSchema(schema)
+}
@@ -1,20 +1,19 @@
 package handlers
 
 import (
-	"encoding/json"
-
 	hermes "github.com/realTristan/hermes"
 	utils "github.com/realTristan/hermes/cloud/socket/utils"
 )
 
-// Search is a handler function that returns a fiber context handler function for searching the cache for a query.
+// Search is a handler function for searching the cache for a query.
 // Parameters:
 //   - p (*utils.Params): A pointer to a utils.Params struct.
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing the search results or an error message if the search fails.
-func Search(p *utils.Params, c *hermes.Cache) []byte {
+//   - any: The search results.
+//   - error: An error if the params are invalid or the search fails.
+func Search(p *utils.Params, c *hermes.Cache) (any, error) {
 	var (
 		strict bool
 		query  string
@@ -24,41 +23,36 @@ func Search(p *utils.Params, c *hermes.Cache) []byte {
 
 	// Get the query from the params
 	if query, err = utils.GetQueryParam(p); err != nil {
-		return utils.Error("query not provided")
+		return nil, utils.BadRequest("query not provided")
 	}
 
 	// Get the limit from the params
 	if err := utils.GetLimitParam(p, &limit); err != nil {
-		return utils.Error(err)
+		return nil, utils.BadRequest(err)
 	}
 
 	// Get the strict from the params
 	if err := utils.GetStrictParam(p, &strict); err != nil {
-		return utils.Error(err)
+		return nil, utils.BadRequest(err)
 	}
 
 	// Search for the query
-	if res, err := c.Search(hermes.SearchParams{
+	return c.Search(hermes.SearchParams{
 		Query:  query,
 		Limit:  limit,
 		Strict: strict,
-	}); err != nil {
-		return utils.Error(err)
-	} else if data, err := json.Marshal(res); err != nil {
-		return utils.Error(err)
-	} else {
-		return data
-	}
+	})
 }
 
-// SearchOneWord is a handler function that returns a fiber context handler function for searching the cache for a single word query.
+// SearchOneWord is a handler function for searching the cache for a single word query.
 // Parameters:
 //   - p (*utils.Params): A pointer to a utils.Params struct.
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing the search results or an error message if the search fails.
-func SearchOneWord(p *utils.Params, c *hermes.Cache) []byte {
+//   - any: The search results.
+//   - error: An error if the params are invalid or the search fails.
+func SearchOneWord(p *utils.Params, c *hermes.Cache) (any, error) {
 	var (
 		strict bool
 		query  string
@@ -68,43 +62,36 @@ func SearchOneWord(p *utils.Params, c *hermes.Cache) []byte {
 
 	// Get the query from the params
 	if query, err = utils.GetQueryParam(p); err != nil {
-		return utils.Error("invalid query")
+		return nil, utils.BadRequest("invalid query")
 	}
 
 	// Get the limit from the params
 	if err := utils.GetLimitParam(p, &limit); err != nil {
-		return utils.Error(err)
+		return nil, utils.BadRequest(err)
 	}
 
 	// Get the strict from the params
 	if err := utils.GetStrictParam(p, &strict); err != nil {
-		return utils.Error(err)
+		return nil, utils.BadRequest(err)
 	}
 
 	// Search for the query
-	if res, err := c.SearchOneWord(hermes.SearchParams{
+	return c.SearchOneWord(hermes.SearchParams{
 		Query:  query,
 		Limit:  limit,
 		Strict: strict,
-	}); err != nil {
-		return utils.Error(err)
-	} else {
-		if data, err := json.Marshal(res); err != nil {
-			return utils.Error(err)
-		} else {
-			return data
-		}
-	}
+	})
 }
 
-// SearchValues is a handler function that returns a fiber context handler function for searching the cache for a query in values.
+// SearchValues is a handler function for searching the cache for a query in values.
 // Parameters:
 //   - p (*utils.Params): A pointer to a utils.Params struct.
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing the search results or an error message if the search fails.
-func SearchValues(p *utils.Params, c *hermes.Cache) []byte {
+//   - any: The search results.
+//   - error: An error if the params are invalid or the search fails.
+func SearchValues(p *utils.Params, c *hermes.Cache) (any, error) {
 	var (
 		query  string
 		limit  int
@@ -114,43 +101,36 @@ func SearchValues(p *utils.Params, c *hermes.Cache) []byte {
 
 	// Get the query from the params
 	if query, err = utils.GetQueryParam(p); err != nil {
-		return utils.Error("invalid query")
+		return nil, utils.BadRequest("invalid query")
 	}
 
 	// Get the limit from the params
 	if err := utils.GetLimitParam(p, &limit); err != nil {
-		return utils.Error(err)
+		return nil, utils.BadRequest(err)
 	}
 
 	// Get the schema from the params
 	if err := utils.GetSchemaParam(p, &schema); err != nil {
-		return utils.Error(err)
+		return nil, utils.BadRequest(err)
 	}
 
 	// Search for the query
-	if res, err := c.SearchValues(hermes.SearchParams{
+	return c.SearchValues(hermes.SearchParams{
 		Query:  query,
 		Limit:  limit,
 		Schema: schema,
-	}); err != nil {
-		return utils.Error(err)
-	} else {
-		if data, err := json.Marshal(res); err != nil {
-			return utils.Error(err)
-		} else {
-			return data
-		}
-	}
+	})
 }
 
-// SearchWithKey is a handler function that returns a fiber context handler function for searching the cache for a query with a specific key.
+// SearchWithKey is a handler function for searching the cache for a query with a specific key.
 // Parameters:
 //   - p (*utils.Params): A pointer to a utils.Params struct.
 //   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
 //
 // Returns:
-//   - []byte: A JSON-encoded byte slice containing the search results or an error message if the search fails.
-func SearchWithKey(p *utils.Params, c *hermes.Cache) []byte {
+//   - any: The search results.
+//   - error: An error if the params are invalid or the search fails.
+func SearchWithKey(p *utils.Params, c *hermes.Cache) (any, error) {
 	var (
 		key    string
 		query  string
@@ -161,36 +141,28 @@ func SearchWithKey(p *utils.Params, c *hermes.Cache) []byte {
 
 	// Get the query from the params
 	if query, err = utils.GetQueryParam(p); err != nil {
-		return utils.Error("invalid query")
+		return nil, utils.BadRequest("invalid query")
 	}
 
 	// Get the key from the params
 	if key, err = utils.GetKeyParam(p); err != nil {
-		return utils.Error("invalid key")
+		return nil, utils.BadRequest("invalid key")
 	}
 
 	// Get the limit from the params
 	if err := utils.GetLimitParam(p, &limit); err != nil {
-		return utils.Error(err)
+		return nil, utils.BadRequest(err)
 	}
 
 	// Get the schema from the params
 	if err := utils.GetSchemaParam(p, &schema); err != nil {
-		return utils.Error(err)
+		return nil, utils.BadRequest(err)
 	}
 
 	// Search for the query
-	if res, err := c.SearchWithKey(hermes.SearchParams{
+	return c.SearchWithKey(hermes.SearchParams{
 		Query: query,
 		Key:   key,
 		Limit: limit,
-	}); err != nil {
-		return utils.Error(err)
-	} else {
-		if data, err := json.Marshal(res); err != nil {
-			return utils.Error(err)
-		} else {
-			return data
-		}
-	}
+	})
 }
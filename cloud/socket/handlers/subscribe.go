@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	hermes "github.com/realTristan/hermes"
+	utils "github.com/realTristan/hermes/cloud/socket/utils"
+)
+
+// Subscribe is a handler function for streaming the changes made to the cache to the client.
+// The events are pushed once the reply has been written. If the client doesn't read the events fast enough,
+// the subscription is dropped with an overflow error, and the client can subscribe again from the last sequence number it received.
+// Parameters:
+//   - r (*utils.Request): A pointer to the subscribe request.
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//   - s (utils.Stream): The connection to push the events to.
+//
+// Returns:
+//   - any: The id of the subscription, and the sequence number of the last change made before the subscription.
+//   - error: An error if the prefix or from params are invalid.
+func Subscribe(r *utils.Request, c *hermes.Cache, s utils.Stream) (any, error) {
+	var filter hermes.WatchFilter
+	if prefix, err := utils.GetPrefixParam(r.Params); err != nil {
+		return nil, utils.BadRequest(err)
+	} else {
+		filter.Prefix = prefix
+	}
+	if err := utils.GetFromParam(r.Params, &filter.From); err != nil {
+		return nil, utils.BadRequest(err)
+	}
+
+	// Watch the cache. The sequence number is read with the watch, so that
+	// no change is missed between them
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		events, seq = c.WatchWithSeq(ctx, filter)
+		sub         = s.Subscribe(cancel)
+	)
+
+	// Push the events once the reply has been written
+	var last uint64 = seq
+	if filter.From > 0 {
+		last = filter.From
+	}
+	s.AfterReply(func() {
+		push(ctx, r, s, sub, events, func(e hermes.Event) {
+			last = e.Seq
+		}, func() string {
+			return fmt.Sprintf("subscription dropped, resume from %d", last)
+		})
+	})
+
+	// Return the subscription
+	return map[string]any{"subscription": sub, "seq": seq}, nil
+}
+
+// WatchSearch is a handler function for registering a standing search query, and pushing the changes in its results to the client.
+// The current results are pushed first as added updates, once the reply has been written.
+// Parameters:
+//   - r (*utils.Request): A pointer to the watch request.
+//   - c (*hermes.Cache): A pointer to a hermes.Cache struct.
+//   - s (utils.Stream): The connection to push the updates to.
+//
+// Returns:
+//   - any: The id of the subscription.
+//   - error: An error if the params are invalid, or the full-text index is not initialized.
+func WatchSearch(r *utils.Request, c *hermes.Cache, s utils.Stream) (any, error) {
+	var (
+		strict bool
+		query  string
+		err    error
+	)
+
+	// Get the query from the params
+	if query, err = utils.GetQueryParam(r.Params); err != nil {
+		return nil, utils.BadRequest("query not provided")
+	}
+
+	// Get the strict from the params
+	if err := utils.GetStrictParam(r.Params, &strict); err != nil {
+		return nil, utils.BadRequest(err)
+	}
+
+	// Watch the query
+	var ctx, cancel = context.WithCancel(context.Background())
+	updates, err := c.WatchSearch(ctx, hermes.SearchParams{
+		Query:  query,
+		Strict: strict,
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	var sub int = s.Subscribe(cancel)
+
+	// Push the updates once the reply has been written
+	s.AfterReply(func() {
+		push(ctx, r, s, sub, updates, nil, func() string {
+			return "subscription dropped, watch the query again"
+		})
+	})
+
+	// Return the subscription
+	return map[string]any{"subscription": sub}, nil
+}
+
+// push is a function that pushes the values of a subscription channel to the client, until the subscription
+// is cancelled or the connection is closed. If the channel is closed because the client is too slow, an overflow error is pushed.
+// Parameters:
+//   - ctx (context.Context): The context of the subscription.
+//   - r (*utils.Request): A pointer to the request that created the subscription.
+//   - s (utils.Stream): The connection to push the values to.
+//   - sub (int): The id of the subscription.
+//   - ch (<-chan T): The channel of values.
+//   - sent (func(T)): A function that is called after each value is pushed. Can be nil.
+//   - overflow (func() string): A function that returns the message of the overflow error.
+//
+// Returns:
+//   - None
+func push[T any](ctx context.Context, r *utils.Request, s utils.Stream, sub int, ch <-chan T, sent func(T), overflow func() string) {
+	defer s.Unsubscribe(sub)
+	for {
+		select {
+		case <-s.Done():
+			return
+		case <-ctx.Done():
+			return
+		case v, ok := <-ch:
+			if ctx.Err() != nil {
+				return
+			} else if !ok {
+				// The client didn't read the values fast enough
+				s.Write(utils.Pushed(r, sub, nil, &utils.Error{
+					Code:    utils.CodeOverflow,
+					Message: overflow(),
+				}))
+				return
+			}
+			if s.Write(utils.Pushed(r, sub, v, nil)) != nil {
+				return
+			}
+			if sent != nil {
+				sent(v)
+			}
+		}
+	}
+}
+
+// Unsubscribe is a handler function for cancelling a subscription of the client.
+// Parameters:
+//   - r (*utils.Request): A pointer to the unsubscribe request.
+//   - _ (*hermes.Cache): A pointer to a hermes.Cache struct (unused).
+//   - s (utils.Stream): The connection of the subscription.
+//
+// Returns:
+//   - any: A nil result.
+//   - error: An error if the subscription param is invalid, or the subscription does not exist.
+func Unsubscribe(r *utils.Request, _ *hermes.Cache, s utils.Stream) (any, error) {
+	var sub int
+	if err := utils.GetSubscriptionParam(r.Params, &sub); err != nil {
+		return nil, utils.BadRequest(err)
+	} else if !s.Unsubscribe(sub) {
+		return nil, utils.NotFound(fmt.Sprintf("subscription %d not found", sub))
+	}
+	return nil, nil
+}
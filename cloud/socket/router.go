@@ -1,48 +1,80 @@
 package ws
 
 import (
+	"fmt"
 	"log"
-	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
 	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/cloud/auth"
 	utils "github.com/realTristan/hermes/cloud/socket/utils"
 )
 
-// Set the router for the socket
-func SetRouter(app *fiber.App, cache *hermes.Cache) {
+// Set the router for the socket with the default settings
+func SetRouter(app *fiber.App, cs *hermes.Collections) *Socket {
+	return SetRouterWithConfig(app, cs, DefaultConfig())
+}
+
+// Set the router for the socket. Each client connection is
+// served by its own goroutine, up to config.MaxConnections
+func SetRouterWithConfig(app *fiber.App, cs *hermes.Collections, config Config) *Socket {
 	// Init a new socket
-	var socket *Socket = &Socket{
-		active: false,
-		mutex:  &sync.Mutex{},
-	}
+	var socket *Socket = NewSocket(config)
 
 	// Middleware
 	app.Use("/ws", func(c *fiber.Ctx) error {
-		// Check if the socket is active
-		if socket.IsActive() {
-			return fiber.ErrLocked
-		}
-
 		// Check if the request is via socket
-		if websocket.IsWebSocketUpgrade(c) {
-			// Allow Locals
-			c.Locals("allowed", true)
+		if !websocket.IsWebSocketUpgrade(c) {
+			return fiber.ErrUpgradeRequired
+		}
 
-			// Set the socket to active
-			socket.SetActive()
+		// Check if the socket can accept the connection
+		if socket.IsFull() {
+			return fiber.ErrServiceUnavailable
+		}
 
-			// Return the next handler
-			return c.Next()
+		// Authenticate the client. The scope of each function
+		// is checked when it's called
+		if config.Auth != nil {
+			if id, err := auth.Authenticate(c, config.Auth); err != nil {
+				c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+				return fiber.ErrUnauthorized
+			} else {
+				c.Locals("identity", id)
+			}
 		}
 
-		// Return an error
-		return fiber.ErrUpgradeRequired
+		// Allow Locals
+		c.Locals("allowed", true)
+
+		// Return the next handler
+		return c.Next()
 	})
 
 	// Main websocket handler
-	app.Get("/ws/hermes", websocket.New(func(c *websocket.Conn) {
+	app.Get("/ws/hermes", websocket.New(func(ws *websocket.Conn) {
+		var c *Conn = newConn(ws, socket.config)
+
+		// Reserve a connection slot. The middleware check can be
+		// passed by several clients at the same time
+		if !socket.acquire() {
+			c.close(websocket.CloseTryAgainLater)
+			return
+		}
+		defer socket.release()
+		defer close(c.done)
+		defer c.unsubscribeAll()
+
+		// Close the connection if the client stops answering
+		c.touch()
+		ws.SetPongHandler(func(string) error {
+			return c.touch()
+		})
+		go c.heartbeat()
+
+		// Read the messages
 		for {
 			var (
 				msg []byte
@@ -50,38 +82,85 @@ func SetRouter(app *fiber.App, cache *hermes.Cache) {
 			)
 
 			// Read the message
-			if _, msg, err = c.ReadMessage(); err != nil {
-				log.Println("read:", err)
-				if IsCloseError(err) {
-					socket.SetInactive()
+			if _, msg, err = ws.ReadMessage(); err != nil {
+				if !IsCloseError(err) {
+					log.Println("read:", err)
 				}
 				break
 			}
+			c.touch()
 
-			// Get the data
-			var p *utils.Params
-			if p, err = utils.ParseParams(msg); err != nil {
-				log.Println("parse:", err)
-				break
-			}
-
-			// Get the function
-			var function string
-			if function, err = p.GetFunction(); err != nil {
-				log.Println("function:", err)
-				break
+			// Parse the request and call the function. Malformed
+			// requests get an error reply, and the connection stays open
+			var reply []byte
+			if r, err := utils.ParseRequest(msg); err != nil {
+				reply = utils.Reply(r, nil, utils.BadRequest(err))
+			} else {
+				var start time.Time = time.Now()
+				var result, err = call(r, cs, c)
+				reply = utils.Reply(r, result, err)
+				if config.Observe != nil {
+					config.Observe(functionName(r.Function), time.Since(start), err)
+				}
 			}
 
-			// Check if the function exists
-			if fn, ok := Functions[function]; !ok {
-				if c.WriteMessage(websocket.TextMessage, []byte("Function not found")) != nil {
-					log.Println("write:", err)
-					break
-				}
-			} else if c.WriteMessage(websocket.TextMessage, fn(p, cache)) != nil {
-				log.Println("function:", err)
+			// Write the reply
+			if err = c.Write(reply); err != nil {
+				log.Println("write:", err)
 				break
 			}
+			c.replied()
 		}
 	}))
+
+	// Return the socket
+	return socket
+}
+
+// Get the name of a function for the observer, so that
+// the names sent by the clients can't be used as labels
+func functionName(name string) string {
+	if _, ok := Scopes[name]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Call a function with the collections, or with the cache of the
+// collection named in the params
+func call(r *utils.Request, cs *hermes.Collections, c *Conn) (any, error) {
+	// Check if the client is allowed to call the function
+	if c.identity != nil {
+		if scope, ok := Scopes[r.Function]; !ok {
+			return nil, utils.FunctionNotFound(r.Function)
+		} else if !c.identity.Scope.Allows(scope) {
+			return nil, utils.Forbidden(fmt.Sprintf("the %s scope is required", scope))
+		}
+	}
+	if c.config.ReadOnly && Scopes[r.Function].Writes() {
+		return nil, utils.Forbidden(auth.ErrReadOnly.Error())
+	}
+
+	// Check if the function manages the collections
+	if fn, ok := CollectionFunctions[r.Function]; ok {
+		return fn(r.Params, cs)
+	}
+
+	// Check if the function exists
+	var fn, ok = Functions[r.Function]
+	var stream, isStream = StreamFunctions[r.Function]
+	if !ok && !isStream {
+		return nil, utils.FunctionNotFound(r.Function)
+	}
+
+	// Get the collection
+	if name, err := utils.GetCollectionParam(r.Params); err != nil {
+		return nil, utils.BadRequest(err)
+	} else if cache, err := cs.Get(name); err != nil {
+		return nil, utils.NotFound(err)
+	} else if isStream {
+		return stream(r, cache, c)
+	} else {
+		return fn(r.Params, cache)
+	}
 }
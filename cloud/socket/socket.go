@@ -1,32 +1,85 @@
 package ws
 
 import (
-	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/realTristan/hermes/cloud/auth"
 )
 
-// Socket struct for storing the socket state and fiber app
+// Config struct for the socket server settings
+type Config struct {
+	// The maximum number of concurrent connections. If zero or less, the connections are unlimited
+	MaxConnections int
+	// The interval between the pings sent to each client
+	PingInterval time.Duration
+	// How long a connection can go without a message or a pong before it's closed
+	IdleTimeout time.Duration
+	// How long a write to a client can take before the connection is closed
+	WriteTimeout time.Duration
+	// The authenticator of the connections. If nil, authentication is disabled
+	Auth auth.Authenticator
+	// Whether the functions that require the write or admin scope are rejected, like on a replication follower
+	ReadOnly bool
+	// A function called after each function call, with the name of the function,
+	// how long the call took, and its error. The names of the functions that
+	// don't exist are replaced with "unknown". If nil, the calls aren't observed
+	Observe func(function string, duration time.Duration, err error)
+}
+
+// DefaultConfig returns the default socket server settings
+func DefaultConfig() Config {
+	return Config{
+		MaxConnections: 1024,
+		PingInterval:   30 * time.Second,
+		IdleTimeout:    60 * time.Second,
+		WriteTimeout:   10 * time.Second,
+	}
+}
+
+// Socket struct for storing the socket server settings and state
 type Socket struct {
-	mutex  *sync.Mutex
-	active bool
+	config      Config
+	connections int64
+}
+
+// Create a new socket with the provided settings. The settings
+// that aren't set are replaced by their default value
+func NewSocket(config Config) *Socket {
+	var d Config = DefaultConfig()
+	if config.PingInterval <= 0 {
+		config.PingInterval = d.PingInterval
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = d.IdleTimeout
+	}
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = d.WriteTimeout
+	}
+	return &Socket{config: config}
+}
+
+// Get the number of open connections
+func (s *Socket) Connections() int {
+	return int(atomic.LoadInt64(&s.connections))
 }
 
-// Set the socket to active
-func (s *Socket) SetActive() {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-	s.active = true
+// Check whether the socket can accept a new connection
+func (s *Socket) IsFull() bool {
+	return s.config.MaxConnections > 0 && s.Connections() >= s.config.MaxConnections
 }
 
-// Set the socket to inactive
-func (s *Socket) SetInactive() {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-	s.active = false
+// Reserve a connection slot. Returns false if the maximum
+// number of connections has been reached
+func (s *Socket) acquire() bool {
+	if n := atomic.AddInt64(&s.connections, 1); s.config.MaxConnections > 0 && n > int64(s.config.MaxConnections) {
+		atomic.AddInt64(&s.connections, -1)
+		return false
+	}
+	return true
 }
 
-// Get the socket state
-func (s *Socket) IsActive() bool {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-	return s.active
+// Release a connection slot
+func (s *Socket) release() {
+	atomic.AddInt64(&s.connections, -1)
 }
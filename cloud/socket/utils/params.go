@@ -3,6 +3,7 @@ package utils
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 )
 
 // Params is a struct that represents the query parameters.
@@ -12,6 +13,69 @@ type Params struct {
 	values map[string]any
 }
 
+// Version is the current version of the socket protocol.
+const Version int = 1
+
+// Request is a struct that represents a message sent by a client.
+// Versioned messages are {"v": 1, "id": ..., "function": "...", "params": {...}}.
+// Unversioned messages are {"function": "...", ...}, with the params next to the function.
+// Fields:
+//   - Version (int): The protocol version. Zero for unversioned messages.
+//   - ID (json.RawMessage): The id of the request, echoed in the response. Can be any JSON value.
+//   - Function (string): The name of the function to call.
+//   - Params (*Params): The params of the function.
+type Request struct {
+	Version  int
+	ID       json.RawMessage
+	Function string
+	Params   *Params
+}
+
+// ParseRequest is a function that parses a JSON-encoded message into a Request struct.
+// Parameters:
+//   - msg ([]byte): A JSON-encoded byte slice containing the message to parse.
+//
+// Returns:
+//   - (*Request, error): A pointer to a Request struct and an error if the parsing fails.
+//     If the message is a valid versioned message with an invalid function, the request is returned with the error, so that the reply can include its id.
+func ParseRequest(msg []byte) (*Request, error) {
+	var envelope struct {
+		Version  int             `json:"v"`
+		ID       json.RawMessage `json:"id"`
+		Function any             `json:"function"`
+		Params   map[string]any  `json:"params"`
+	}
+	if err := json.Unmarshal(msg, &envelope); err != nil {
+		return nil, err
+	}
+
+	// Get the params
+	var r *Request = &Request{Version: envelope.Version, ID: envelope.ID}
+	switch {
+	case envelope.Version == 0:
+		if p, err := ParseParams(msg); err != nil {
+			return nil, err
+		} else {
+			r.Params = p
+		}
+	case envelope.Version > Version:
+		return r, fmt.Errorf("unsupported version %d", envelope.Version)
+	default:
+		if envelope.Params == nil {
+			envelope.Params = map[string]any{}
+		}
+		r.Params = &Params{values: envelope.Params}
+	}
+
+	// Get the function
+	if f, ok := envelope.Function.(string); !ok || len(f) == 0 {
+		return r, errors.New("no function provided")
+	} else {
+		r.Function = f
+	}
+	return r, nil
+}
+
 // ParseParams is a function that parses a JSON-encoded byte slice into a Params struct.
 // Parameters:
 //   - msg ([]byte): A JSON-encoded byte slice containing the parameters to parse.
@@ -61,6 +125,102 @@ func GetKeyParam(p *Params) (string, error) {
 	}
 }
 
+// GetIDParam is a function that retrieves the value of the "id" query parameter from a Params struct.
+// Parameters:
+//   - p (*Params): A pointer to a Params struct.
+//
+// Returns:
+//   - (string, error): The value of the "id" query parameter and an error if the parameter is not provided or is not a string, or nil if successful.
+func GetIDParam(p *Params) (string, error) {
+	if id, ok := p.Get("id").(string); !ok || len(id) == 0 {
+		return "", errors.New("invalid id")
+	} else {
+		return id, nil
+	}
+}
+
+// GetNameParam is a function that retrieves the value of the "name" query parameter from a Params struct.
+// Parameters:
+//   - p (*Params): A pointer to a Params struct.
+//
+// Returns:
+//   - (string, error): The value of the "name" query parameter and an error if the parameter is not provided or is not a string, or nil if successful.
+func GetNameParam(p *Params) (string, error) {
+	if n, ok := p.Get("name").(string); !ok || len(n) == 0 {
+		return "", errors.New("no name provided")
+	} else {
+		return n, nil
+	}
+}
+
+// GetCollectionParam is a function that retrieves the value of the optional "collection" query parameter from a Params struct.
+// Parameters:
+//   - p (*Params): A pointer to a Params struct.
+//
+// Returns:
+//   - (string, error): The value of the "collection" query parameter (empty if it's not provided) and an error if the parameter is not a string, or nil if successful.
+func GetCollectionParam(p *Params) (string, error) {
+	switch c := p.Get("collection").(type) {
+	case nil:
+		return "", nil
+	case string:
+		return c, nil
+	}
+	return "", errors.New("invalid collection")
+}
+
+// GetPrefixParam is a function that retrieves the value of the optional "prefix" query parameter from a Params struct.
+// Parameters:
+//   - p (*Params): A pointer to a Params struct.
+//
+// Returns:
+//   - (string, error): The value of the "prefix" query parameter (empty if it's not provided) and an error if the parameter is not a string, or nil if successful.
+func GetPrefixParam(p *Params) (string, error) {
+	switch prefix := p.Get("prefix").(type) {
+	case nil:
+		return "", nil
+	case string:
+		return prefix, nil
+	}
+	return "", errors.New("invalid prefix")
+}
+
+// GetFromParam is a function that retrieves the value of the optional "from" query parameter from a Params struct and stores it in a provided sequence number pointer.
+// Parameters:
+//   - p (*Params): A pointer to a Params struct.
+//   - from (*uint64): A pointer to a sequence number to store the value of the "from" query parameter. Left as-is if the parameter is not provided.
+//
+// Returns:
+//   - error: An error if the "from" query parameter is not a positive float64, or nil if successful.
+func GetFromParam(p *Params, from *uint64) error {
+	switch i := p.Get("from").(type) {
+	case nil:
+		return nil
+	case float64:
+		if i >= 0 {
+			*from = uint64(i)
+			return nil
+		}
+	}
+	return errors.New("invalid from")
+}
+
+// GetSubscriptionParam is a function that retrieves the value of the "subscription" query parameter from a Params struct and stores it in a provided integer pointer.
+// Parameters:
+//   - p (*Params): A pointer to a Params struct.
+//   - sub (*int): A pointer to an integer to store the value of the "subscription" query parameter.
+//
+// Returns:
+//   - error: An error if the "subscription" query parameter is not provided or is not a float64, or nil if successful.
+func GetSubscriptionParam(p *Params, sub *int) error {
+	if i, ok := p.Get("subscription").(float64); !ok {
+		return errors.New("invalid subscription")
+	} else {
+		*sub = int(i)
+	}
+	return nil
+}
+
 // GetQueryParam is a function that retrieves the value of the "query" query parameter from a Params struct.
 // Parameters:
 //   - p (*Params): A pointer to a Params struct.
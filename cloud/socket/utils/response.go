@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The codes of the response errors.
+const (
+	// The message or its params are invalid
+	CodeBadRequest string = "bad_request"
+	// The function or collection does not exist
+	CodeNotFound string = "not_found"
+	// The client is not allowed to call the function
+	CodeForbidden string = "forbidden"
+	// The function failed
+	CodeFailed string = "failed"
+	// The result could not be encoded
+	CodeInternal string = "internal"
+	// The client did not read the pushed messages fast enough, and the subscription was dropped
+	CodeOverflow string = "overflow"
+)
+
+// Error is a struct that represents the error of a response.
+// Fields:
+//   - Code (string): The error code, used by the clients to handle the error.
+//   - Message (string): The error message.
+//   - function (bool): Whether the error is a not_found error for a function, which unversioned requests get as plain text.
+type Error struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	function bool
+}
+
+// Error is a method of the Error struct that returns the error message.
+// Returns:
+//   - string: The error message.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// BadRequest is a function that returns a bad_request error with the provided message.
+// Parameters:
+//   - err (T): The error message.
+//
+// Returns:
+//   - *Error: A pointer to the error.
+func BadRequest[T any](err T) *Error {
+	return &Error{Code: CodeBadRequest, Message: fmt.Sprint(err)}
+}
+
+// NotFound is a function that returns a not_found error with the provided message.
+// Parameters:
+//   - err (T): The error message.
+//
+// Returns:
+//   - *Error: A pointer to the error.
+func NotFound[T any](err T) *Error {
+	return &Error{Code: CodeNotFound, Message: fmt.Sprint(err)}
+}
+
+// FunctionNotFound is a function that returns a not_found error for a function that doesn't exist.
+// Parameters:
+//   - name (string): The name of the function.
+//
+// Returns:
+//   - *Error: A pointer to the error.
+func FunctionNotFound(name string) *Error {
+	return &Error{Code: CodeNotFound, Message: fmt.Sprintf("function %s not found", name), function: true}
+}
+
+// Forbidden is a function that returns a forbidden error with the provided message.
+// Parameters:
+//   - err (T): The error message.
+//
+// Returns:
+//   - *Error: A pointer to the error.
+func Forbidden[T any](err T) *Error {
+	return &Error{Code: CodeForbidden, Message: fmt.Sprint(err)}
+}
+
+// Response is a struct that represents a versioned response to a request.
+// Fields:
+//   - Version (int): The protocol version.
+//   - ID (json.RawMessage): The id of the request, so that the client can match the response with it.
+//   - Ok (bool): Whether the function succeeded.
+//   - Result (any): The result of the function. Omitted if the function failed.
+//   - Error (*Error): The error of the function. Omitted if the function succeeded.
+type Response struct {
+	Version int             `json:"v"`
+	ID      json.RawMessage `json:"id"`
+	Ok      bool            `json:"ok"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// legacySuccess is a struct that represents the successful response to an unversioned request.
+type legacySuccess struct {
+	Success bool `json:"success"`
+	Data    any  `json:"data"`
+}
+
+// legacyError is a struct that represents the error response to an unversioned request.
+type legacyError struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+}
+
+// The functions whose results are sent to unversioned requests as-is,
+// without the {"success": ..., "data": ...} wrapper
+var rawResults = map[string]bool{
+	"cache.get":         true,
+	"cache.keys":        true,
+	"ft.storage":        true,
+	"ft.search":         true,
+	"ft.search.oneword": true,
+	"ft.search.values":  true,
+	"ft.search.withkey": true,
+}
+
+// The reply to an unversioned request of a function that doesn't exist
+var legacyFunctionNotFound = []byte("Function not found")
+
+// Reply is a function that encodes the response to a request.
+// Versioned requests get a Response. Unversioned requests get the replies of the protocol before it was versioned:
+// {"success": true, "data": ...} or {"success": false, "error": "..."}, the raw result of the get, keys, storage and
+// search functions, and "Function not found" for the functions that don't exist.
+// Parameters:
+//   - r (*Request): A pointer to the request. If nil, the request could not be parsed, and a versioned response is returned.
+//   - result (any): The result of the function.
+//   - err (error): The error of the function. Errors that aren't an *Error get the "failed" code.
+//
+// Returns:
+//   - []byte: The JSON-encoded response.
+func Reply(r *Request, result any, err error) []byte {
+	var e *Error
+	if err != nil {
+		if e, _ = err.(*Error); e == nil {
+			e = &Error{Code: CodeFailed, Message: err.Error()}
+		}
+		result = nil
+	}
+
+	// Encode the response
+	var v any
+	switch {
+	case r != nil && r.Version == 0 && e != nil && e.function:
+		return legacyFunctionNotFound
+	case r != nil && r.Version == 0 && e != nil:
+		v = legacyError{Error: e.Message}
+	case r != nil && r.Version == 0 && rawResults[r.Function]:
+		v = result
+	case r != nil && r.Version == 0:
+		v = legacySuccess{Success: true, Data: result}
+	default:
+		var res *Response = &Response{Version: Version, Ok: e == nil, Result: result, Error: e}
+		if r != nil {
+			res.ID = r.ID
+		}
+		v = res
+	}
+	if b, err := json.Marshal(v); err == nil {
+		return b
+	}
+
+	// The result could not be encoded
+	return Reply(r, nil, &Error{Code: CodeInternal, Message: "result could not be encoded"})
+}
@@ -0,0 +1,54 @@
+package utils
+
+import "encoding/json"
+
+// Stream is the interface of a client connection that functions can push messages to,
+// after they've returned their reply.
+type Stream interface {
+	// Write a message to the client
+	Write(msg []byte) error
+	// Get a channel that's closed when the connection is closed
+	Done() <-chan struct{}
+	// Run a function once the reply of the current request has been written
+	AfterReply(fn func())
+	// Register a subscription with the function that cancels it, and get its id
+	Subscribe(cancel func()) int
+	// Cancel a subscription. Returns false if the subscription does not exist
+	Unsubscribe(id int) bool
+}
+
+// Push is a struct that represents a message pushed to a client by a subscription.
+// Fields:
+//   - Version (int): The protocol version.
+//   - ID (json.RawMessage): The id of the request that created the subscription.
+//   - Subscription (int): The id of the subscription.
+//   - Event (any): The pushed event. Omitted if the subscription ended with an error.
+//   - Error (*Error): The error that ended the subscription. Omitted otherwise.
+type Push struct {
+	Version      int             `json:"v"`
+	ID           json.RawMessage `json:"id"`
+	Subscription int             `json:"subscription"`
+	Event        any             `json:"event,omitempty"`
+	Error        *Error          `json:"error,omitempty"`
+}
+
+// Pushed is a function that encodes a message pushed by a subscription.
+// Pushed messages use the versioned format for all requests, since unversioned responses have no way to identify the subscription.
+// Parameters:
+//   - r (*Request): A pointer to the request that created the subscription.
+//   - sub (int): The id of the subscription.
+//   - event (any): The event to push.
+//   - err (*Error): The error that ended the subscription, or nil.
+//
+// Returns:
+//   - []byte: The JSON-encoded message.
+func Pushed(r *Request, sub int, event any, err *Error) []byte {
+	var p *Push = &Push{Version: Version, ID: r.ID, Subscription: sub, Event: event, Error: err}
+	if err != nil {
+		p.Event = nil
+	}
+	if b, e := json.Marshal(p); e == nil {
+		return b
+	}
+	return Pushed(r, sub, nil, &Error{Code: CodeInternal, Message: "event could not be encoded"})
+}
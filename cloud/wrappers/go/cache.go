@@ -0,0 +1,203 @@
+package hermescloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// Set is a method of the Client struct that sets a record in the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - key (string): The key of the record. It must not exist.
+//   - value (map[string]any): The record.
+//
+// Returns:
+//   - error: The error of the request.
+func (c *Client) Set(ctx context.Context, key string, value map[string]any) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/cache/set", function: "cache.set", params: map[string]any{"key": key}, body: value, bodyParam: "value", cache: true, once: true}, nil)
+}
+
+// Get is a method of the Client struct that gets a record of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - map[string]any: The record.
+//   - error: An *Error that matches ErrNotFound if the key does not exist, or the error of the request.
+func (c *Client) Get(ctx context.Context, key string) (map[string]any, error) {
+	var value map[string]any
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/cache/get", function: "cache.get", params: map[string]any{"key": key}, raw: true, cache: true}, &value)
+
+	// The websocket replies with null for a key that does not exist
+	if err == nil && value == nil {
+		err = &Error{Code: CodeNotFound, Message: fmt.Sprintf("key %s does not exist", key)}
+	}
+	return value, err
+}
+
+// GetAll is a method of the Client struct that gets the records of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - map[string]map[string]any: The records, mapped by key.
+//   - error: The error of the request.
+func (c *Client) GetAll(ctx context.Context) (map[string]map[string]any, error) {
+	var data map[string]map[string]any
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/cache/get/all", function: "cache.get.all", raw: true, cache: true}, &data)
+	return data, err
+}
+
+// Delete is a method of the Client struct that deletes a record of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - error: The error of the request.
+func (c *Client) Delete(ctx context.Context, key string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/cache/delete", function: "cache.delete", params: map[string]any{"key": key}, cache: true}, nil)
+}
+
+// Exists is a method of the Client struct that checks whether a key exists in the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - key (string): The key.
+//
+// Returns:
+//   - bool: Whether the key exists.
+//   - error: The error of the request.
+func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
+	var exists bool
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/cache/exists", function: "cache.exists", params: map[string]any{"key": key}, cache: true}, &exists)
+	return exists, err
+}
+
+// Keys is a method of the Client struct that gets the keys of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - []string: The keys.
+//   - error: The error of the request.
+func (c *Client) Keys(ctx context.Context) ([]string, error) {
+	var keys []string
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/cache/keys", function: "cache.keys", raw: true, cache: true}, &keys)
+	return keys, err
+}
+
+// Values is a method of the Client struct that gets the records of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - []map[string]any: The records.
+//   - error: The error of the request.
+func (c *Client) Values(ctx context.Context) ([]map[string]any, error) {
+	var values []map[string]any
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/cache/values", function: "cache.values", raw: true, cache: true}, &values)
+	return values, err
+}
+
+// Length is a method of the Client struct that gets the number of records in the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - int: The number of records.
+//   - error: The error of the request.
+func (c *Client) Length(ctx context.Context) (int, error) {
+	var length int
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/cache/length", function: "cache.length", cache: true}, &length)
+	return length, err
+}
+
+// Clean is a method of the Client struct that deletes every record of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - error: The error of the request.
+func (c *Client) Clean(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/cache/clean", function: "cache.clean", cache: true}, nil)
+}
+
+// Info is a method of the Client struct that gets the number of records, and the size of the full-text index.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - map[string]any: The info of the collection.
+//   - error: The error of the request.
+func (c *Client) Info(ctx context.Context) (map[string]any, error) {
+	var info map[string]any
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/cache/info", function: "cache.info", cache: true}, &info)
+	return info, err
+}
+
+// InfoForTesting is a method of the Client struct that gets the info of the collection, with its contents.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - map[string]any: The info of the collection.
+//   - error: The error of the request.
+func (c *Client) InfoForTesting(ctx context.Context) (map[string]any, error) {
+	var info map[string]any
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/cache/info/testing", function: "cache.info.testing", cache: true}, &info)
+	return info, err
+}
+
+// ExportJSON is a method of the Client struct that streams the records of the collection to a writer.
+// The export is only supported over the REST API.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - w (io.Writer): The writer.
+//   - format (hermes.ExportFormat): The format of the records: hermes.ExportHash or hermes.ExportNDJSON.
+//
+// Returns:
+//   - error: The error of the request.
+func (c *Client) ExportJSON(ctx context.Context, w io.Writer, format hermes.ExportFormat) error {
+	var t, ok = c.transport.(*httpTransport)
+	if !ok {
+		return errors.New("hermes: the export is only supported over the REST API")
+	}
+	var resp, err = t.send(ctx, request{method: http.MethodGet, path: "/cache/export", params: map[string]any{"format": string(format)}, collection: c.collection})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// GetSchema is a method of the Client struct that gets the schema of the records of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - *hermes.Schema: The schema, or nil if no schema is set.
+//   - error: The error of the request.
+func (c *Client) GetSchema(ctx context.Context) (*hermes.Schema, error) {
+	var schema *hermes.Schema
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/cache/schema", function: "cache.schema.get", cache: true}, &schema)
+	return schema, err
+}
+
+// SetSchema is a method of the Client struct that sets the schema of the records of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - schema (*hermes.Schema): The schema. Nil removes the schema.
+//
+// Returns:
+//   - error: The error of the request.
+func (c *Client) SetSchema(ctx context.Context, schema *hermes.Schema) error {
+	// A nil schema is encoded as null, which removes the schema
+	return c.do(ctx, request{method: http.MethodPost, path: "/cache/schema", function: "cache.schema.set", body: schema, bodyParam: "value", cache: true}, nil)
+}
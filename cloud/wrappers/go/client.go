@@ -0,0 +1,281 @@
+package hermescloud
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client is a struct that represents a client of a hermes cloud server, over the REST API or the websocket.
+// A client is safe for concurrent use.
+// Fields:
+//   - transport (transport): The transport that sends the requests. It's shared by the clients of the collections.
+//   - collection (string): The collection that the cache requests use. Empty for the default collection.
+//   - retry (Retry): How the failed requests are retried.
+//   - timeout (time.Duration): The timeout of the requests whose context has no deadline. 0 for no timeout.
+//   - token (string): The api key or token of the client. Empty if authentication is disabled.
+//   - http (*http.Client): The http client of the REST API. Nil for the default client.
+//   - poolSize (int): The number of pooled connections.
+//   - tls (*tls.Config): The tls config of the connections. Nil for the default config.
+type Client struct {
+	transport  transport
+	collection string
+	retry      Retry
+	timeout    time.Duration
+	token      string
+	http       *http.Client
+	poolSize   int
+	tls        *tls.Config
+}
+
+// Retry is a struct that represents how the failed requests are retried.
+// The delay before a retry doubles after each attempt, up to MaxDelay, and is jittered.
+// The requests are retried when the server is unavailable or the connection failed,
+// but the requests that can't be applied twice, like Set, are only retried if they were not sent.
+// Fields:
+//   - Max (int): The maximum number of retries. 0 disables the retries.
+//   - Delay (time.Duration): The delay before the first retry.
+//   - MaxDelay (time.Duration): The maximum delay between two attempts.
+type Retry struct {
+	Max      int
+	Delay    time.Duration
+	MaxDelay time.Duration
+}
+
+// DefaultRetry is the retry policy of the clients that don't set one.
+var DefaultRetry Retry = Retry{Max: 3, Delay: 100 * time.Millisecond, MaxDelay: 2 * time.Second}
+
+// Option is a function that sets an option of a client.
+type Option func(c *Client)
+
+// WithHTTPClient is a function that returns an option that sets the http client of the REST API.
+// The pool size and tls options are ignored when it's set.
+// Parameters:
+//   - h (*http.Client): The http client.
+//
+// Returns:
+//   - Option: The option.
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) {
+		c.http = h
+	}
+}
+
+// WithToken is a function that returns an option that sets the api key or token that the requests are authenticated with.
+// Parameters:
+//   - token (string): The api key, hmac token or jwt.
+//
+// Returns:
+//   - Option: The option.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithPoolSize is a function that returns an option that sets the number of pooled connections.
+// Over the REST API, it's the number of idle connections that are kept open. Over the websocket,
+// it's the number of connections that the requests are spread over. Defaults to 16 and 4.
+// Parameters:
+//   - size (int): The number of connections.
+//
+// Returns:
+//   - Option: The option.
+func WithPoolSize(size int) Option {
+	return func(c *Client) {
+		c.poolSize = size
+	}
+}
+
+// WithRetry is a function that returns an option that sets how the failed requests are retried.
+// Parameters:
+//   - r (Retry): The retry policy. Retry{} disables the retries.
+//
+// Returns:
+//   - Option: The option.
+func WithRetry(r Retry) Option {
+	return func(c *Client) {
+		c.retry = r
+	}
+}
+
+// WithTimeout is a function that returns an option that sets the timeout of the requests whose context has no deadline.
+// The timeout includes the retries. Defaults to 30 seconds.
+// Parameters:
+//   - d (time.Duration): The timeout. 0 for no timeout.
+//
+// Returns:
+//   - Option: The option.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithTLSConfig is a function that returns an option that sets the tls config of the connections, for example to send a client certificate.
+// Parameters:
+//   - config (*tls.Config): The tls config.
+//
+// Returns:
+//   - Option: The option.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		c.tls = config
+	}
+}
+
+// New is a function that creates a client of a hermes cloud server. The transport is chosen by the scheme of the url:
+// http and https use the REST API, and ws and wss use the websocket. The websocket connections are opened by the first requests.
+// Parameters:
+//   - baseURL (string): The url of the server, for example http://localhost:3000 or ws://localhost:3000.
+//   - opts (...Option): The options of the client.
+//
+// Returns:
+//   - *Client: A pointer to the client.
+func New(baseURL string, opts ...Option) *Client {
+	var c *Client = &Client{
+		retry:   DefaultRetry,
+		timeout: 30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// Create the transport
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if strings.HasPrefix(baseURL, "ws://") || strings.HasPrefix(baseURL, "wss://") {
+		c.transport = newWebSocket(baseURL, c)
+	} else {
+		c.transport = newHTTP(baseURL, c)
+	}
+	return c
+}
+
+// Collection is a method of the Client struct that returns a client of a collection.
+// The client shares the connections of the original client.
+// Parameters:
+//   - name (string): The name of the collection.
+//
+// Returns:
+//   - *Client: A pointer to the client of the collection.
+func (c *Client) Collection(name string) *Client {
+	var copy Client = *c
+	copy.collection = name
+	return &copy
+}
+
+// Close is a method of the Client struct that closes the connections of the client, and of the clients of its collections.
+// Returns:
+//   - error: The error of closing the connections.
+func (c *Client) Close() error {
+	return c.transport.close()
+}
+
+// request is a struct that represents a request, as a route of the REST API and a function of the websocket.
+// Fields:
+//   - method (string): The http method.
+//   - path (string): The path of the route.
+//   - function (string): The websocket function. Empty if the request is only supported by the REST API.
+//   - params (map[string]any): The params, which are the query parameters of the route. Can be nil.
+//   - body (any): The value that is encoded as the JSON body. Nil if the route takes no body.
+//   - bodyParam (string): The websocket param that the body is sent as, "value" or "json".
+//   - raw (bool): Whether the result of the route is the response body, instead of the "data" of a {"success": true, "data": ...} response.
+//   - cache (bool): Whether the request is sent to a collection.
+//   - once (bool): Whether the request can't be applied twice, so it's only retried if it was not sent.
+//   - collection (string): The collection of the request, set by the client.
+type request struct {
+	method     string
+	path       string
+	function   string
+	params     map[string]any
+	body       any
+	bodyParam  string
+	raw        bool
+	cache      bool
+	once       bool
+	collection string
+}
+
+// transport is an interface that sends the requests of a client.
+type transport interface {
+	// do sends a request and decodes its result into a pointer, which can be nil.
+	do(ctx context.Context, r request, result any) error
+
+	// close closes the connections of the transport.
+	close() error
+}
+
+// do is a method of the Client struct that sends a request and decodes its result, retrying it if it failed.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - r (request): The request.
+//   - result (any): A pointer to the value that the result is decoded into. Can be nil.
+//
+// Returns:
+//   - error: An *Error if the server responded with an error, or the error of the request.
+func (c *Client) do(ctx context.Context, r request, result any) error {
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	if r.cache {
+		r.collection = c.collection
+	}
+
+	// Send the request, until it succeeds or can't be retried
+	for attempt := 0; ; attempt++ {
+		var err error = c.transport.do(ctx, r, result)
+		if err == nil || attempt >= c.retry.Max || !retryable(r, err) {
+			return err
+		}
+
+		// Wait before the next attempt
+		var timer *time.Timer = time.NewTimer(c.retry.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable is a function that checks whether a failed request can be retried.
+// Parameters:
+//   - r (request): The request.
+//   - err (error): The error of the request.
+//
+// Returns:
+//   - bool: Whether the request can be retried.
+func retryable(r request, err error) bool {
+	var e *Error
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	} else if errors.As(err, &e) {
+		// The request was received, so it's retried if the server was unavailable
+		return e.Is(ErrUnavailable) && (!r.once || errors.As(err, new(*sendError)))
+	}
+	return !r.once || errors.As(err, new(*sendError))
+}
+
+// backoff is a method of the Retry struct that returns the delay before a retry.
+// Parameters:
+//   - attempt (int): The number of the failed attempt, from 0.
+//
+// Returns:
+//   - time.Duration: The delay, between half and all of the exponential delay.
+func (r Retry) backoff(attempt int) time.Duration {
+	var d time.Duration = r.Delay << attempt
+	if d <= 0 || (r.MaxDelay > 0 && d > r.MaxDelay) {
+		d = r.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
@@ -0,0 +1,41 @@
+package hermescloud
+
+import (
+	"context"
+	"net/http"
+)
+
+// Collections is a method of the Client struct that lists the names of the collections.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - []string: The names of the collections.
+//   - error: The error of the request.
+func (c *Client) Collections(ctx context.Context) ([]string, error) {
+	var names []string
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/collections", function: "collections.list"}, &names)
+	return names, err
+}
+
+// CreateCollection is a method of the Client struct that creates a collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - name (string): The name of the collection.
+//
+// Returns:
+//   - error: An *Error that matches ErrConflict if the collection already exists, or the error of the request.
+func (c *Client) CreateCollection(ctx context.Context, name string) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/collections/create", function: "collections.create", params: map[string]any{"name": name}, once: true}, nil)
+}
+
+// DropCollection is a method of the Client struct that drops a collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - name (string): The name of the collection.
+//
+// Returns:
+//   - error: An *Error that matches ErrNotFound if the collection does not exist, or the error of the request.
+func (c *Client) DropCollection(ctx context.Context, name string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/collections/drop", function: "collections.drop", params: map[string]any{"name": name}, once: true}, nil)
+}
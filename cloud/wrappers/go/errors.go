@@ -0,0 +1,164 @@
+package hermescloud
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// The kinds of errors. An *Error matches one of them with errors.Is, whichever transport it was returned by.
+var (
+	ErrBadRequest   = errors.New("hermes: bad request")
+	ErrUnauthorized = errors.New("hermes: unauthorized")
+	ErrForbidden    = errors.New("hermes: forbidden")
+	ErrNotFound     = errors.New("hermes: not found")
+	ErrConflict     = errors.New("hermes: conflict")
+	ErrFailed       = errors.New("hermes: failed")
+	ErrUnavailable  = errors.New("hermes: unavailable")
+)
+
+// The error codes, as sent by the websocket. The errors of the REST API get the code of their status.
+const (
+	CodeBadRequest   = "bad_request"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeFailed       = "failed"
+	CodeInternal     = "internal"
+	CodeUnavailable  = "unavailable"
+)
+
+// Error is a struct that represents an error response of the server, or an error of the embedded cache.
+// Fields:
+//   - Status (int): The status code of the response, for example 404 for a key that does not exist. 0 for the errors of the websocket.
+//   - Code (string): The error code, for example "not_found".
+//   - Message (string): The error message.
+//   - err (error): The error of the embedded cache. Nil for the errors of the server.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	err     error
+}
+
+// Error is a method of the Error struct that returns the error message.
+// Returns:
+//   - string: The status code or error code, and the error message.
+func (e *Error) Error() string {
+	if e.Status > 0 {
+		return fmt.Sprintf("hermes: %d %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("hermes: %s %s", e.Code, e.Message)
+}
+
+// Is is a method of the Error struct that reports whether the error is of a kind, for example errors.Is(err, ErrNotFound).
+// Parameters:
+//   - target (error): The kind of error.
+//
+// Returns:
+//   - bool: Whether the error is of the kind.
+func (e *Error) Is(target error) bool {
+	switch e.Code {
+	case CodeBadRequest:
+		return target == ErrBadRequest
+	case CodeUnauthorized:
+		return target == ErrUnauthorized
+	case CodeForbidden:
+		return target == ErrForbidden
+	case CodeNotFound:
+		return target == ErrNotFound
+	case CodeConflict:
+		return target == ErrConflict
+	case CodeFailed, CodeInternal:
+		return target == ErrFailed
+	case CodeUnavailable:
+		return target == ErrUnavailable
+	}
+	return false
+}
+
+// Unwrap is a method of the Error struct that returns the error of the embedded cache.
+// Returns:
+//   - error: The error, or nil for the errors of the server.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// statusCode is a function that returns the error code of a status code.
+// Parameters:
+//   - status (int): The status code of the response.
+//
+// Returns:
+//   - string: The error code.
+func statusCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusUnprocessableEntity:
+		return CodeFailed
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return CodeUnavailable
+	}
+	return CodeInternal
+}
+
+// responseError is a function that reads the error of a response.
+// Parameters:
+//   - resp (*http.Response): The response, with an error status code.
+//
+// Returns:
+//   - *Error: The error. The status text is the message if the body is not a JSON error.
+func responseError(resp *http.Response) *Error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	var data, _ = io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err := json.Unmarshal(data, &body); err != nil || len(body.Error) == 0 {
+		body.Error = http.StatusText(resp.StatusCode)
+	}
+	return &Error{Status: resp.StatusCode, Code: statusCode(resp.StatusCode), Message: body.Error}
+}
+
+// sendError is a struct that represents an error that happened before the request was sent, so it can always be retried.
+// Fields:
+//   - err (error): The error, for example the error of the dial.
+type sendError struct {
+	err error
+}
+
+// Error is a method of the sendError struct that returns the error message.
+// Returns:
+//   - string: The error message.
+func (e *sendError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap is a method of the sendError struct that returns the error.
+// Returns:
+//   - error: The error.
+func (e *sendError) Unwrap() error {
+	return e.err
+}
+
+// isDialError is a function that checks whether an error of the http client happened when dialing the server.
+// Parameters:
+//   - err (error): The error.
+//
+// Returns:
+//   - bool: Whether the connection could not be opened.
+func isDialError(err error) bool {
+	var op *net.OpError
+	return errors.As(err, &op) && op.Op == "dial"
+}
@@ -0,0 +1,279 @@
+package hermescloud
+
+import (
+	"context"
+	"net/http"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// ftParams is a function that returns the params of the full-text settings.
+// Parameters:
+//   - maxSize (int): The maximum number of words in the full-text index. -1 for no limit.
+//   - maxBytes (int): The maximum size of the full-text index, in bytes. -1 for no limit.
+//   - minWordLength (int): The minimum length of the indexed words.
+//
+// Returns:
+//   - map[string]any: The params.
+func ftParams(maxSize, maxBytes, minWordLength int) map[string]any {
+	return map[string]any{
+		"maxsize":       maxSize,
+		"maxbytes":      maxBytes,
+		"minwordlength": minWordLength,
+	}
+}
+
+// FTInit is a method of the Client struct that initializes the full-text index of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - maxSize (int): The maximum number of words in the full-text index. -1 for no limit.
+//   - maxBytes (int): The maximum size of the full-text index, in bytes. -1 for no limit.
+//   - minWordLength (int): The minimum length of the indexed words.
+//
+// Returns:
+//   - error: An *Error that matches ErrFailed if the index is already initialized, or the error of the request.
+func (c *Client) FTInit(ctx context.Context, maxSize, maxBytes, minWordLength int) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/ft/init", function: "ft.init", params: ftParams(maxSize, maxBytes, minWordLength), cache: true, once: true}, nil)
+}
+
+// FTInitWithMap is a method of the Client struct that initializes the full-text index of the collection with records.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - data (map[string]map[string]any): The records, mapped by key.
+//   - maxSize (int): The maximum number of words in the full-text index. -1 for no limit.
+//   - maxBytes (int): The maximum size of the full-text index, in bytes. -1 for no limit.
+//   - minWordLength (int): The minimum length of the indexed words.
+//
+// Returns:
+//   - error: The error of the request.
+func (c *Client) FTInitWithMap(ctx context.Context, data map[string]map[string]any, maxSize, maxBytes, minWordLength int) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/ft/init/json", function: "ft.init.json", params: ftParams(maxSize, maxBytes, minWordLength), body: data, bodyParam: "json", cache: true, once: true}, nil)
+}
+
+// FTClean is a method of the Client struct that clears the full-text index of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - error: The error of the request.
+func (c *Client) FTClean(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/ft/clean", function: "ft.clean", cache: true}, nil)
+}
+
+// search is a method of the Client struct that sends a search request.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - path (string): The path of the search route.
+//   - function (string): The search function of the websocket.
+//   - params (map[string]any): The params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: The error of the request.
+func (c *Client) search(ctx context.Context, path, function string, params map[string]any) ([]map[string]any, error) {
+	var result []map[string]any
+	var err error = c.do(ctx, request{method: http.MethodGet, path: path, function: function, params: params, raw: true, cache: true}, &result)
+	return result, err
+}
+
+// Search is a method of the Client struct that searches the full-text index of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - sp (hermes.SearchParams): The query, limit and strict params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: The error of the request.
+func (c *Client) Search(ctx context.Context, sp hermes.SearchParams) ([]map[string]any, error) {
+	return c.search(ctx, "/ft/search", "ft.search", map[string]any{
+		"query":  sp.Query,
+		"limit":  sp.Limit,
+		"strict": sp.Strict,
+	})
+}
+
+// SearchOneWord is a method of the Client struct that searches the full-text index of the collection for a single word.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - sp (hermes.SearchParams): The query, limit and strict params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: The error of the request.
+func (c *Client) SearchOneWord(ctx context.Context, sp hermes.SearchParams) ([]map[string]any, error) {
+	return c.search(ctx, "/ft/search/oneword", "ft.search.oneword", map[string]any{
+		"query":  sp.Query,
+		"limit":  sp.Limit,
+		"strict": sp.Strict,
+	})
+}
+
+// SearchValues is a method of the Client struct that searches the values of the records of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - sp (hermes.SearchParams): The query, limit and schema params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: The error of the request.
+func (c *Client) SearchValues(ctx context.Context, sp hermes.SearchParams) ([]map[string]any, error) {
+	return c.search(ctx, "/ft/search/values", "ft.search.values", map[string]any{
+		"query":  sp.Query,
+		"limit":  sp.Limit,
+		"schema": sp.Schema,
+	})
+}
+
+// SearchWithKey is a method of the Client struct that searches the values of a field of the records of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - sp (hermes.SearchParams): The query, key and limit params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: The error of the request.
+func (c *Client) SearchWithKey(ctx context.Context, sp hermes.SearchParams) ([]map[string]any, error) {
+	// The websocket function also takes the schema of the search
+	return c.search(ctx, "/ft/search/withkey", "ft.search.withkey", map[string]any{
+		"query":  sp.Query,
+		"key":    sp.Key,
+		"limit":  sp.Limit,
+		"schema": sp.Schema,
+	})
+}
+
+// RegisterQuery is a method of the Client struct that registers a search query, so that documents can be percolated against it.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - id (string): The id of the query.
+//   - sp (hermes.SearchParams): The query and strict params of the search.
+//
+// Returns:
+//   - error: The error of the request.
+func (c *Client) RegisterQuery(ctx context.Context, id string, sp hermes.SearchParams) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/ft/queries/register", function: "ft.queries.register", params: map[string]any{
+		"id":     id,
+		"query":  sp.Query,
+		"strict": sp.Strict,
+	}, cache: true, once: true}, nil)
+}
+
+// UnregisterQuery is a method of the Client struct that removes a registered search query.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - id (string): The id of the query.
+//
+// Returns:
+//   - error: An *Error that matches ErrNotFound if no query is registered with the id, or the error of the request.
+func (c *Client) UnregisterQuery(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/ft/queries/unregister", function: "ft.queries.unregister", params: map[string]any{"id": id}, cache: true, once: true}, nil)
+}
+
+// Percolate is a method of the Client struct that gets the ids of the registered queries that a document matches.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - doc (map[string]any): The document.
+//
+// Returns:
+//   - []string: The ids of the matching queries.
+//   - error: The error of the request.
+func (c *Client) Percolate(ctx context.Context, doc map[string]any) ([]string, error) {
+	var ids []string
+	var err error = c.do(ctx, request{method: http.MethodPost, path: "/ft/percolate", function: "ft.percolate", body: doc, bodyParam: "value", cache: true}, &ids)
+	return ids, err
+}
+
+// FTSetMaxBytes is a method of the Client struct that sets the maximum size of the full-text index.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - maxBytes (int): The maximum size, in bytes. -1 for no limit.
+//
+// Returns:
+//   - error: The error of the request.
+func (c *Client) FTSetMaxBytes(ctx context.Context, maxBytes int) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/ft/maxbytes", function: "ft.maxbytes.set", params: map[string]any{"maxbytes": maxBytes}, cache: true}, nil)
+}
+
+// FTSetMaxSize is a method of the Client struct that sets the maximum number of words in the full-text index.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - maxSize (int): The maximum number of words. -1 for no limit.
+//
+// Returns:
+//   - error: The error of the request.
+func (c *Client) FTSetMaxSize(ctx context.Context, maxSize int) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/ft/maxsize", function: "ft.maxsize.set", params: map[string]any{"maxsize": maxSize}, cache: true}, nil)
+}
+
+// FTSetMinWordLength is a method of the Client struct that sets the minimum length of the indexed words.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - minWordLength (int): The minimum length of the words.
+//
+// Returns:
+//   - error: The error of the request.
+func (c *Client) FTSetMinWordLength(ctx context.Context, minWordLength int) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/ft/minwordlength", function: "ft.minwordlength.set", params: map[string]any{"minwordlength": minWordLength}, cache: true}, nil)
+}
+
+// FTStorage is a method of the Client struct that gets the full-text index of the collection.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - map[string]any: The full-text index.
+//   - error: The error of the request.
+func (c *Client) FTStorage(ctx context.Context) (map[string]any, error) {
+	var storage map[string]any
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/ft/storage", function: "ft.storage", raw: true, cache: true}, &storage)
+	return storage, err
+}
+
+// FTStorageSize is a method of the Client struct that gets the size of the full-text index, in bytes.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - int: The size of the index.
+//   - error: The error of the request.
+func (c *Client) FTStorageSize(ctx context.Context) (int, error) {
+	var size int
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/ft/storage/size", function: "ft.storage.size", cache: true}, &size)
+	return size, err
+}
+
+// FTStorageLength is a method of the Client struct that gets the number of words in the full-text index.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - int: The number of words.
+//   - error: The error of the request.
+func (c *Client) FTStorageLength(ctx context.Context) (int, error) {
+	var length int
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/ft/storage/length", function: "ft.storage.length", cache: true}, &length)
+	return length, err
+}
+
+// FTIsInitialized is a method of the Client struct that checks whether the full-text index of the collection is initialized.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - bool: Whether the index is initialized.
+//   - error: The error of the request.
+func (c *Client) FTIsInitialized(ctx context.Context) (bool, error) {
+	var initialized bool
+	var err error = c.do(ctx, request{method: http.MethodGet, path: "/ft/isinitialized", function: "ft.isinitialized", cache: true}, &initialized)
+	return initialized, err
+}
+
+// FTSequenceIndices is a method of the Client struct that renumbers the record indices of the full-text index.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//
+// Returns:
+//   - error: The error of the request.
+func (c *Client) FTSequenceIndices(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/ft/indices/sequence", function: "ft.indices.sequence", cache: true}, nil)
+}
@@ -0,0 +1,165 @@
+package hermescloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// httpTransport is a struct that sends the requests to the REST API.
+// Fields:
+//   - url (string): The base url of the server, for example http://localhost:3000.
+//   - client (*http.Client): The http client. Its transport pools the connections.
+//   - token (string): The api key or token of the client. Empty if authentication is disabled.
+type httpTransport struct {
+	url    string
+	client *http.Client
+	token  string
+}
+
+// newHTTP is a function that creates the REST transport of a client.
+// Parameters:
+//   - baseURL (string): The url of the server.
+//   - c (*Client): The client, with its options set.
+//
+// Returns:
+//   - *httpTransport: A pointer to the transport.
+func newHTTP(baseURL string, c *Client) *httpTransport {
+	var client *http.Client = c.http
+	if client == nil {
+		var t *http.Transport = http.DefaultTransport.(*http.Transport).Clone()
+		t.MaxIdleConnsPerHost = 16
+		if c.poolSize > 0 {
+			t.MaxIdleConnsPerHost = c.poolSize
+		}
+		if c.tls != nil {
+			t.TLSClientConfig = c.tls
+		}
+		client = &http.Client{Transport: t}
+	}
+	return &httpTransport{url: baseURL, client: client, token: c.token}
+}
+
+// do is a method of the httpTransport struct that sends a request and decodes its result.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - r (request): The request.
+//   - result (any): A pointer to the value that the result is decoded into. Can be nil.
+//
+// Returns:
+//   - error: An *Error if the server responded with an error status, or the error of the request.
+func (t *httpTransport) do(ctx context.Context, r request, result any) error {
+	var resp, err = t.send(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Decode the result
+	if result == nil {
+		return nil
+	} else if r.raw {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return err
+	}
+	return json.Unmarshal(envelope.Data, result)
+}
+
+// send is a method of the httpTransport struct that sends a request, and returns the response if it succeeded.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - r (request): The request.
+//
+// Returns:
+//   - *http.Response: The response. The caller must close its body.
+//   - error: An *Error if the server responded with an error status, or the error of the request.
+func (t *httpTransport) send(ctx context.Context, r request) (*http.Response, error) {
+	// Set the query parameters
+	var query url.Values = url.Values{}
+	for name, value := range r.params {
+		if s, err := queryValue(value); err != nil {
+			return nil, err
+		} else {
+			query.Set(name, s)
+		}
+	}
+	if len(r.collection) > 0 {
+		query.Set("collection", r.collection)
+	}
+	var u string = t.url + r.path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	// Encode the body
+	var body io.Reader
+	if r.body != nil {
+		if data, err := json.Marshal(r.body); err != nil {
+			return nil, err
+		} else {
+			body = bytes.NewReader(data)
+		}
+	}
+
+	// Create the request
+	var req, err = http.NewRequestWithContext(ctx, r.method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(t.token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+
+	// Send the request
+	resp, err := t.client.Do(req)
+	if err != nil {
+		if isDialError(err) {
+			return nil, &sendError{err}
+		}
+		return nil, err
+	} else if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return resp, nil
+}
+
+// close is a method of the httpTransport struct that closes the idle connections.
+// Returns:
+//   - error: Always nil.
+func (t *httpTransport) close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+// queryValue is a function that encodes a param as a query parameter.
+// Parameters:
+//   - value (any): The param. Strings, ints and bools are sent as is, and the other values as JSON.
+//
+// Returns:
+//   - string: The query parameter.
+//   - error: An error if the param could not be encoded, or nil if successful.
+func queryValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int:
+		return strconv.Itoa(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	var data, err = json.Marshal(value)
+	return string(data), err
+}
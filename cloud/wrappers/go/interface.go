@@ -0,0 +1,48 @@
+package hermescloud
+
+import (
+	"context"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// Cache is an interface of the functions of a cache, that is satisfied by the Client over either transport,
+// and by an embedded hermes.Cache wrapped with Local, so that the code that uses a cache can switch between them.
+// The errors match the same kinds with errors.Is, for example ErrNotFound for a key that does not exist.
+type Cache interface {
+	Set(ctx context.Context, key string, value map[string]any) error
+	Get(ctx context.Context, key string) (map[string]any, error)
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	Keys(ctx context.Context) ([]string, error)
+	Values(ctx context.Context) ([]map[string]any, error)
+	Length(ctx context.Context) (int, error)
+	Clean(ctx context.Context) error
+	Info(ctx context.Context) (map[string]any, error)
+	GetSchema(ctx context.Context) (*hermes.Schema, error)
+	SetSchema(ctx context.Context, schema *hermes.Schema) error
+	FTInit(ctx context.Context, maxSize, maxBytes, minWordLength int) error
+	FTInitWithMap(ctx context.Context, data map[string]map[string]any, maxSize, maxBytes, minWordLength int) error
+	FTClean(ctx context.Context) error
+	FTIsInitialized(ctx context.Context) (bool, error)
+	FTSetMaxBytes(ctx context.Context, maxBytes int) error
+	FTSetMaxSize(ctx context.Context, maxSize int) error
+	FTSetMinWordLength(ctx context.Context, minWordLength int) error
+	FTStorage(ctx context.Context) (map[string]any, error)
+	FTStorageSize(ctx context.Context) (int, error)
+	FTStorageLength(ctx context.Context) (int, error)
+	FTSequenceIndices(ctx context.Context) error
+	Search(ctx context.Context, sp hermes.SearchParams) ([]map[string]any, error)
+	SearchOneWord(ctx context.Context, sp hermes.SearchParams) ([]map[string]any, error)
+	SearchValues(ctx context.Context, sp hermes.SearchParams) ([]map[string]any, error)
+	SearchWithKey(ctx context.Context, sp hermes.SearchParams) ([]map[string]any, error)
+	RegisterQuery(ctx context.Context, id string, sp hermes.SearchParams) error
+	UnregisterQuery(ctx context.Context, id string) error
+	Percolate(ctx context.Context, doc map[string]any) ([]string, error)
+}
+
+// The client and the embedded cache satisfy the interface
+var (
+	_ Cache = (*Client)(nil)
+	_ Cache = (*local)(nil)
+)
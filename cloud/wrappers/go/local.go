@@ -0,0 +1,472 @@
+package hermescloud
+
+import (
+	"context"
+	"fmt"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+// local is a struct that adapts an embedded cache to the Cache interface.
+// The methods return the error of the context if it's done, and wrap the errors of the cache in an *Error,
+// so that they match the same kinds as the errors of the client.
+// Fields:
+//   - cache (*hermes.Cache): The embedded cache.
+type local struct {
+	cache *hermes.Cache
+}
+
+// Local is a function that adapts an embedded cache to the Cache interface.
+// Parameters:
+//   - c (*hermes.Cache): The embedded cache.
+//
+// Returns:
+//   - Cache: The cache, with the methods of the client.
+func Local(c *hermes.Cache) Cache {
+	return &local{cache: c}
+}
+
+// failed is a function that wraps an error of the cache in an *Error.
+// Parameters:
+//   - code (string): The error code.
+//   - err (error): The error of the cache. Can be nil.
+//
+// Returns:
+//   - error: The *Error, or nil if the error is nil.
+func failed(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Message: err.Error(), err: err}
+}
+
+// Set is a method of the local struct that sets a record in the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - key (string): The key of the record. It must not exist.
+//   - value (map[string]any): The record.
+//
+// Returns:
+//   - error: An *Error that matches ErrFailed if the record could not be set, or the error of the context.
+func (l *local) Set(ctx context.Context, key string, value map[string]any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return failed(CodeFailed, l.cache.Set(key, value))
+}
+
+// Get is a method of the local struct that gets a record of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - map[string]any: The record.
+//   - error: An *Error that matches ErrNotFound if the key does not exist, or the error of the context.
+func (l *local) Get(ctx context.Context, key string) (map[string]any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	} else if value := l.cache.Get(key); value != nil {
+		return value, nil
+	}
+	return nil, &Error{Code: CodeNotFound, Message: fmt.Sprintf("key %s does not exist", key)}
+}
+
+// Delete is a method of the local struct that deletes a record of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - error: The error of the context.
+func (l *local) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.cache.Delete(key)
+	return nil
+}
+
+// Exists is a method of the local struct that checks whether a key exists in the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - key (string): The key.
+//
+// Returns:
+//   - bool: Whether the key exists.
+//   - error: The error of the context.
+func (l *local) Exists(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return l.cache.Exists(key), nil
+}
+
+// Keys is a method of the local struct that gets the keys of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - []string: The keys.
+//   - error: The error of the context.
+func (l *local) Keys(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return l.cache.Keys(), nil
+}
+
+// Values is a method of the local struct that gets the records of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - []map[string]any: The records.
+//   - error: The error of the context.
+func (l *local) Values(ctx context.Context) ([]map[string]any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return l.cache.Values(), nil
+}
+
+// Length is a method of the local struct that gets the number of records in the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - int: The number of records.
+//   - error: The error of the context.
+func (l *local) Length(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return l.cache.Length(), nil
+}
+
+// Clean is a method of the local struct that deletes every record of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - error: The error of the context.
+func (l *local) Clean(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.cache.Clean()
+	return nil
+}
+
+// Info is a method of the local struct that gets the number of records, and the size of the full-text index.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - map[string]any: The info of the cache.
+//   - error: An *Error that matches ErrFailed if the info could not be read, or the error of the context.
+func (l *local) Info(ctx context.Context) (map[string]any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var info, err = l.cache.Info()
+	return info, failed(CodeFailed, err)
+}
+
+// GetSchema is a method of the local struct that gets the schema of the records of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - *hermes.Schema: The schema, or nil if no schema is set.
+//   - error: The error of the context.
+func (l *local) GetSchema(ctx context.Context) (*hermes.Schema, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return l.cache.GetSchema(), nil
+}
+
+// SetSchema is a method of the local struct that sets the schema of the records of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - schema (*hermes.Schema): The schema. Nil removes the schema.
+//
+// Returns:
+//   - error: An *Error that matches ErrFailed if the schema is invalid, or the error of the context.
+func (l *local) SetSchema(ctx context.Context, schema *hermes.Schema) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return failed(CodeFailed, l.cache.SetSchema(schema))
+}
+
+// FTInit is a method of the local struct that initializes the full-text index of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - maxSize (int): The maximum number of words in the full-text index. -1 for no limit.
+//   - maxBytes (int): The maximum size of the full-text index, in bytes. -1 for no limit.
+//   - minWordLength (int): The minimum length of the indexed words.
+//
+// Returns:
+//   - error: An *Error that matches ErrFailed if the index is already initialized, or the error of the context.
+func (l *local) FTInit(ctx context.Context, maxSize, maxBytes, minWordLength int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return failed(CodeFailed, l.cache.FTInit(maxSize, maxBytes, minWordLength))
+}
+
+// FTInitWithMap is a method of the local struct that initializes the full-text index of the cache with records.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - data (map[string]map[string]any): The records, mapped by key.
+//   - maxSize (int): The maximum number of words in the full-text index. -1 for no limit.
+//   - maxBytes (int): The maximum size of the full-text index, in bytes. -1 for no limit.
+//   - minWordLength (int): The minimum length of the indexed words.
+//
+// Returns:
+//   - error: An *Error that matches ErrFailed if the index could not be initialized, or the error of the context.
+func (l *local) FTInitWithMap(ctx context.Context, data map[string]map[string]any, maxSize, maxBytes, minWordLength int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return failed(CodeFailed, l.cache.FTInitWithMap(data, maxSize, maxBytes, minWordLength))
+}
+
+// FTClean is a method of the local struct that clears the full-text index of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - error: An *Error that matches ErrFailed if the index is not initialized, or the error of the context.
+func (l *local) FTClean(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return failed(CodeFailed, l.cache.FTClean())
+}
+
+// FTIsInitialized is a method of the local struct that checks whether the full-text index of the cache is initialized.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - bool: Whether the index is initialized.
+//   - error: The error of the context.
+func (l *local) FTIsInitialized(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return l.cache.FTIsInitialized(), nil
+}
+
+// FTSetMaxBytes is a method of the local struct that sets the maximum size of the full-text index.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - maxBytes (int): The maximum size, in bytes. -1 for no limit.
+//
+// Returns:
+//   - error: An *Error that matches ErrFailed if the size could not be set, or the error of the context.
+func (l *local) FTSetMaxBytes(ctx context.Context, maxBytes int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return failed(CodeFailed, l.cache.FTSetMaxBytes(maxBytes))
+}
+
+// FTSetMaxSize is a method of the local struct that sets the maximum number of words in the full-text index.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - maxSize (int): The maximum number of words. -1 for no limit.
+//
+// Returns:
+//   - error: An *Error that matches ErrFailed if the size could not be set, or the error of the context.
+func (l *local) FTSetMaxSize(ctx context.Context, maxSize int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return failed(CodeFailed, l.cache.FTSetMaxSize(maxSize))
+}
+
+// FTSetMinWordLength is a method of the local struct that sets the minimum length of the indexed words.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - minWordLength (int): The minimum length of the words.
+//
+// Returns:
+//   - error: An *Error that matches ErrFailed if the length could not be set, or the error of the context.
+func (l *local) FTSetMinWordLength(ctx context.Context, minWordLength int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return failed(CodeFailed, l.cache.FTSetMinWordLength(minWordLength))
+}
+
+// FTStorage is a method of the local struct that gets the full-text index of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - map[string]any: The full-text index.
+//   - error: An *Error that matches ErrFailed if the index is not initialized, or the error of the context.
+func (l *local) FTStorage(ctx context.Context) (map[string]any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var storage, err = l.cache.FTStorage()
+	return storage, failed(CodeFailed, err)
+}
+
+// FTStorageSize is a method of the local struct that gets the size of the full-text index, in bytes.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - int: The size of the index.
+//   - error: An *Error that matches ErrFailed if the index is not initialized, or the error of the context.
+func (l *local) FTStorageSize(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	var size, err = l.cache.FTStorageSize()
+	return size, failed(CodeFailed, err)
+}
+
+// FTStorageLength is a method of the local struct that gets the number of words in the full-text index.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - int: The number of words.
+//   - error: An *Error that matches ErrFailed if the index is not initialized, or the error of the context.
+func (l *local) FTStorageLength(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	var length, err = l.cache.FTStorageLength()
+	return length, failed(CodeFailed, err)
+}
+
+// FTSequenceIndices is a method of the local struct that renumbers the record indices of the full-text index.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//
+// Returns:
+//   - error: The error of the context.
+func (l *local) FTSequenceIndices(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.cache.FTSequenceIndices()
+	return nil
+}
+
+// Search is a method of the local struct that searches the full-text index of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - sp (hermes.SearchParams): The query, limit and strict params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: An *Error that matches ErrFailed if the search failed, or the error of the context.
+func (l *local) Search(ctx context.Context, sp hermes.SearchParams) ([]map[string]any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var result, err = l.cache.Search(sp)
+	return result, failed(CodeFailed, err)
+}
+
+// SearchOneWord is a method of the local struct that searches the full-text index of the cache for a single word.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - sp (hermes.SearchParams): The query, limit and strict params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: An *Error that matches ErrFailed if the search failed, or the error of the context.
+func (l *local) SearchOneWord(ctx context.Context, sp hermes.SearchParams) ([]map[string]any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var result, err = l.cache.SearchOneWord(sp)
+	return result, failed(CodeFailed, err)
+}
+
+// SearchValues is a method of the local struct that searches the values of the records of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - sp (hermes.SearchParams): The query, limit and schema params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: An *Error that matches ErrFailed if the search failed, or the error of the context.
+func (l *local) SearchValues(ctx context.Context, sp hermes.SearchParams) ([]map[string]any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var result, err = l.cache.SearchValues(sp)
+	return result, failed(CodeFailed, err)
+}
+
+// SearchWithKey is a method of the local struct that searches the values of a field of the records of the cache.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - sp (hermes.SearchParams): The query, key and limit params of the search.
+//
+// Returns:
+//   - []map[string]any: The records that match the search.
+//   - error: An *Error that matches ErrFailed if the search failed, or the error of the context.
+func (l *local) SearchWithKey(ctx context.Context, sp hermes.SearchParams) ([]map[string]any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var result, err = l.cache.SearchWithKey(sp)
+	return result, failed(CodeFailed, err)
+}
+
+// RegisterQuery is a method of the local struct that registers a search query, so that documents can be percolated against it.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - id (string): The id of the query.
+//   - sp (hermes.SearchParams): The query and strict params of the search.
+//
+// Returns:
+//   - error: An *Error that matches ErrFailed if the query could not be registered, or the error of the context.
+func (l *local) RegisterQuery(ctx context.Context, id string, sp hermes.SearchParams) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return failed(CodeFailed, l.cache.RegisterQuery(id, sp))
+}
+
+// UnregisterQuery is a method of the local struct that removes a registered search query.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - id (string): The id of the query.
+//
+// Returns:
+//   - error: An *Error that matches ErrNotFound if no query is registered with the id, or the error of the context.
+func (l *local) UnregisterQuery(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return failed(CodeNotFound, l.cache.UnregisterQuery(id))
+}
+
+// Percolate is a method of the local struct that gets the ids of the registered queries that a document matches.
+// Parameters:
+//   - ctx (context.Context): The context of the call.
+//   - doc (map[string]any): The document.
+//
+// Returns:
+//   - []string: The ids of the matching queries.
+//   - error: An *Error that matches ErrFailed if the document could not be percolated, or the error of the context.
+func (l *local) Percolate(ctx context.Context, doc map[string]any) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var ids, err = l.cache.Percolate(doc)
+	return ids, failed(CodeFailed, err)
+}
@@ -1,5 +0,0 @@
-package hermescloud
-
-func Get() {
-	// send http request
-}
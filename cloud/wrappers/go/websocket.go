@@ -0,0 +1,373 @@
+package hermescloud
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fasthttp/websocket"
+)
+
+// errClosed is the error of the requests of a closed client.
+var errClosed = errors.New("hermes: client closed")
+
+// wsTransport is a struct that sends the requests to the websocket, over a pool of connections.
+// The requests are spread over the connections, and a connection that failed is opened again by the next request that uses it.
+// Fields:
+//   - url (string): The url of the websocket, for example ws://localhost:3000/ws/hermes.
+//   - header (http.Header): The headers of the handshake, with the token of the client.
+//   - dialer (*websocket.Dialer): The dialer of the connections.
+//   - slots ([]*wsSlot): The slots of the connections.
+//   - next (atomic.Uint64): The counter that picks the slot of the next request.
+//   - closed (atomic.Bool): Whether the transport was closed.
+type wsTransport struct {
+	url    string
+	header http.Header
+	dialer *websocket.Dialer
+	slots  []*wsSlot
+	next   atomic.Uint64
+	closed atomic.Bool
+}
+
+// wsSlot is a struct that holds a connection of the pool.
+// Fields:
+//   - mutex (sync.Mutex): The mutex that guards the connection, so that a single request opens it.
+//   - conn (*wsConn): The connection. Nil if it was not opened yet.
+type wsSlot struct {
+	mutex sync.Mutex
+	conn  *wsConn
+}
+
+// newWebSocket is a function that creates the websocket transport of a client.
+// Parameters:
+//   - baseURL (string): The url of the server.
+//   - c (*Client): The client, with its options set.
+//
+// Returns:
+//   - *wsTransport: A pointer to the transport.
+func newWebSocket(baseURL string, c *Client) *wsTransport {
+	var t *wsTransport = &wsTransport{
+		url:    baseURL + "/ws/hermes",
+		header: http.Header{},
+		dialer: &websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: 10 * time.Second,
+			TLSClientConfig:  c.tls,
+		},
+	}
+	if len(c.token) > 0 {
+		t.header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	// Create the slots of the pool
+	var size int = 4
+	if c.poolSize > 0 {
+		size = c.poolSize
+	}
+	for i := 0; i < size; i++ {
+		t.slots = append(t.slots, &wsSlot{})
+	}
+	return t
+}
+
+// do is a method of the wsTransport struct that calls the function of a request and decodes its result.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - r (request): The request.
+//   - result (any): A pointer to the value that the result is decoded into. Can be nil.
+//
+// Returns:
+//   - error: An *Error if the server replied with an error, or the error of the connection.
+func (t *wsTransport) do(ctx context.Context, r request, result any) error {
+	if len(r.function) == 0 {
+		return fmt.Errorf("hermes: %s is not supported over the websocket", r.path)
+	}
+	var params, err = wsParams(r)
+	if err != nil {
+		return err
+	}
+	conn, err := t.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.call(ctx, r.function, params, result)
+}
+
+// conn is a method of the wsTransport struct that returns the connection of the next slot, and opens it if it's not open.
+// Parameters:
+//   - ctx (context.Context): The context of the request, which bounds the dial.
+//
+// Returns:
+//   - *wsConn: A pointer to the connection.
+//   - error: A *sendError if the connection could not be opened, or nil if successful.
+func (t *wsTransport) conn(ctx context.Context) (*wsConn, error) {
+	var slot *wsSlot = t.slots[t.next.Add(1)%uint64(len(t.slots))]
+	slot.mutex.Lock()
+	defer slot.mutex.Unlock()
+	if t.closed.Load() {
+		return nil, errClosed
+	} else if slot.conn != nil && !slot.conn.isClosed() {
+		return slot.conn, nil
+	}
+
+	// Open the connection
+	var ws, resp, err = t.dialer.DialContext(ctx, t.url, t.header)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		} else if resp != nil && resp.StatusCode >= 400 {
+			return nil, &sendError{responseError(resp)}
+		}
+		return nil, &sendError{err}
+	}
+	slot.conn = newWSConn(ws)
+	return slot.conn, nil
+}
+
+// close is a method of the wsTransport struct that closes the connections of the pool.
+// Returns:
+//   - error: Always nil.
+func (t *wsTransport) close() error {
+	t.closed.Store(true)
+	for _, slot := range t.slots {
+		slot.mutex.Lock()
+		if slot.conn != nil {
+			slot.conn.fail(errClosed)
+		}
+		slot.mutex.Unlock()
+	}
+	return nil
+}
+
+// wsParams is a function that returns the websocket params of a request.
+// The body, and the params that are not strings, ints or bools, are sent as base64-encoded JSON.
+// Parameters:
+//   - r (request): The request.
+//
+// Returns:
+//   - map[string]any: The params.
+//   - error: An error if a param could not be encoded, or nil if successful.
+func wsParams(r request) (map[string]any, error) {
+	var params map[string]any = make(map[string]any, len(r.params)+2)
+	for name, value := range r.params {
+		switch value.(type) {
+		case string, int, bool:
+			params[name] = value
+		default:
+			if s, err := encode(value); err != nil {
+				return nil, err
+			} else {
+				params[name] = s
+			}
+		}
+	}
+	if len(r.bodyParam) > 0 {
+		if s, err := encode(r.body); err != nil {
+			return nil, err
+		} else {
+			params[r.bodyParam] = s
+		}
+	}
+	if len(r.collection) > 0 {
+		params["collection"] = r.collection
+	}
+	return params, nil
+}
+
+// encode is a function that encodes a value as base64-encoded JSON.
+// Parameters:
+//   - v (any): The value.
+//
+// Returns:
+//   - string: The encoded value.
+//   - error: An error if the value could not be encoded, or nil if successful.
+func encode(v any) (string, error) {
+	var data, err = json.Marshal(v)
+	return base64.StdEncoding.EncodeToString(data), err
+}
+
+// wsReply is a struct that represents a reply of the websocket.
+// Fields:
+//   - ID (json.RawMessage): The id of the request.
+//   - Ok (bool): Whether the function succeeded.
+//   - Result (json.RawMessage): The result of the function. Empty if the result is null.
+//   - Error (*Error): The error of the function, with its code and message.
+type wsReply struct {
+	ID     json.RawMessage `json:"id"`
+	Ok     bool            `json:"ok"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// wsConn is a struct that represents a connection of the pool. The requests are sent with an id,
+// and a goroutine reads the replies and passes them to the requests that wait for them.
+// Fields:
+//   - ws (*websocket.Conn): The connection.
+//   - write (sync.Mutex): The mutex that serializes the writes.
+//   - mutex (sync.Mutex): The mutex that guards the pending requests.
+//   - pending (map[uint64]chan wsReply): The channels of the requests that wait for their reply, mapped by id.
+//   - id (uint64): The id of the last request.
+//   - done (chan struct{}): The channel that is closed when the connection fails.
+//   - err (error): The error that the connection failed with.
+type wsConn struct {
+	ws      *websocket.Conn
+	write   sync.Mutex
+	mutex   sync.Mutex
+	pending map[uint64]chan wsReply
+	id      uint64
+	done    chan struct{}
+	err     error
+}
+
+// newWSConn is a function that wraps an open connection, and starts reading its replies.
+// Parameters:
+//   - ws (*websocket.Conn): The connection.
+//
+// Returns:
+//   - *wsConn: A pointer to the connection of the pool.
+func newWSConn(ws *websocket.Conn) *wsConn {
+	var c *wsConn = &wsConn{
+		ws:      ws,
+		pending: make(map[uint64]chan wsReply),
+		done:    make(chan struct{}),
+	}
+	go c.read()
+	return c
+}
+
+// read is a method of the wsConn struct that reads the replies, until the connection fails.
+// The messages that are not replies to a pending request are ignored.
+func (c *wsConn) read() {
+	for {
+		var _, msg, err = c.ws.ReadMessage()
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		var reply wsReply
+		if err := json.Unmarshal(msg, &reply); err != nil {
+			continue
+		}
+		if id, err := strconv.ParseUint(string(reply.ID), 10, 64); err == nil {
+			c.mutex.Lock()
+			var ch, ok = c.pending[id]
+			delete(c.pending, id)
+			c.mutex.Unlock()
+			if ok {
+				ch <- reply
+			}
+		}
+	}
+}
+
+// call is a method of the wsConn struct that calls a function and waits for its reply.
+// Parameters:
+//   - ctx (context.Context): The context of the request.
+//   - function (string): The function.
+//   - params (map[string]any): The params of the function.
+//   - result (any): A pointer to the value that the result is decoded into. Can be nil.
+//
+// Returns:
+//   - error: An *Error if the function failed, a *sendError if the request could not be written, or the error of the connection.
+func (c *wsConn) call(ctx context.Context, function string, params map[string]any, result any) error {
+	// Register the request
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var ch chan wsReply = make(chan wsReply, 1)
+	c.mutex.Lock()
+	if c.isClosed() {
+		c.mutex.Unlock()
+		return &sendError{c.err}
+	}
+	c.id++
+	var id uint64 = c.id
+	c.pending[id] = ch
+	c.mutex.Unlock()
+
+	// Write the request
+	var msg, err = json.Marshal(map[string]any{"v": 1, "id": id, "function": function, "params": params})
+	if err != nil {
+		c.cancel(id)
+		return err
+	}
+	var deadline, _ = ctx.Deadline()
+	c.write.Lock()
+	c.ws.SetWriteDeadline(deadline)
+	err = c.ws.WriteMessage(websocket.TextMessage, msg)
+	c.write.Unlock()
+	if err != nil {
+		// The message may be partly written, so the connection can't be used
+		c.cancel(id)
+		c.fail(err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &sendError{err}
+	}
+
+	// Wait for the reply
+	select {
+	case reply := <-ch:
+		if !reply.Ok {
+			if reply.Error == nil {
+				return &Error{Code: CodeInternal, Message: "no error in the reply"}
+			}
+			return &Error{Code: reply.Error.Code, Message: reply.Error.Message}
+		} else if result != nil && len(reply.Result) > 0 {
+			return json.Unmarshal(reply.Result, result)
+		}
+		return nil
+	case <-c.done:
+		return fmt.Errorf("hermes: websocket connection failed: %w", c.err)
+	case <-ctx.Done():
+		c.cancel(id)
+		return ctx.Err()
+	}
+}
+
+// cancel is a method of the wsConn struct that stops waiting for the reply of a request.
+// Parameters:
+//   - id (uint64): The id of the request.
+func (c *wsConn) cancel(id uint64) {
+	c.mutex.Lock()
+	delete(c.pending, id)
+	c.mutex.Unlock()
+}
+
+// fail is a method of the wsConn struct that closes the connection, and fails its pending requests.
+// Parameters:
+//   - err (error): The error of the connection.
+func (c *wsConn) fail(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.isClosed() {
+		return
+	}
+	c.err = err
+	close(c.done)
+	c.pending = make(map[uint64]chan wsReply)
+	c.ws.Close()
+}
+
+// isClosed is a method of the wsConn struct that checks whether the connection failed.
+// Returns:
+//   - bool: Whether the connection failed.
+func (c *wsConn) isClosed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
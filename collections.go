@@ -0,0 +1,160 @@
+package hermes
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultCollection is the name of the collection that is used when no collection name is provided.
+const DefaultCollection string = "default"
+
+// ErrCollectionExists is the error of Create when a collection with the same name already exists.
+var ErrCollectionExists error = errors.New("collection already exists")
+
+// Collections is a struct that holds named caches, so that several datasets can share one hermes server.
+// Each collection is a separate Cache, with its own data, full-text settings and schema.
+//
+// Fields:
+//   - mutex (*sync.RWMutex): A RWMutex that guards access to the caches map.
+//   - caches (map[string]*Cache): The caches, mapped by collection name.
+type Collections struct {
+	mutex  *sync.RWMutex
+	caches map[string]*Cache
+}
+
+// InitCollections is a function that initializes a new Collections struct with an empty default collection.
+//
+// Returns:
+//   - A pointer to a new Collections struct.
+func InitCollections() *Collections {
+	return &Collections{
+		mutex: &sync.RWMutex{},
+		caches: map[string]*Cache{
+			DefaultCollection: InitCache(),
+		},
+	}
+}
+
+// Create is a method of the Collections struct that creates a new empty collection.
+// This method is thread-safe.
+//
+// Parameters:
+//   - name (string): The name of the collection.
+//
+// Returns:
+//   - *Cache: The cache of the new collection.
+//   - error: If the name is empty, or a collection with the same name already exists (ErrCollectionExists).
+func (cs *Collections) Create(name string) (*Cache, error) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	// Verify the name
+	if len(name) == 0 {
+		return nil, errors.New("invalid collection name")
+	} else if _, ok := cs.caches[name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrCollectionExists, name)
+	}
+
+	// Create the collection
+	var c *Cache = InitCache()
+	cs.caches[name] = c
+	return c, nil
+}
+
+// Drop is a method of the Collections struct that removes a collection and all of its data.
+// The default collection can't be dropped.
+// This method is thread-safe.
+//
+// Parameters:
+//   - name (string): The name of the collection.
+//
+// Returns:
+//   - error: If the collection is the default collection, or doesn't exist.
+func (cs *Collections) Drop(name string) error {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	// Verify the name
+	if name == DefaultCollection {
+		return errors.New("the default collection can't be dropped")
+	} else if _, ok := cs.caches[name]; !ok {
+		return fmt.Errorf("collection %s does not exist", name)
+	}
+
+	// Drop the collection
+	delete(cs.caches, name)
+	return nil
+}
+
+// Replace is a method of the Collections struct that sets the cache of a collection, so that a collection
+// can be swapped with a cache that was filled separately. The collection is created if it doesn't exist.
+// This method is thread-safe.
+//
+// Parameters:
+//   - name (string): The name of the collection.
+//   - c (*Cache): The new cache of the collection.
+//
+// Returns:
+//   - error: If the name is empty or the cache is nil.
+func (cs *Collections) Replace(name string, c *Cache) error {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	// Verify the name and the cache
+	if len(name) == 0 {
+		return errors.New("invalid collection name")
+	} else if c == nil {
+		return errors.New("invalid cache")
+	}
+
+	// Replace the collection
+	cs.caches[name] = c
+	return nil
+}
+
+// Get is a method of the Collections struct that returns the cache of a collection.
+// This method is thread-safe.
+//
+// Parameters:
+//   - name (string): The name of the collection. If empty, the default collection is returned.
+//
+// Returns:
+//   - *Cache: The cache of the collection.
+//   - error: If the collection doesn't exist.
+func (cs *Collections) Get(name string) (*Cache, error) {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
+
+	// Use the default collection if no name is provided
+	if len(name) == 0 {
+		name = DefaultCollection
+	}
+
+	// Get the collection
+	if c, ok := cs.caches[name]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("collection %s does not exist", name)
+}
+
+// List is a method of the Collections struct that returns the names of the collections.
+// This method is thread-safe.
+//
+// Returns:
+//   - []string: The sorted collection names.
+func (cs *Collections) List() []string {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
+
+	// Get the names
+	var names []string = make([]string, 0, len(cs.caches))
+	for name := range cs.caches {
+		names = append(names, name)
+	}
+
+	// Sort and return the names
+	sort.Strings(names)
+	return names
+}
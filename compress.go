@@ -0,0 +1,251 @@
+package hermes
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/realTristan/hermes/compression"
+)
+
+// CompressionMode is the type of the modes that can be used to store the cache values compressed.
+type CompressionMode int
+
+const (
+	// CompressFields compresses each string field of a record separately.
+	CompressFields CompressionMode = iota
+	// CompressDocuments compresses all the string fields of a record together, as a single value.
+	CompressDocuments
+)
+
+// compressedDocumentKey is the key that the compressed string fields of a record are stored under
+// when the CompressDocuments mode is used.
+const compressedDocumentKey string = "$hermes.compressed"
+
+// compressed is the type of a value that has been compressed by the cache compressor.
+type compressed []byte
+
+// compressor is a struct that compresses and decompresses the records stored in the cache.
+// Only string values are compressed, so that the other field types are kept as-is.
+//
+// Fields:
+//   - codec (compression.Codec): The codec used to compress the values.
+//   - mode (CompressionMode): Whether the fields are compressed separately or together.
+//   - fields (map[string]bool): The fields to compress. If empty, every string field is compressed.
+type compressor struct {
+	codec  compression.Codec
+	mode   CompressionMode
+	fields map[string]bool
+}
+
+// SetCompression is a method of the Cache struct that sets the codec used to store the cache values compressed.
+// The values that are already stored in the cache are re-compressed with the new codec.
+// The values are decompressed when they're returned from Get, Values, or a search.
+// This method is thread-safe.
+//
+// Parameters:
+//   - codec (compression.Codec): The codec to compress the values with. If nil, compression is disabled.
+//   - mode (CompressionMode): Whether to compress each field separately, or all the fields of a record together.
+//   - fields (...string): The fields to compress. If none are provided, every string field is compressed.
+//
+// Returns:
+//   - error: An error if the mode is invalid or one of the values could not be compressed or decompressed.
+func (c *Cache) SetCompression(codec compression.Codec, mode CompressionMode, fields ...string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.setCompression(codec, mode, fields)
+}
+
+// setCompression is a method of the Cache struct that sets the codec used to store the cache values compressed.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - codec (compression.Codec): The codec to compress the values with. If nil, compression is disabled.
+//   - mode (CompressionMode): Whether to compress each field separately, or all the fields of a record together.
+//   - fields ([]string): The fields to compress. If empty, every string field is compressed.
+//
+// Returns:
+//   - error: An error if the mode is invalid or one of the values could not be compressed or decompressed.
+func (c *Cache) setCompression(codec compression.Codec, mode CompressionMode, fields []string) error {
+	if mode != CompressFields && mode != CompressDocuments {
+		return errors.New("invalid compression mode")
+	}
+
+	// Create the new compressor
+	var cp *compressor = nil
+	if codec != nil {
+		cp = &compressor{
+			codec:  codec,
+			mode:   mode,
+			fields: make(map[string]bool, len(fields)),
+		}
+		for _, field := range fields {
+			cp.fields[field] = true
+		}
+	}
+
+	// Re-compress the data with the new compressor
+	var data map[string]map[string]any = make(map[string]map[string]any, len(c.data))
+	for key, value := range c.data {
+		if v, err := c.compression.decompress(value); err != nil {
+			return err
+		} else if v, err := cp.compress(v); err != nil {
+			return err
+		} else {
+			data[key] = v
+		}
+	}
+
+	// Update the cache variables
+	c.data = data
+	c.compression = cp
+
+	// Return no error
+	return nil
+}
+
+// record is a method of the Cache struct that returns the decompressed value stored under a key.
+// A record that can't be decompressed is dropped, as if the key didn't exist.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - map[string]any: The decompressed record, or nil if the key doesn't exist or the record can't be decompressed.
+func (c *Cache) record(key string) map[string]any {
+	if v, err := c.materialize(c.data[key]); err == nil {
+		return v
+	}
+	return nil
+}
+
+// appendRecord is a method of the Cache struct that appends the decompressed value stored under a key to a result.
+// A record that can't be decompressed is dropped from the result.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - result ([]map[string]any): The result to append the record to.
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - []map[string]any: The result, with the record appended.
+func (c *Cache) appendRecord(result []map[string]any, key string) []map[string]any {
+	if record := c.record(key); record != nil {
+		return append(result, record)
+	}
+	return result
+}
+
+// materialize is a method of the Cache struct that decompresses a record stored in the cache.
+// Since the records are compressed by the cache itself, a decompression error can only happen if the
+// stored bytes were corrupted.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - value (map[string]any): The record to decompress.
+//
+// Returns:
+//   - map[string]any: The decompressed record.
+//   - error: An error if the record could not be decompressed.
+func (c *Cache) materialize(value map[string]any) (map[string]any, error) {
+	return c.compression.decompress(value)
+}
+
+// compress is a method of the compressor struct that compresses the string values of a record.
+// The provided record is not modified. If the compressor is nil, the record is returned as-is.
+//
+// Parameters:
+//   - value (map[string]any): The record to compress.
+//
+// Returns:
+//   - map[string]any: The compressed record.
+//   - error: An error if the values could not be compressed.
+func (cp *compressor) compress(value map[string]any) (map[string]any, error) {
+	if cp == nil || value == nil {
+		return value, nil
+	}
+
+	// Copy the record, and collect the string values to compress
+	var (
+		result map[string]any    = make(map[string]any, len(value))
+		texts  map[string]string = make(map[string]string)
+	)
+	for k, v := range value {
+		if s, ok := v.(string); ok && (len(cp.fields) == 0 || cp.fields[k]) {
+			texts[k] = s
+			continue
+		}
+		result[k] = v
+	}
+
+	// Compress each field separately
+	if cp.mode == CompressFields {
+		for k, s := range texts {
+			if b, err := cp.codec.Compress([]byte(s)); err != nil {
+				return nil, err
+			} else {
+				result[k] = compressed(b)
+			}
+		}
+		return result, nil
+	}
+
+	// Compress all the fields together
+	if len(texts) == 0 {
+		return result, nil
+	} else if b, err := json.Marshal(texts); err != nil {
+		return nil, err
+	} else if b, err := cp.codec.Compress(b); err != nil {
+		return nil, err
+	} else {
+		result[compressedDocumentKey] = compressed(b)
+	}
+	return result, nil
+}
+
+// decompress is a method of the compressor struct that decompresses the values of a record.
+// The provided record is not modified. If the compressor is nil, the record is returned as-is.
+//
+// Parameters:
+//   - value (map[string]any): The record to decompress.
+//
+// Returns:
+//   - map[string]any: The decompressed record.
+//   - error: An error if the values could not be decompressed.
+func (cp *compressor) decompress(value map[string]any) (map[string]any, error) {
+	if cp == nil || value == nil {
+		return value, nil
+	}
+
+	// Copy the record, and decompress the compressed values
+	var result map[string]any = make(map[string]any, len(value))
+	for k, v := range value {
+		var b, ok = v.(compressed)
+		if !ok {
+			result[k] = v
+			continue
+		}
+
+		// Decompress the value
+		var s, err = cp.codec.Decompress(b)
+		if err != nil {
+			return nil, err
+		}
+
+		// If the value is a single field, set it
+		if k != compressedDocumentKey {
+			result[k] = string(s)
+			continue
+		}
+
+		// Else, the value contains all the string fields of the record
+		var texts map[string]string
+		if err := json.Unmarshal(s, &texts); err != nil {
+			return nil, err
+		}
+		for field, s := range texts {
+			result[field] = s
+		}
+	}
+	return result, nil
+}
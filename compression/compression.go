@@ -0,0 +1,12 @@
+package compression
+
+// Codec is an interface for the compression algorithms that can be used to store cache values compressed.
+// The gzip, zlib, zstd and snappy packages each provide a Codec struct that implements this interface.
+type Codec interface {
+	// Name returns the name of the compression algorithm.
+	Name() string
+	// Compress compresses a byte slice.
+	Compress(v []byte) ([]byte, error)
+	// Decompress decompresses a byte slice that was compressed with Compress.
+	Decompress(v []byte) ([]byte, error)
+}
@@ -0,0 +1,76 @@
+package snappy
+
+import (
+	"github.com/klauspost/compress/snappy"
+)
+
+// Compress is a function that compresses a byte slice using snappy compression.
+//
+// Parameters:
+//   - v: A byte slice representing the value to compress.
+//
+// Returns:
+//   - A byte slice representing the compressed value.
+//   - An error if there was an error compressing the value.
+//
+// Example usage:
+//
+//	compressed, err := Compress([]byte("value")) // compressed == []byte{...}, err == nil
+func Compress(v []byte) ([]byte, error) {
+	return snappy.Encode(nil, v), nil
+}
+
+// Decompress is a function that decompresses a byte slice using snappy decompression.
+//
+// Parameters:
+//   - v: A byte slice representing the compressed value to decompress.
+//
+// Returns:
+//   - A string representing the decompressed value.
+//   - An error if there was an error decompressing the value.
+//
+// Example usage:
+//
+//	decompressed, err := Decompress([]byte{...}) // decompressed == "value", err == nil
+func Decompress(v []byte) (string, error) {
+	if s, err := snappy.Decode(nil, v); err != nil {
+		return "", err
+	} else {
+		return string(s), nil
+	}
+}
+
+// Codec is a struct that implements the compression.Codec interface using snappy compression.
+type Codec struct{}
+
+// Name is a method of the Codec struct that returns the name of the codec.
+//
+// Returns:
+//   - A string representing the name of the codec.
+func (Codec) Name() string {
+	return "snappy"
+}
+
+// Compress is a method of the Codec struct that compresses a byte slice using snappy compression.
+//
+// Parameters:
+//   - v: A byte slice representing the value to compress.
+//
+// Returns:
+//   - A byte slice representing the compressed value.
+//   - An error if there was an error compressing the value.
+func (Codec) Compress(v []byte) ([]byte, error) {
+	return Compress(v)
+}
+
+// Decompress is a method of the Codec struct that decompresses a byte slice using snappy decompression.
+//
+// Parameters:
+//   - v: A byte slice representing the compressed value to decompress.
+//
+// Returns:
+//   - A byte slice representing the decompressed value.
+//   - An error if there was an error decompressing the value.
+func (Codec) Decompress(v []byte) ([]byte, error) {
+	return snappy.Decode(nil, v)
+}
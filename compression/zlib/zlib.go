@@ -45,12 +45,61 @@ func Compress(v []byte) ([]byte, error) {
 //
 //	decompressed, err := Decompress([]byte{...}) // decompressed == "value", err == nil
 func Decompress(v []byte) (string, error) {
-	var b *bytes.Buffer = bytes.NewBuffer(v)
-	if gz, err := zlib.NewReader(b); err != nil {
-		return "", err
-	} else if s, err := io.ReadAll(gz); err != nil {
+	if s, err := decompress(v); err != nil {
 		return "", err
 	} else {
 		return string(s), nil
 	}
 }
+
+// decompress is a function that decompresses a byte slice using zlib decompression.
+//
+// Parameters:
+//   - v: A byte slice representing the compressed value to decompress.
+//
+// Returns:
+//   - A byte slice representing the decompressed value.
+//   - An error if there was an error decompressing the value.
+func decompress(v []byte) ([]byte, error) {
+	var b *bytes.Buffer = bytes.NewBuffer(v)
+	if r, err := zlib.NewReader(b); err != nil {
+		return nil, err
+	} else {
+		return io.ReadAll(r)
+	}
+}
+
+// Codec is a struct that implements the compression.Codec interface using zlib compression.
+type Codec struct{}
+
+// Name is a method of the Codec struct that returns the name of the codec.
+//
+// Returns:
+//   - A string representing the name of the codec.
+func (Codec) Name() string {
+	return "zlib"
+}
+
+// Compress is a method of the Codec struct that compresses a byte slice using zlib compression.
+//
+// Parameters:
+//   - v: A byte slice representing the value to compress.
+//
+// Returns:
+//   - A byte slice representing the compressed value.
+//   - An error if there was an error compressing the value.
+func (Codec) Compress(v []byte) ([]byte, error) {
+	return Compress(v)
+}
+
+// Decompress is a method of the Codec struct that decompresses a byte slice using zlib decompression.
+//
+// Parameters:
+//   - v: A byte slice representing the compressed value to decompress.
+//
+// Returns:
+//   - A byte slice representing the decompressed value.
+//   - An error if there was an error decompressing the value.
+func (Codec) Decompress(v []byte) ([]byte, error) {
+	return decompress(v)
+}
@@ -0,0 +1,83 @@
+package zstd
+
+import (
+	"github.com/klauspost/compress/zstd"
+)
+
+// The encoder and decoder are safe for concurrent use when using EncodeAll and DecodeAll,
+// so they're shared between all calls.
+var (
+	encoder, _ = zstd.NewWriter(nil)
+	decoder, _ = zstd.NewReader(nil)
+)
+
+// Compress is a function that compresses a byte slice using zstd compression.
+//
+// Parameters:
+//   - v: A byte slice representing the value to compress.
+//
+// Returns:
+//   - A byte slice representing the compressed value.
+//   - An error if there was an error compressing the value.
+//
+// Example usage:
+//
+//	compressed, err := Compress([]byte("value")) // compressed == []byte{...}, err == nil
+func Compress(v []byte) ([]byte, error) {
+	return encoder.EncodeAll(v, nil), nil
+}
+
+// Decompress is a function that decompresses a byte slice using zstd decompression.
+//
+// Parameters:
+//   - v: A byte slice representing the compressed value to decompress.
+//
+// Returns:
+//   - A string representing the decompressed value.
+//   - An error if there was an error decompressing the value.
+//
+// Example usage:
+//
+//	decompressed, err := Decompress([]byte{...}) // decompressed == "value", err == nil
+func Decompress(v []byte) (string, error) {
+	if s, err := decoder.DecodeAll(v, nil); err != nil {
+		return "", err
+	} else {
+		return string(s), nil
+	}
+}
+
+// Codec is a struct that implements the compression.Codec interface using zstd compression.
+type Codec struct{}
+
+// Name is a method of the Codec struct that returns the name of the codec.
+//
+// Returns:
+//   - A string representing the name of the codec.
+func (Codec) Name() string {
+	return "zstd"
+}
+
+// Compress is a method of the Codec struct that compresses a byte slice using zstd compression.
+//
+// Parameters:
+//   - v: A byte slice representing the value to compress.
+//
+// Returns:
+//   - A byte slice representing the compressed value.
+//   - An error if there was an error compressing the value.
+func (Codec) Compress(v []byte) ([]byte, error) {
+	return Compress(v)
+}
+
+// Decompress is a method of the Codec struct that decompresses a byte slice using zstd decompression.
+//
+// Parameters:
+//   - v: A byte slice representing the compressed value to decompress.
+//
+// Returns:
+//   - A byte slice representing the decompressed value.
+//   - An error if there was an error decompressing the value.
+func (Codec) Decompress(v []byte) ([]byte, error) {
+	return decoder.DecodeAll(v, nil)
+}
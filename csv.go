@@ -0,0 +1,245 @@
+package hermes
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnType is the type of a csv column. The cells of a typed column are parsed into the matching go type.
+type ColumnType string
+
+// The column types that are supported by the csv importer.
+const (
+	ColumnString ColumnType = "string"
+	ColumnInt    ColumnType = "int"
+	ColumnFloat  ColumnType = "float"
+	ColumnBool   ColumnType = "bool"
+	ColumnDate   ColumnType = "date"
+)
+
+// CSVMapping is a struct that describes how the rows of a csv file are converted into cache records.
+// The first row of the csv file must be a header with the column names.
+type CSVMapping struct {
+	// The column that contains the key of each record
+	Key string
+	// The columns whose values are stored in the full-text index
+	FullText []string
+	// The types of the columns. Columns that aren't in the map are stored as strings
+	Types map[string]ColumnType
+	// The field delimiter. Defaults to ',' (use '\t' for tsv files)
+	Comma rune
+	// The layout used to parse the date columns. Defaults to "2006-01-02"
+	DateLayout string
+}
+
+// ReadCSV is a function that reads a csv file and converts its rows into records.
+// The full-text columns are wrapped in {"$hermes.full_text": true, "$hermes.value": "..."} maps,
+// except for the empty cells, which have nothing to index and are kept as empty strings.
+// The result can be passed to cache.FTInitWithMap() or encoded as a json file for cache.FTInitWithJson().
+//
+// Parameters:
+//   - r (io.Reader): The reader to read the csv data from.
+//   - m (CSVMapping): The mapping of the csv columns.
+//
+// Returns:
+//   - map[string]map[string]any: The records, mapped by key.
+//   - error: If the mapping is invalid, a cell can't be parsed, or a key is duplicated.
+func ReadCSV(r io.Reader, m CSVMapping) (map[string]map[string]any, error) {
+	var data map[string]map[string]any = make(map[string]map[string]any)
+	if err := readCSV(r, m, func(key string, value map[string]any) error {
+		if _, ok := data[key]; ok {
+			return fmt.Errorf("duplicate key %s", key)
+		}
+		data[key] = value
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ImportCSV is a method of the Cache struct that reads a csv file and sets its rows in the cache.
+// The rows are set one by one, and indexed in the full-text index if it's initialized.
+// If an error occurs, the rows that were set before it remain in the cache, just like with repeated calls to Set.
+// This method is thread-safe.
+//
+// Parameters:
+//   - r (io.Reader): The reader to read the csv data from.
+//   - m (CSVMapping): The mapping of the csv columns.
+//   - progress (Progress): A function that is called after each row is set. Can be nil.
+//
+// Returns:
+//   - error: If the mapping is invalid, a cell can't be parsed, a key already exists, or a full-text limit is reached.
+func (c *Cache) ImportCSV(r io.Reader, m CSVMapping, progress Progress) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.importCSV(r, m, progress)
+}
+
+// importCSV is a method of the Cache struct that reads a csv file and sets its rows in the cache.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - r (io.Reader): The reader to read the csv data from.
+//   - m (CSVMapping): The mapping of the csv columns.
+//   - progress (Progress): A function that is called after each row is set. Can be nil.
+//
+// Returns:
+//   - error: If the mapping is invalid, a cell can't be parsed, a key already exists, or a full-text limit is reached.
+func (c *Cache) importCSV(r io.Reader, m CSVMapping, progress Progress) error {
+	var l *loader = c.newLoader(c.ft, progress)
+	defer l.commit()
+	return readCSV(r, m, l.load)
+}
+
+// readCSV is a function that reads a csv file and calls a function with each of its rows, converted into a record.
+//
+// Parameters:
+//   - r (io.Reader): The reader to read the csv data from.
+//   - m (CSVMapping): The mapping of the csv columns.
+//   - fn (func(string, map[string]any) error): The function to call with the key and record of each row.
+//
+// Returns:
+//   - error: If the mapping is invalid, a cell can't be parsed, or fn returns an error.
+func readCSV(r io.Reader, m CSVMapping, fn func(key string, value map[string]any) error) error {
+	var reader *csv.Reader = csv.NewReader(r)
+	if m.Comma != 0 {
+		reader.Comma = m.Comma
+	}
+	if len(m.DateLayout) == 0 {
+		m.DateLayout = "2006-01-02"
+	}
+
+	// Read the header
+	var header, err = reader.Read()
+	if err == io.EOF {
+		return errors.New("csv file is empty")
+	} else if err != nil {
+		return err
+	}
+
+	// Verify the mapping against the header
+	var (
+		key      int             = -1
+		columns  map[string]bool = make(map[string]bool, len(header))
+		fullText map[string]bool = make(map[string]bool, len(m.FullText))
+	)
+	for i, column := range header {
+		if columns[column] {
+			return fmt.Errorf("duplicate column %s", column)
+		}
+		columns[column] = true
+		if column == m.Key {
+			key = i
+		}
+	}
+	if key < 0 {
+		return fmt.Errorf("key column %s not found", m.Key)
+	}
+	for _, column := range m.FullText {
+		if !columns[column] {
+			return fmt.Errorf("full-text column %s not found", column)
+		} else if column == m.Key {
+			return fmt.Errorf("key column %s can't be a full-text column", column)
+		}
+		fullText[column] = true
+	}
+	for column, t := range m.Types {
+		switch {
+		case !columns[column]:
+			return fmt.Errorf("typed column %s not found", column)
+		case !t.valid():
+			return fmt.Errorf("invalid type %s for column %s", t, column)
+		case fullText[column] && t != "" && t != ColumnString:
+			return fmt.Errorf("full-text column %s can't have the %s type", column, t)
+		}
+	}
+
+	// Read the rows
+	for row := 2; ; row++ {
+		var record []string
+		if record, err = reader.Read(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// Convert the cells
+		var value map[string]any = make(map[string]any, len(header)-1)
+		for i, column := range header {
+			if i == key {
+				continue
+			} else if fullText[column] && len(record[i]) == 0 {
+				value[column] = ""
+			} else if fullText[column] {
+				value[column] = map[string]any{
+					"$hermes.full_text": true,
+					"$hermes.value":     record[i],
+				}
+			} else if v, err := parseCell(record[i], m.Types[column], m.DateLayout); err != nil {
+				return fmt.Errorf("row %d, column %s: %w", row, column, err)
+			} else if v != nil {
+				value[column] = v
+			}
+		}
+
+		// Call the function with the record
+		if len(record[key]) == 0 {
+			return fmt.Errorf("row %d: empty key", row)
+		} else if err := fn(record[key], value); err != nil {
+			return fmt.Errorf("row %d: %w", row, err)
+		}
+	}
+}
+
+// valid is a method of the ColumnType type that checks whether the type is supported by the csv importer.
+//
+// Returns:
+//   - bool: Whether the type is supported. The empty type is the string type.
+func (t ColumnType) valid() bool {
+	switch t {
+	case "", ColumnString, ColumnInt, ColumnFloat, ColumnBool, ColumnDate:
+		return true
+	}
+	return false
+}
+
+// parseCell is a function that parses a csv cell into the go type of its column.
+// Empty cells of typed columns are returned as nil, so that they're not set in the record.
+//
+// Parameters:
+//   - cell (string): The cell value.
+//   - t (ColumnType): The type of the column. If empty, the cell is returned as a string.
+//   - dateLayout (string): The layout used to parse date cells.
+//
+// Returns:
+//   - any: The parsed value.
+//   - error: If the cell can't be parsed, or the type is invalid.
+func parseCell(cell string, t ColumnType, dateLayout string) (any, error) {
+	if t == "" || t == ColumnString {
+		return cell, nil
+	}
+
+	// Empty typed cells are skipped
+	if cell = strings.TrimSpace(cell); len(cell) == 0 {
+		return nil, nil
+	}
+
+	// Parse the cell
+	switch t {
+	case ColumnInt:
+		return strconv.Atoi(cell)
+	case ColumnFloat:
+		return strconv.ParseFloat(cell, 64)
+	case ColumnBool:
+		return strconv.ParseBool(cell)
+	case ColumnDate:
+		return time.Parse(dateLayout, cell)
+	}
+	return nil, fmt.Errorf("invalid column type %s", t)
+}
@@ -25,6 +25,10 @@ func (c *Cache) Delete(key string) {
 // Returns:
 //   - None
 func (c *Cache) delete(key string) {
+	if _, ok := c.data[key]; !ok {
+		return
+	}
+
 	// Delete the key from the FT cache
 	if c.ft != nil {
 		c.ft.delete(key)
@@ -32,6 +36,9 @@ func (c *Cache) delete(key string) {
 
 	// Delete the key from the cache
 	delete(c.data, key)
+	delete(c.fields, key)
+	c.queries.unhit(key)
+	c.events.publish(EventDelete, key, nil, nil, nil)
 }
 
 // delete is a method of the FullText struct that removes a key from the full-text storage.
@@ -45,30 +52,29 @@ func (c *Cache) delete(key string) {
 func (ft *FullText) delete(key string) {
 	// Remove the key from the ft.storage
 	for word, data := range ft.storage {
-		// Check if the data is []int or int
-		if _, ok := data.(int); ok {
-			delete(ft.storage, word)
-			continue
-		}
-
-		// If the data is []int, loop through the slice
-		if keys, ok := data.([]int); !ok {
-			for i := 0; i < len(keys); i++ {
-				if key != ft.indices[keys[i]] {
-					continue
+		switch v := data.(type) {
+		case int:
+			if ft.indices[v] == key {
+				delete(ft.storage, word)
+			}
+		case []int:
+			var keys []int = make([]int, 0, len(v))
+			for _, index := range v {
+				if ft.indices[index] != key {
+					keys = append(keys, index)
 				}
-
-				// Remove the key from the ft.storage slice
-				keys = append(keys[:i], keys[i+1:]...)
-				ft.storage[word] = keys
-				break
 			}
 
 			// If keys is empty, remove it from the storage
-			if len(keys) == 0 {
+			switch len(keys) {
+			case len(v):
+				continue
+			case 0:
 				delete(ft.storage, word)
-			} else if len(keys) == 1 {
+			case 1:
 				ft.storage[word] = keys[0]
+			default:
+				ft.storage[word] = keys
 			}
 		}
 	}
@@ -0,0 +1,120 @@
+package disk
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Get is a method of the Index struct that retrieves the document stored under the given key.
+// The document is decoded from the document store when it is requested.
+// This method is thread-safe.
+//
+// Parameters:
+//   - key (string): The key of the document to retrieve.
+//
+// Returns:
+//   - map[string]any: The document, or nil if the key doesn't exist.
+//   - error: An error if the index is closed or the document could not be decoded.
+func (idx *Index) Get(key string) (map[string]any, error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	// Check if the index is closed
+	if idx.docs == nil {
+		return nil, errors.New("index is closed")
+	}
+
+	// Binary search the document keys
+	var err error
+	var i int = sort.Search(idx.docCount, func(i int) bool {
+		var k string
+		if k, _, err = idx.document(i); err != nil {
+			return true
+		}
+		return k >= key
+	})
+	if err != nil {
+		return nil, err
+	} else if i >= idx.docCount {
+		return nil, nil
+	}
+
+	// Verify that the key matches
+	if k, doc, err := idx.document(i); err != nil {
+		return nil, err
+	} else if k != key {
+		return nil, nil
+	} else {
+		return decode(doc)
+	}
+}
+
+// document is a method of the Index struct that returns the key and the encoded json of a document.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - id (int): The id of the document.
+//
+// Returns:
+//   - string: The key of the document.
+//   - []byte: The json encoded document.
+//   - error: An error if the document entry is invalid.
+func (idx *Index) document(id int) (string, []byte, error) {
+	var b, err = entry(idx.docs, id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Read the key
+	if len(b) < 2 {
+		return "", nil, fmt.Errorf("document %d is truncated", id)
+	}
+	var keyLen int = int(binary.LittleEndian.Uint16(b))
+	if len(b) < 6+keyLen {
+		return "", nil, fmt.Errorf("document %d is truncated", id)
+	}
+	var key string = string(b[2 : 2+keyLen])
+
+	// Read the json value
+	var docLen int = int(binary.LittleEndian.Uint32(b[2+keyLen:]))
+	if len(b) < 6+keyLen+docLen {
+		return "", nil, fmt.Errorf("document %d is truncated", id)
+	}
+	return key, b[6+keyLen : 6+keyLen+docLen], nil
+}
+
+// record is a method of the Index struct that decodes a document.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - id (int): The id of the document.
+//
+// Returns:
+//   - map[string]any: The decoded document.
+//   - error: An error if the document could not be read or decoded.
+func (idx *Index) record(id int) (map[string]any, error) {
+	if _, doc, err := idx.document(id); err != nil {
+		return nil, err
+	} else {
+		return decode(doc)
+	}
+}
+
+// decode is a function that decodes a json encoded document.
+//
+// Parameters:
+//   - doc ([]byte): The json encoded document.
+//
+// Returns:
+//   - map[string]any: The decoded document.
+//   - error: An error if the json is invalid.
+func decode(doc []byte) (map[string]any, error) {
+	var v map[string]any
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,200 @@
+package disk
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// The names of the files that make up an on-disk index directory.
+const (
+	TermsFile     string = "terms.hrm"
+	PostingsFile  string = "postings.hrm"
+	DocumentsFile string = "docs.hrm"
+)
+
+// The magic bytes that are written at the start of each index file.
+var (
+	termsMagic     []byte = []byte("HRMT")
+	postingsMagic  []byte = []byte("HRMP")
+	documentsMagic []byte = []byte("HRMD")
+)
+
+// The size of the header of the terms and documents files (magic + count).
+const headerSize int = 8
+
+// Index is a struct that represents a read-only, memory-mapped full-text index stored on disk.
+// The index is made up of three files:
+//   - terms.hrm: A sorted term dictionary. Each term points to a range of document ids in the postings file.
+//   - postings.hrm: The document ids for every term, stored as little-endian uint32 values.
+//   - docs.hrm: The document store. Each document is stored as json and decoded lazily when it's returned.
+//
+// Fields:
+//   - mutex (*sync.RWMutex): A RWMutex that guards access to the mapped files.
+//   - terms ([]byte): The memory-mapped term dictionary.
+//   - postings ([]byte): The memory-mapped postings file.
+//   - docs ([]byte): The memory-mapped document store.
+//   - termCount (int): The number of terms in the term dictionary.
+//   - docCount (int): The number of documents in the document store.
+type Index struct {
+	mutex     *sync.RWMutex
+	terms     []byte
+	postings  []byte
+	docs      []byte
+	termCount int
+	docCount  int
+}
+
+// Open is a function that opens an on-disk index that was created with Write.
+// The index files are memory-mapped, so only the pages that are read during a search are loaded into memory.
+//
+// Parameters:
+//   - dir (string): The directory that contains the index files.
+//
+// Returns:
+//   - *Index: A pointer to the opened index.
+//   - error: An error if one of the files could not be opened, mapped or is invalid.
+func Open(dir string) (*Index, error) {
+	var (
+		idx *Index = &Index{mutex: &sync.RWMutex{}}
+		err error
+	)
+
+	// Map the index files
+	if idx.terms, err = mapFile(filepath.Join(dir, TermsFile), termsMagic); err != nil {
+		return nil, err
+	}
+	if idx.postings, err = mapFile(filepath.Join(dir, PostingsFile), postingsMagic); err != nil {
+		idx.close()
+		return nil, err
+	}
+	if idx.docs, err = mapFile(filepath.Join(dir, DocumentsFile), documentsMagic); err != nil {
+		idx.close()
+		return nil, err
+	}
+
+	// Read the term and document counts
+	if idx.termCount, err = tableCount(idx.terms); err != nil {
+		idx.close()
+		return nil, fmt.Errorf("%s: %w", TermsFile, err)
+	}
+	if idx.docCount, err = tableCount(idx.docs); err != nil {
+		idx.close()
+		return nil, fmt.Errorf("%s: %w", DocumentsFile, err)
+	}
+
+	// Return the index
+	return idx, nil
+}
+
+// Close is a method of the Index struct that unmaps the index files.
+// The index can't be used after it has been closed.
+// This method is thread-safe.
+//
+// Returns:
+//   - error: An error if one of the files could not be unmapped.
+func (idx *Index) Close() error {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	return idx.close()
+}
+
+// close is a method of the Index struct that unmaps the index files.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Returns:
+//   - error: The first error that occurred while unmapping the files.
+func (idx *Index) close() error {
+	var result error
+	for _, b := range [][]byte{idx.terms, idx.postings, idx.docs} {
+		if err := munmap(b); err != nil && result == nil {
+			result = err
+		}
+	}
+	idx.terms, idx.postings, idx.docs = nil, nil, nil
+	idx.termCount, idx.docCount = 0, 0
+	return result
+}
+
+// Length is a method of the Index struct that returns the number of documents in the index.
+// This method is thread-safe.
+//
+// Returns:
+//   - int: The number of documents in the index.
+func (idx *Index) Length() int {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	return idx.docCount
+}
+
+// mapFile is a function that memory-maps a file and verifies its magic bytes.
+//
+// Parameters:
+//   - path (string): The path to the file to map.
+//   - magic ([]byte): The magic bytes that the file is expected to start with.
+//
+// Returns:
+//   - []byte: The mapped file contents.
+//   - error: An error if the file could not be opened or mapped, or if the magic bytes don't match.
+func mapFile(path string, magic []byte) ([]byte, error) {
+	var f, err = os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Map the file
+	var b []byte
+	if b, err = mmap(f); err != nil {
+		return nil, err
+	}
+
+	// Verify the magic bytes
+	if len(b) < len(magic) || string(b[:len(magic)]) != string(magic) {
+		_ = munmap(b)
+		return nil, fmt.Errorf("%s is not a hermes index file", path)
+	}
+	return b, nil
+}
+
+// tableCount is a function that reads and validates the entry count of a terms or documents file.
+//
+// Parameters:
+//   - b ([]byte): The mapped file contents.
+//
+// Returns:
+//   - int: The number of entries in the file.
+//   - error: An error if the offset table doesn't fit in the file.
+func tableCount(b []byte) (int, error) {
+	if len(b) < headerSize {
+		return 0, errors.New("truncated header")
+	}
+	var count int = int(binary.LittleEndian.Uint32(b[4:8]))
+	if headerSize+count*8 > len(b) {
+		return 0, errors.New("truncated offset table")
+	}
+	return count, nil
+}
+
+// entry is a function that returns the bytes of the i'th entry of a terms or documents file.
+//
+// Parameters:
+//   - b ([]byte): The mapped file contents.
+//   - i (int): The index of the entry.
+//
+// Returns:
+//   - []byte: The entry bytes, starting at the entry and ending at the end of the file.
+//   - error: An error if the entry offset is out of range.
+func entry(b []byte, i int) ([]byte, error) {
+	var (
+		pos    int    = headerSize + i*8
+		offset uint64 = binary.LittleEndian.Uint64(b[pos : pos+8])
+	)
+	if offset >= uint64(len(b)) {
+		return nil, fmt.Errorf("entry %d is out of range", i)
+	}
+	return b[offset:], nil
+}
@@ -0,0 +1,357 @@
+package disk
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// chunkSize is the number of bytes of entries that are sorted in memory before they're
+// written to a temporary file, so that the memory used to build an index doesn't grow with the data.
+const chunkSize int = 32 << 20
+
+// item is a struct that represents an entry of a sorted run.
+//
+// Fields:
+//   - key (string): The key that the entries are sorted by.
+//   - data ([]byte): The value of the entry.
+type item struct {
+	key  string
+	data []byte
+}
+
+// runs is a struct that sorts entries by key with an external merge sort.
+// The entries are sorted in chunks that are written to temporary files, and the files are merged when they're read.
+// The entries with the same key are read in the order they were added.
+//
+// Fields:
+//   - dir (string): The directory of the temporary files.
+//   - items ([]item): The entries of the current chunk.
+//   - size (int): The approximate size of the current chunk, in bytes.
+//   - files ([]*os.File): The temporary files of the sorted chunks, in the order they were written.
+type runs struct {
+	dir   string
+	items []item
+	size  int
+	files []*os.File
+}
+
+// add is a method of the runs struct that adds an entry, and writes the chunk to a temporary file once it's full.
+//
+// Parameters:
+//   - key (string): The key of the entry. It must be at most 65535 bytes long.
+//   - data ([]byte): The value of the entry.
+//
+// Returns:
+//   - error: An error if the chunk could not be written.
+func (r *runs) add(key string, data []byte) error {
+	r.items = append(r.items, item{key: key, data: data})
+	if r.size += len(key) + len(data) + 64; r.size >= chunkSize {
+		return r.spill()
+	}
+	return nil
+}
+
+// spill is a method of the runs struct that sorts the current chunk and writes it to a temporary file.
+//
+// Returns:
+//   - error: An error if the file could not be written.
+func (r *runs) spill() error {
+	if len(r.items) == 0 {
+		return nil
+	}
+	sort.SliceStable(r.items, func(i, j int) bool {
+		return r.items[i].key < r.items[j].key
+	})
+
+	// Write the entries
+	var f, err = os.CreateTemp(r.dir, ".run-*.tmp")
+	if err != nil {
+		return err
+	}
+	r.files = append(r.files, f)
+	var w *bufio.Writer = bufio.NewWriter(f)
+	for _, it := range r.items {
+		var b []byte = make([]byte, 0, 6+len(it.key))
+		b = binary.LittleEndian.AppendUint16(b, uint16(len(it.key)))
+		b = append(b, it.key...)
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(it.data)))
+		if _, err := w.Write(b); err != nil {
+			return err
+		} else if _, err := w.Write(it.data); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	// Reset the chunk
+	r.items, r.size = nil, 0
+	_, err = f.Seek(0, io.SeekStart)
+	return err
+}
+
+// merge is a method of the runs struct that reads the entries in the order of their keys.
+//
+// Parameters:
+//   - fn (func(key string, data []byte) error): The function called with each entry. The merge stops at its first error.
+//
+// Returns:
+//   - error: An error if a temporary file could not be written or read, or the error of fn.
+func (r *runs) merge(fn func(key string, data []byte) error) error {
+	if err := r.spill(); err != nil {
+		return err
+	}
+
+	// Read the first entry of every file
+	var h cursors = make(cursors, 0, len(r.files))
+	for i, f := range r.files {
+		var c *cursor = &cursor{r: bufio.NewReader(f), run: i}
+		if ok, err := c.next(); err != nil {
+			return err
+		} else if ok {
+			h = append(h, c)
+		}
+	}
+	heap.Init(&h)
+
+	// Read the smallest entry, until every file is read
+	for len(h) > 0 {
+		var c *cursor = h[0]
+		if err := fn(c.item.key, c.item.data); err != nil {
+			return err
+		}
+		if ok, err := c.next(); err != nil {
+			return err
+		} else if ok {
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+	return nil
+}
+
+// remove is a method of the runs struct that closes and removes the temporary files.
+func (r *runs) remove() {
+	for _, f := range r.files {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}
+	r.items, r.files = nil, nil
+}
+
+// cursor is a struct that reads the entries of a sorted run.
+//
+// Fields:
+//   - r (*bufio.Reader): The reader of the temporary file.
+//   - run (int): The position of the run, so that the entries with the same key are read in the order they were added.
+//   - item (item): The current entry.
+type cursor struct {
+	r    *bufio.Reader
+	run  int
+	item item
+}
+
+// next is a method of the cursor struct that reads the next entry of the run.
+//
+// Returns:
+//   - bool: Whether an entry was read. False at the end of the run.
+//   - error: An error if the file could not be read, or is truncated.
+func (c *cursor) next() (bool, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(c.r, size[:2]); err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	var key []byte = make([]byte, binary.LittleEndian.Uint16(size[:2]))
+	if _, err := io.ReadFull(c.r, key); err != nil {
+		return false, truncated(err)
+	} else if _, err := io.ReadFull(c.r, size[:]); err != nil {
+		return false, truncated(err)
+	}
+	var data []byte = make([]byte, binary.LittleEndian.Uint32(size[:]))
+	if _, err := io.ReadFull(c.r, data); err != nil {
+		return false, truncated(err)
+	}
+	c.item = item{key: string(key), data: data}
+	return true, nil
+}
+
+// truncated is a function that reports the end of a temporary file in the middle of an entry.
+//
+// Parameters:
+//   - err (error): The read error.
+//
+// Returns:
+//   - error: The read error, or an error for the truncated file.
+func truncated(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errors.New("truncated temporary file")
+	}
+	return err
+}
+
+// cursors is a heap of the cursors of the runs, ordered by their current key and then by their position.
+type cursors []*cursor
+
+func (h cursors) Len() int { return len(h) }
+func (h cursors) Less(i, j int) bool {
+	if h[i].item.key != h[j].item.key {
+		return h[i].item.key < h[j].item.key
+	}
+	return h[i].run < h[j].run
+}
+func (h cursors) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *cursors) Push(x any)   { *h = append(*h, x.(*cursor)) }
+func (h *cursors) Pop() any {
+	var old cursors = *h
+	var c *cursor = old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// table is a struct that writes a terms or documents file whose entries are added one by one.
+// The entries and their offsets are written to temporary files, and copied after the header once the count is known.
+//
+// Fields:
+//   - path (string): The path to the file to write.
+//   - magic ([]byte): The magic bytes to start the file with.
+//   - offsets (*os.File): The temporary file of the offsets of the entries, relative to the first entry.
+//   - entries (*os.File): The temporary file of the entries.
+//   - ow (*bufio.Writer): The writer of the offsets.
+//   - ew (*bufio.Writer): The writer of the entries.
+//   - count (int): The number of entries.
+//   - size (uint64): The size of the entries, in bytes.
+type table struct {
+	path    string
+	magic   []byte
+	offsets *os.File
+	entries *os.File
+	ow      *bufio.Writer
+	ew      *bufio.Writer
+	count   int
+	size    uint64
+}
+
+// newTable is a function that creates the writer of a terms or documents file.
+//
+// Parameters:
+//   - path (string): The path to the file to write.
+//   - magic ([]byte): The magic bytes to start the file with.
+//
+// Returns:
+//   - *table: The writer. Its remove method must be called once it's done.
+//   - error: An error if a temporary file could not be created.
+func newTable(path string, magic []byte) (*table, error) {
+	var t *table = &table{path: filepath.Clean(path), magic: magic}
+	var err error
+	if t.offsets, err = os.CreateTemp(filepath.Dir(t.path), ".offsets-*.tmp"); err != nil {
+		return nil, err
+	} else if t.entries, err = os.CreateTemp(filepath.Dir(t.path), ".entries-*.tmp"); err != nil {
+		t.remove()
+		return nil, err
+	}
+	t.ow, t.ew = bufio.NewWriter(t.offsets), bufio.NewWriter(t.entries)
+	return t, nil
+}
+
+// add is a method of the table struct that appends an entry, made up of the given parts.
+//
+// Parameters:
+//   - parts (...[]byte): The parts of the entry.
+//
+// Returns:
+//   - error: An error if the file has too many entries, or a temporary file could not be written.
+func (t *table) add(parts ...[]byte) error {
+	if t.count == math.MaxUint32 {
+		return errors.New("too many entries")
+	}
+	var offset [8]byte
+	binary.LittleEndian.PutUint64(offset[:], t.size)
+	if _, err := t.ow.Write(offset[:]); err != nil {
+		return err
+	}
+	for _, p := range parts {
+		if _, err := t.ew.Write(p); err != nil {
+			return err
+		}
+		t.size += uint64(len(p))
+	}
+	t.count++
+	return nil
+}
+
+// write is a method of the table struct that writes the file: the header, the offset table and the entries.
+//
+// Returns:
+//   - error: An error if the file could not be written.
+func (t *table) write() error {
+	if err := t.ow.Flush(); err != nil {
+		return err
+	} else if err := t.ew.Flush(); err != nil {
+		return err
+	} else if _, err := t.offsets.Seek(0, io.SeekStart); err != nil {
+		return err
+	} else if _, err := t.entries.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	var f, err = os.Create(t.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Write the header
+	var (
+		w      *bufio.Writer = bufio.NewWriter(f)
+		header []byte        = append([]byte{}, t.magic...)
+		base   uint64        = uint64(headerSize + t.count*8)
+	)
+	header = binary.LittleEndian.AppendUint32(header, uint32(t.count))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	// Write the offset table, relative to the start of the file
+	var (
+		r      *bufio.Reader = bufio.NewReader(t.offsets)
+		offset [8]byte
+	)
+	for i := 0; i < t.count; i++ {
+		if _, err := io.ReadFull(r, offset[:]); err != nil {
+			return truncated(err)
+		}
+		binary.LittleEndian.PutUint64(offset[:], base+binary.LittleEndian.Uint64(offset[:]))
+		if _, err := w.Write(offset[:]); err != nil {
+			return err
+		}
+	}
+
+	// Write the entries
+	if _, err := io.Copy(w, t.entries); err != nil {
+		return err
+	} else if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// remove is a method of the table struct that closes and removes the temporary files.
+func (t *table) remove() {
+	for _, f := range []*os.File{t.offsets, t.entries} {
+		if f != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}
+}
@@ -0,0 +1,32 @@
+//go:build !unix
+
+package disk
+
+import (
+	"io"
+	"os"
+)
+
+// mmap is a function that reads a file into memory.
+// Memory-mapping is only supported on unix systems, so the file is read instead.
+//
+// Parameters:
+//   - f (*os.File): The file to read.
+//
+// Returns:
+//   - []byte: The file contents.
+//   - error: An error if the file could not be read.
+func mmap(f *os.File) ([]byte, error) {
+	return io.ReadAll(f)
+}
+
+// munmap is a function that releases a file that was read with mmap.
+//
+// Parameters:
+//   - b ([]byte): The file contents.
+//
+// Returns:
+//   - error: Always nil.
+func munmap(_ []byte) error {
+	return nil
+}
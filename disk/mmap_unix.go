@@ -0,0 +1,40 @@
+//go:build unix
+
+package disk
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmap is a function that maps a file into memory as read-only.
+//
+// Parameters:
+//   - f (*os.File): The file to map.
+//
+// Returns:
+//   - []byte: The mapped file contents.
+//   - error: An error if the file could not be mapped.
+func mmap(f *os.File) ([]byte, error) {
+	if info, err := f.Stat(); err != nil {
+		return nil, err
+	} else if info.Size() == 0 {
+		return []byte{}, nil
+	} else {
+		return syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	}
+}
+
+// munmap is a function that unmaps a file that was mapped with mmap.
+//
+// Parameters:
+//   - b ([]byte): The mapped file contents.
+//
+// Returns:
+//   - error: An error if the file could not be unmapped.
+func munmap(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return syscall.Munmap(b)
+}
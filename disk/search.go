@@ -0,0 +1,350 @@
+package disk
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	hermes "github.com/realTristan/hermes"
+	utils "github.com/realTristan/hermes/utils"
+)
+
+// Search is a method of the Index struct that searches for a query by splitting the query into separate words and returning the search results.
+// This method accepts the same SearchParams as hermes.Cache.Search.
+// This method is thread-safe.
+//
+// Parameters:
+//   - sp (hermes.SearchParams): A SearchParams struct containing the search parameters.
+//
+// Returns:
+//   - []map[string]any: A slice of maps containing the search results.
+//   - error: An error if the query is invalid, the index is closed or a document could not be decoded.
+func (idx *Index) Search(sp hermes.SearchParams) ([]map[string]any, error) {
+	// If the query is empty, return an error
+	if len(sp.Query) == 0 {
+		return []map[string]any{}, errors.New("invalid query")
+	}
+
+	// If no limit is provided, set it to 10
+	if sp.Limit == 0 {
+		sp.Limit = 10
+	}
+
+	// Lock the mutex
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	// Check if the index is closed
+	if idx.terms == nil {
+		return []map[string]any{}, errors.New("index is closed")
+	}
+
+	// Set the query to lowercase
+	sp.Query = strings.ToLower(sp.Query)
+
+	// Search for the query
+	return idx.search(sp)
+}
+
+// SearchOneWord is a method of the Index struct that searches for a single word in the term dictionary.
+// This method accepts the same SearchParams as hermes.Cache.SearchOneWord.
+// This method is thread-safe.
+//
+// Parameters:
+//   - sp (hermes.SearchParams): A SearchParams struct containing the search parameters.
+//
+// Returns:
+//   - []map[string]any: A slice of maps containing the search results.
+//   - error: An error if the query is invalid, the index is closed or a document could not be decoded.
+func (idx *Index) SearchOneWord(sp hermes.SearchParams) ([]map[string]any, error) {
+	// If the query is empty, return an error
+	if len(sp.Query) == 0 {
+		return []map[string]any{}, errors.New("invalid query")
+	}
+
+	// If no limit is provided, set it to 10
+	if sp.Limit == 0 {
+		sp.Limit = 10
+	}
+
+	// Lock the mutex
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	// Check if the index is closed
+	if idx.terms == nil {
+		return []map[string]any{}, errors.New("index is closed")
+	}
+
+	// Search the index
+	sp.Query = strings.ToLower(sp.Query)
+	return idx.searchOneWord(sp)
+}
+
+// search is a method of the Index struct that searches for a query by splitting the query into separate words.
+// Like hermes.Cache.Search, the first word of the query has to be in the index, and the last word can be incomplete.
+// The documents of the first or a middle word (whichever has the fewest) are loaded and checked for the full query.
+// If the search isn't strict, the words match every term that contains them.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - sp (hermes.SearchParams): A SearchParams struct containing the search parameters.
+//
+// Returns:
+//   - []map[string]any: A slice of maps containing the search results.
+//   - error: An error if a term or document could not be read.
+func (idx *Index) search(sp hermes.SearchParams) ([]map[string]any, error) {
+	// Split the query into separate words
+	var words []string = strings.Fields(sp.Query)
+	switch {
+	case len(words) == 0:
+		return []map[string]any{}, nil
+	case len(words) == 1:
+		sp.Query = words[0]
+		return idx.searchOneWord(sp)
+	}
+
+	// Find the documents of the first word
+	var smallest, err = idx.documentsOf(words[0], sp.Strict)
+	if err != nil {
+		return nil, err
+	} else if len(smallest) == 0 {
+		return []map[string]any{}, nil
+	}
+
+	// Find the smallest documents list. Don't include the last word from the query
+	for _, word := range words[1 : len(words)-1] {
+		if ids, err := idx.documentsOf(word, sp.Strict); err != nil {
+			return nil, err
+		} else if len(ids) > 0 && len(ids) < len(smallest) {
+			smallest = ids
+		}
+	}
+
+	// Load the documents and check whether they contain the query
+	var result []map[string]any = []map[string]any{}
+	for _, id := range smallest {
+		if len(result) >= sp.Limit {
+			break
+		}
+		if record, err := idx.record(int(id)); err != nil {
+			return nil, err
+		} else if contains(record, sp.Query, sp.Schema) {
+			result = append(result, record)
+		}
+	}
+
+	// Return the result
+	return result, nil
+}
+
+// searchOneWord is a method of the Index struct that searches for a single word in the term dictionary.
+// If the search is strict, only the exact term is matched. Otherwise every term that contains the query is matched.
+// If a schema is provided, only the documents whose schema fields contain the query are returned.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - sp (hermes.SearchParams): A SearchParams struct containing the search parameters.
+//
+// Returns:
+//   - []map[string]any: A slice of maps containing the search results.
+//   - error: An error if a term or document could not be read.
+func (idx *Index) searchOneWord(sp hermes.SearchParams) ([]map[string]any, error) {
+	var (
+		result       []map[string]any = []map[string]any{}
+		alreadyAdded map[uint32]bool  = map[uint32]bool{}
+	)
+
+	// Add the documents of a term to the result
+	var add = func(i int) error {
+		var ids, err = idx.postingsOf(i)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if len(result) >= sp.Limit {
+				return nil
+			} else if alreadyAdded[id] {
+				continue
+			}
+			if record, err := idx.record(int(id)); err != nil {
+				return err
+			} else if len(sp.Schema) == 0 || contains(record, sp.Query, sp.Schema) {
+				result = append(result, record)
+				alreadyAdded[id] = true
+			}
+		}
+		return nil
+	}
+
+	// If the user wants a strict search, only use the exact term
+	if sp.Strict {
+		if i, ok := idx.findTerm(sp.Query); ok {
+			if err := add(i); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+
+	// Loop through the term dictionary
+	for i := 0; i < idx.termCount && len(result) < sp.Limit; i++ {
+		if term, err := idx.term(i); err != nil {
+			return nil, err
+		} else if !utils.Contains(term, sp.Query) {
+			continue
+		} else if err := add(i); err != nil {
+			return nil, err
+		}
+	}
+
+	// Return the result
+	return result, nil
+}
+
+// documentsOf is a method of the Index struct that returns the ids of the documents that contain a word.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - word (string): The word to find.
+//   - strict (bool): Whether only the exact term is matched. Otherwise every term that contains the word is matched.
+//
+// Returns:
+//   - []uint32: The ids of the documents, in ascending order. Empty if no term matches.
+//   - error: An error if a term or its postings could not be read.
+func (idx *Index) documentsOf(word string, strict bool) ([]uint32, error) {
+	if strict {
+		if i, ok := idx.findTerm(word); ok {
+			return idx.postingsOf(i)
+		}
+		return []uint32{}, nil
+	}
+
+	// Loop through the term dictionary, and merge the postings of the matching terms
+	var seen map[uint32]bool = map[uint32]bool{}
+	for i := 0; i < idx.termCount; i++ {
+		if term, err := idx.term(i); err != nil {
+			return nil, err
+		} else if !utils.Contains(term, word) {
+			continue
+		} else if ids, err := idx.postingsOf(i); err != nil {
+			return nil, err
+		} else {
+			for _, id := range ids {
+				seen[id] = true
+			}
+		}
+	}
+	var ids []uint32 = make([]uint32, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// contains is a function that checks whether a string value of a document contains a query.
+//
+// Parameters:
+//   - record (map[string]any): The decoded document.
+//   - query (string): The lowercase query.
+//   - schema (map[string]bool): The fields to check. If it's empty, every field is checked.
+//
+// Returns:
+//   - bool: Whether a value contains the query.
+func contains(record map[string]any, query string, schema map[string]bool) bool {
+	for key, value := range record {
+		if len(schema) > 0 && !schema[key] {
+			continue
+		} else if v, ok := value.(string); ok && strings.Contains(strings.ToLower(v), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// findTerm is a method of the Index struct that binary searches the term dictionary for a word.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - word (string): The word to find.
+//
+// Returns:
+//   - int: The index of the term in the term dictionary.
+//   - bool: Whether the term was found.
+func (idx *Index) findTerm(word string) (int, bool) {
+	var i int = sort.Search(idx.termCount, func(i int) bool {
+		var term, err = idx.term(i)
+		return err != nil || term >= word
+	})
+	if i >= idx.termCount {
+		return i, false
+	}
+	var term, err = idx.term(i)
+	return i, err == nil && term == word
+}
+
+// term is a method of the Index struct that returns the word of the i'th term in the term dictionary.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - i (int): The index of the term.
+//
+// Returns:
+//   - string: The term.
+//   - error: An error if the term entry is invalid.
+func (idx *Index) term(i int) (string, error) {
+	var b, err = entry(idx.terms, i)
+	if err != nil {
+		return "", err
+	}
+	if len(b) < 2 {
+		return "", fmt.Errorf("term %d is truncated", i)
+	}
+	var termLen int = int(binary.LittleEndian.Uint16(b))
+	if len(b) < 14+termLen {
+		return "", fmt.Errorf("term %d is truncated", i)
+	}
+	return string(b[2 : 2+termLen]), nil
+}
+
+// postingsOf is a method of the Index struct that returns the document ids of the i'th term in the term dictionary.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - i (int): The index of the term.
+//
+// Returns:
+//   - []uint32: The ids of the documents that contain the term.
+//   - error: An error if the term entry or postings are invalid.
+func (idx *Index) postingsOf(i int) ([]uint32, error) {
+	var b, err = entry(idx.terms, i)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 2 {
+		return nil, fmt.Errorf("term %d is truncated", i)
+	}
+	var termLen int = int(binary.LittleEndian.Uint16(b))
+	if len(b) < 14+termLen {
+		return nil, fmt.Errorf("term %d is truncated", i)
+	}
+
+	// Read the postings range
+	var (
+		offset uint64 = binary.LittleEndian.Uint64(b[2+termLen:])
+		count  uint64 = uint64(binary.LittleEndian.Uint32(b[10+termLen:]))
+	)
+	if offset+count*4 > uint64(len(idx.postings)) {
+		return nil, fmt.Errorf("postings of term %d are out of range", i)
+	}
+
+	// Read the document ids
+	var ids []uint32 = make([]uint32, count)
+	for j := uint64(0); j < count; j++ {
+		ids[j] = binary.LittleEndian.Uint32(idx.postings[offset+j*4:])
+	}
+	return ids, nil
+}
@@ -0,0 +1,331 @@
+package disk
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	hermes "github.com/realTristan/hermes"
+	utils "github.com/realTristan/hermes/utils"
+)
+
+// Write is a function that builds an on-disk index from a map of records and writes it to a directory.
+// The records use the same format as hermes.Cache.FTInitWithMap: full-text values are wrapped with
+// cache.WithFT() or stored as {"$hermes.full_text": true, "$hermes.value": "..."} maps.
+// The full-text values are stored as plain strings in the document store.
+//
+// Parameters:
+//   - dir (string): The directory to write the index files to. It is created if it doesn't exist.
+//   - data (map[string]map[string]any): The records to index.
+//   - minWordLength (int): The minimum length of a word that is stored in the term dictionary.
+//
+// Returns:
+//   - error: An error if a key is too long or if one of the files could not be written.
+func Write(dir string, data map[string]map[string]any, minWordLength int) error {
+	var b, err = newBuilder(dir, minWordLength)
+	if err != nil {
+		return err
+	}
+	defer b.remove()
+	for key, record := range data {
+		if err := b.add(key, record); err != nil {
+			return err
+		}
+	}
+	return b.write()
+}
+
+// WriteJson is a function that builds an on-disk index from a json file and writes it to a directory.
+// The json file uses the same format as hermes.Cache.FTInitWithJson. The records are read one by one,
+// and sorted in chunks on disk, so the file can be larger than the memory.
+//
+// Parameters:
+//   - file (string): The path to the json file to index.
+//   - dir (string): The directory to write the index files to.
+//   - minWordLength (int): The minimum length of a word that is stored in the term dictionary.
+//
+// Returns:
+//   - error: An error if the json file could not be read, or the index could not be written.
+func WriteJson(file string, dir string, minWordLength int) error {
+	var f, err = os.Open(filepath.Clean(file))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	b, err := newBuilder(dir, minWordLength)
+	if err != nil {
+		return err
+	}
+	defer b.remove()
+
+	// Read the records of the json object
+	var dec *json.Decoder = json.NewDecoder(bufio.NewReader(f))
+	if t, err := dec.Token(); err != nil {
+		return err
+	} else if t != json.Delim('{') {
+		return fmt.Errorf("%s is not a json object", file)
+	}
+	for dec.More() {
+		var t, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		var (
+			key    string = t.(string)
+			record map[string]any
+		)
+		if err := dec.Decode(&record); err != nil {
+			return fmt.Errorf("key %s: %w", key, err)
+		} else if err := b.add(key, record); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	return b.write()
+}
+
+// builder is a struct that builds an on-disk index from records that are added in any order.
+// The records are sorted by key, and the postings by term, with an external merge sort,
+// so that only a chunk of the records or postings is kept in memory.
+//
+// Fields:
+//   - dir (string): The directory to write the index files to.
+//   - minWordLength (int): The minimum length of a word that is stored in the term dictionary.
+//   - records (*runs): The encoded records with their words, sorted by key.
+//   - postings (*runs): The document ids of the terms, sorted by term.
+type builder struct {
+	dir           string
+	minWordLength int
+	records       *runs
+	postings      *runs
+}
+
+// newBuilder is a function that creates the builder of an index.
+//
+// Parameters:
+//   - dir (string): The directory to write the index files to. It is created if it doesn't exist.
+//   - minWordLength (int): The minimum length of a word that is stored in the term dictionary.
+//
+// Returns:
+//   - *builder: The builder. Its remove method must be called once it's done.
+//   - error: An error if the directory could not be created.
+func newBuilder(dir string, minWordLength int) (*builder, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &builder{
+		dir:           dir,
+		minWordLength: minWordLength,
+		records:       &runs{dir: dir},
+		postings:      &runs{dir: dir},
+	}, nil
+}
+
+// add is a method of the builder struct that flattens the full-text values of a record and adds it to the index.
+// If a key is added twice, the last record is kept.
+//
+// Parameters:
+//   - key (string): The key of the record.
+//   - record (map[string]any): The record.
+//
+// Returns:
+//   - error: An error if the key is too long, or the record could not be encoded.
+func (b *builder) add(key string, record map[string]any) error {
+	if len(key) > math.MaxUint16 {
+		return fmt.Errorf("key %s is too long", key[:32])
+	}
+
+	// Flatten the full-text values, and split them into words
+	var (
+		doc   map[string]any  = make(map[string]any, len(record))
+		words map[string]bool = make(map[string]bool)
+	)
+	for k, v := range record {
+		if ftv := hermes.WFTGetValue(v); len(ftv) > 0 {
+			for _, word := range split(ftv, b.minWordLength) {
+				words[word] = true
+			}
+			v = ftv
+		}
+		doc[k] = v
+	}
+
+	// Encode the record, followed by its words
+	var encoded, err = json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	var data []byte = binary.LittleEndian.AppendUint32(nil, uint32(len(encoded)))
+	data = append(data, encoded...)
+	for word := range words {
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(word)))
+		data = append(data, word...)
+	}
+	return b.records.add(key, data)
+}
+
+// write is a method of the builder struct that writes the index files.
+// The document id of a record is the position of its key, so that the document store can be binary searched by key.
+//
+// Returns:
+//   - error: An error if one of the files could not be written.
+func (b *builder) write() error {
+	var docs, err = newTable(filepath.Join(b.dir, DocumentsFile), documentsMagic)
+	if err != nil {
+		return err
+	}
+	defer docs.remove()
+
+	// Write the documents in the order of their keys, and keep the last record of a key
+	var (
+		id      uint32
+		pending *item
+	)
+	var flush = func() error {
+		if pending == nil {
+			return nil
+		}
+		var (
+			key  []byte = []byte(pending.key)
+			size uint32 = binary.LittleEndian.Uint32(pending.data)
+			doc  []byte = pending.data[4 : 4+size]
+		)
+		if err := docs.add(binary.LittleEndian.AppendUint16(nil, uint16(len(key))), key, pending.data[:4], doc); err != nil {
+			return err
+		}
+		for rest := pending.data[4+size:]; len(rest) > 0; {
+			var n int = int(binary.LittleEndian.Uint16(rest))
+			if err := b.postings.add(string(rest[2:2+n]), binary.LittleEndian.AppendUint32(nil, id)); err != nil {
+				return err
+			}
+			rest = rest[2+n:]
+		}
+		id++
+		return nil
+	}
+	err = b.records.merge(func(key string, data []byte) error {
+		if pending != nil && pending.key != key {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		pending = &item{key: key, data: data}
+		return nil
+	})
+	if err != nil {
+		return err
+	} else if err := flush(); err != nil {
+		return err
+	} else if err := docs.write(); err != nil {
+		return err
+	}
+	b.records.remove()
+	return b.writeTerms()
+}
+
+// writeTerms is a method of the builder struct that writes the term dictionary and the postings file.
+// The postings of a term are merged in the order of the document ids, since the ids were added in that order.
+//
+// Returns:
+//   - error: An error if one of the files could not be written.
+func (b *builder) writeTerms() error {
+	var terms, err = newTable(filepath.Join(b.dir, TermsFile), termsMagic)
+	if err != nil {
+		return err
+	}
+	defer terms.remove()
+	f, err := os.OpenFile(filepath.Join(b.dir, PostingsFile), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Write the postings file, and add the term entries
+	var (
+		w      *bufio.Writer = bufio.NewWriter(f)
+		term   string
+		count  uint32
+		offset uint64 = uint64(len(postingsMagic))
+	)
+	var flush = func() error {
+		if count == 0 {
+			return nil
+		}
+		var e []byte = make([]byte, 0, 2+len(term)+12)
+		e = binary.LittleEndian.AppendUint16(e, uint16(len(term)))
+		e = append(e, term...)
+		e = binary.LittleEndian.AppendUint64(e, offset)
+		e = binary.LittleEndian.AppendUint32(e, count)
+		offset += uint64(count) * 4
+		count = 0
+		return terms.add(e)
+	}
+	if _, err := w.Write(postingsMagic); err != nil {
+		return err
+	}
+	err = b.postings.merge(func(key string, data []byte) error {
+		if key != term {
+			if err := flush(); err != nil {
+				return err
+			}
+			term = key
+		}
+		count++
+		_, err := w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	} else if err := flush(); err != nil {
+		return err
+	} else if err := w.Flush(); err != nil {
+		return err
+	} else if err := f.Close(); err != nil {
+		return err
+	}
+	return terms.write()
+}
+
+// remove is a method of the builder struct that removes the temporary files.
+func (b *builder) remove() {
+	b.records.remove()
+	b.postings.remove()
+}
+
+// split is a function that splits a full-text value into the words that are stored in the term dictionary.
+// The value is cleaned the same way the hermes.Cache full-text index cleans it.
+//
+// Parameters:
+//   - value (string): The full-text value to split.
+//   - minWordLength (int): The minimum length of a word.
+//
+// Returns:
+//   - []string: The words in the value.
+func split(value string, minWordLength int) []string {
+	var result []string = []string{}
+
+	// Clean the string value
+	value = strings.TrimSpace(value)
+	value = utils.RemoveDoubleSpaces(value)
+	value = strings.ToLower(value)
+
+	// Loop through the words
+	for _, word := range strings.Split(value, " ") {
+		if len(word) == 0 || len(word) < minWordLength {
+			continue
+		}
+		for _, w := range utils.SplitByAlphaNum(utils.TrimNonAlphaNum(word)) {
+			if len(w) >= minWordLength && len(w) <= math.MaxUint16 {
+				result = append(result, w)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,227 @@
+package hermes
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ExportFormat is the type of the formats that the cache can be exported to.
+type ExportFormat string
+
+const (
+	// ExportHash exports the cache as a single json object that maps keys to records.
+	// This is the same format that is used by cache.FTInitWithJson().
+	ExportHash ExportFormat = "json"
+	// ExportNDJSON exports the cache as newline-delimited json, where each line is {"key": {...}}.
+	// This is the same format that is used by cache.LoadNDJSON().
+	ExportNDJSON ExportFormat = "ndjson"
+)
+
+// exportBatchSize is the number of records that are encoded each time the cache is read-locked during an export.
+const exportBatchSize int = 1000
+
+// ExportJSON is a method of the Cache struct that writes the cache contents to a writer.
+// The full-text fields are wrapped in {"$hermes.full_text": true, "$hermes.value": "..."} maps,
+// so that the output can be loaded back into a cache with cache.FTInitWithJson() or cache.LoadNDJSON().
+// The fields that aren't stored are exported with "$hermes.stored": false, so that they're indexed again.
+// The records are written in key order. They're encoded in batches, and the cache is only read-locked while
+// a batch is encoded, so a slow writer doesn't block the cache updates. The keys that are deleted during the
+// export are skipped, and the keys that are set during the export aren't written.
+// This method is thread-safe.
+//
+// Parameters:
+//   - w (io.Writer): The writer to write the records to.
+//   - format (ExportFormat): The format to write the records in.
+//
+// Returns:
+//   - error: If the format is invalid, a record can't be encoded, or the writer returns an error.
+func (c *Cache) ExportJSON(w io.Writer, format ExportFormat) error {
+	if format != ExportHash && format != ExportNDJSON {
+		return fmt.Errorf("invalid export format %s", format)
+	}
+
+	// Sort the keys
+	c.mutex.RLock()
+	var keys []string = c.keys()
+	c.mutex.RUnlock()
+	sort.Strings(keys)
+
+	// Write the records
+	var (
+		bw      *bufio.Writer = bufio.NewWriter(w)
+		written int           = 0
+	)
+	if format == ExportHash {
+		if _, err := bw.WriteString("{"); err != nil {
+			return err
+		}
+	}
+	for len(keys) > 0 {
+		var batch []string = keys
+		if len(batch) > exportBatchSize {
+			batch = batch[:exportBatchSize]
+		}
+		keys = keys[len(batch):]
+
+		// Encode the batch, then write it without the lock
+		var records, err = c.exportBatch(batch)
+		if err != nil {
+			return err
+		}
+		for _, r := range records {
+			if err := writeRecord(bw, format, written, r[0], r[1]); err != nil {
+				return err
+			}
+			written++
+		}
+	}
+	if format == ExportHash {
+		if _, err := bw.WriteString("\n}\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// exportBatch is a method of the Cache struct that encodes a batch of records for an export.
+// This method is thread-safe.
+//
+// Parameters:
+//   - keys ([]string): The keys of the records. The keys that don't exist anymore are skipped.
+//
+// Returns:
+//   - [][2][]byte: The json encoded keys and records.
+//   - error: If a record can't be encoded.
+func (c *Cache) exportBatch(keys []string) ([][2][]byte, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	var records [][2][]byte = make([][2][]byte, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := c.data[key]; !ok {
+			continue
+		} else if k, v, err := c.exportRecord(key); err != nil {
+			return nil, err
+		} else {
+			records = append(records, [2][]byte{k, v})
+		}
+	}
+	return records, nil
+}
+
+// writeRecord is a function that writes an encoded record of an export.
+//
+// Parameters:
+//   - w (*bufio.Writer): The writer to write the record to.
+//   - format (ExportFormat): The format to write the record in.
+//   - i (int): The number of records that were written before this one.
+//   - k ([]byte): The json encoded key.
+//   - v ([]byte): The json encoded record.
+//
+// Returns:
+//   - error: If the writer returns an error.
+func writeRecord(w *bufio.Writer, format ExportFormat, i int, k []byte, v []byte) error {
+	var err error
+	switch {
+	case format == ExportNDJSON:
+		_, err = fmt.Fprintf(w, "{%s:%s}\n", k, v)
+	case i == 0:
+		_, err = fmt.Fprintf(w, "\n%s:%s", k, v)
+	default:
+		_, err = fmt.Fprintf(w, ",\n%s:%s", k, v)
+	}
+	return err
+}
+
+// exportRecord is a method of the Cache struct that encodes a record for an export.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - []byte: The json encoded key.
+//   - []byte: The json encoded record, with the full-text fields wrapped.
+//   - error: If the record can't be decompressed, or the key or record can't be encoded.
+func (c *Cache) exportRecord(key string) ([]byte, []byte, error) {
+	var value, err = c.wrapped(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("key %s: %w", key, err)
+	}
+
+	// Encode the key and record
+	if k, err := json.Marshal(key); err != nil {
+		return nil, nil, err
+	} else if v, err := json.Marshal(value); err != nil {
+		return nil, nil, err
+	} else {
+		return k, v, nil
+	}
+}
+
+// wrapped is a method of the Cache struct that returns a record with its full-text fields wrapped,
+// so that it can be set again with the same full-text fields. The fields that aren't stored are included.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - map[string]any: A copy of the record, with the full-text fields wrapped.
+//   - error: If the record can't be decompressed.
+func (c *Cache) wrapped(key string) (map[string]any, error) {
+	var record, err = c.materialize(c.data[key])
+	if err != nil {
+		return nil, err
+	}
+	return WrapRecord(record, c.fields[key]), nil
+}
+
+// WrapRecord is a function that wraps the full-text fields of a record in the map format that is used in json files,
+// so that the record can be set in another cache with the same full-text fields. The fields that aren't stored are
+// added from their metadata, with "$hermes.stored": false.
+//
+// Parameters:
+//   - record (map[string]any): The record, as it's returned by cache.Get() or in a set event.
+//   - fields (map[string]Field): The metadata of the full-text fields, as it's returned by cache.Fields() or in a set event.
+//
+// Returns:
+//   - map[string]any: A copy of the record, with the full-text fields wrapped.
+func WrapRecord(record map[string]any, fields map[string]Field) map[string]any {
+	record = indexed(record, fields)
+	var value map[string]any = make(map[string]any, len(record))
+	for k, v := range record {
+		if s, ok := v.(string); ok && fields[k].FullText {
+			value[k] = wrapFullText(s, fields[k])
+		} else {
+			value[k] = v
+		}
+	}
+	return value
+}
+
+// wrapFullText is a function that wraps a full-text value in the map format that is used in json files.
+//
+// Parameters:
+//   - value (string): The full-text value.
+//   - f (Field): The metadata of the field.
+//
+// Returns:
+//   - map[string]any: The wrapped value: {"$hermes.full_text": true, "$hermes.value": value}.
+//     If the field doesn't use the standard analyzer, the "$hermes.analyzer" key is set as well,
+//     and if the field isn't stored, the "$hermes.stored" key is set to false.
+func wrapFullText(value string, f Field) map[string]any {
+	var wrapped map[string]any = map[string]any{
+		"$hermes.full_text": true,
+		"$hermes.value":     value,
+	}
+	if f.Analyzer != StandardAnalyzer {
+		wrapped["$hermes.analyzer"] = f.Analyzer
+	}
+	if !f.Stored {
+		wrapped["$hermes.stored"] = false
+	}
+	return wrapped
+}
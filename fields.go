@@ -0,0 +1,153 @@
+package hermes
+
+// Field is a struct that holds the metadata of a record field.
+// The metadata is kept separately from the record values, so that the
+// full-text fields can be re-indexed and exported after they've been set.
+type Field struct {
+	// Whether the field is indexed in the full-text index
+	FullText bool
+	// The name of the analyzer that splits the field value into words
+	Analyzer string
+	// Whether the field value is kept in the record. Fields that aren't stored are only indexed
+	Stored bool
+	// The value of a field that isn't stored, so that it can be re-indexed and exported
+	value string
+}
+
+// Fields is a method of the Cache struct that returns the metadata of the full-text fields of a record.
+// This method is thread-safe.
+//
+// Parameters:
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - map[string]Field: A copy of the field metadata, mapped by field name. Empty if the record has no full-text fields.
+func (c *Cache) Fields(key string) map[string]Field {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	// Copy the fields map
+	var fields map[string]Field = make(map[string]Field, len(c.fields[key]))
+	for name, f := range c.fields[key] {
+		fields[name] = f
+	}
+	return fields
+}
+
+// flatten is a function that copies a record, replacing its full-text values with their strings.
+// If a schema is provided, the record is validated against it, and its declared full-text fields are set as well.
+// The provided record is not modified.
+//
+// Parameters:
+//   - value (map[string]any): The record to flatten.
+//   - s (*Schema): The schema of the cache records. Can be nil.
+//
+// Returns:
+//   - map[string]any: The flattened record, including the fields that aren't stored.
+//   - map[string]Field: The metadata of the full-text fields. Nil if the record has no full-text fields.
+//   - error: If a full-text field uses an analyzer that isn't registered, or the record doesn't match the schema.
+func flatten(value map[string]any, s *Schema) (map[string]any, map[string]Field, error) {
+	var (
+		record map[string]any = make(map[string]any, len(value))
+		fields map[string]Field
+	)
+	for k, v := range value {
+		var wft *WFT = wftFrom(v)
+		if wft == nil {
+			record[k] = v
+			continue
+		}
+
+		// Verify the analyzer
+		var f Field = wft.field()
+		if _, err := analyzer(f.Analyzer); err != nil {
+			return nil, nil, err
+		}
+
+		// Set the full-text value and its metadata
+		if fields == nil {
+			fields = make(map[string]Field)
+		}
+		record[k] = wft.value
+		fields[k] = f
+	}
+
+	// Apply the schema
+	if s != nil {
+		if fields == nil {
+			fields = make(map[string]Field)
+		}
+		if err := s.apply(record, fields); err != nil {
+			return nil, nil, err
+		} else if len(fields) == 0 {
+			fields = nil
+		}
+	}
+	unstored(record, fields)
+	return record, fields, nil
+}
+
+// unstored is a function that keeps the values of the fields that aren't stored in their metadata.
+// The fields map is modified in place.
+//
+// Parameters:
+//   - record (map[string]any): The flattened record.
+//   - fields (map[string]Field): The metadata of the full-text fields.
+func unstored(record map[string]any, fields map[string]Field) {
+	for k, f := range fields {
+		if !f.Stored {
+			f.value, _ = record[k].(string)
+			fields[k] = f
+		}
+	}
+}
+
+// indexed is a function that returns a stored record with the values of its fields that aren't stored.
+// It's the inverse of stored, so that the record can be re-indexed and exported with all of its full-text fields.
+//
+// Parameters:
+//   - record (map[string]any): The stored record.
+//   - fields (map[string]Field): The metadata of the full-text fields.
+//
+// Returns:
+//   - map[string]any: A copy of the record with the fields that aren't stored, or the record itself if every field is stored.
+func indexed(record map[string]any, fields map[string]Field) map[string]any {
+	for _, f := range fields {
+		if !f.Stored {
+			var result map[string]any = make(map[string]any, len(record)+len(fields))
+			for k, v := range record {
+				result[k] = v
+			}
+			for k, f := range fields {
+				if !f.Stored && len(f.value) > 0 {
+					result[k] = f.value
+				}
+			}
+			return result
+		}
+	}
+	return record
+}
+
+// stored is a function that returns the fields of a flattened record that are kept in the cache.
+//
+// Parameters:
+//   - record (map[string]any): The flattened record.
+//   - fields (map[string]Field): The metadata of the full-text fields.
+//
+// Returns:
+//   - map[string]any: The record without the fields that aren't stored.
+func stored(record map[string]any, fields map[string]Field) map[string]any {
+	for _, f := range fields {
+		if !f.Stored {
+			var result map[string]any = make(map[string]any, len(record))
+			for k, v := range record {
+				if f, ok := fields[k]; !ok || f.Stored {
+					result[k] = v
+				}
+			}
+			return result
+		}
+	}
+	return record
+}
@@ -74,6 +74,7 @@ func (c *Cache) FTSetMaxBytes(maxBytes int) error {
 
 	// Set the maxBytes field
 	c.ft.maxBytes = maxBytes
+	c.events.publish(EventFTSettings, "", c.ft.settings(), nil, nil)
 
 	// Return no error
 	return nil
@@ -111,17 +112,22 @@ func (c *Cache) FTSetMaxSize(maxSize int) error {
 
 	// Set the maxSize field
 	c.ft.maxSize = maxSize
+	c.events.publish(EventFTSettings, "", c.ft.settings(), nil, nil)
 
 	// Return no error
 	return nil
 }
 
 // FTSetMinWordLength is a method of the Cache struct that sets the minimum word length for the full-text search.
+// If the new minimum word length is greater, the shorter words are removed from the full-text index.
+// Otherwise, the full-text fields of the records are re-indexed, so that the shorter words are added.
+// This method is thread-safe.
+//
 // Parameters:
 //   - minWordLength (int): An integer representing the minimum word length.
 //
 // Returns:
-//   - error: An error if the full-text search is not initialized or if the new minimum word length is greater than the maximum word length.
+//   - error: An error if the full-text search is not initialized, or if a full-text limit is reached while re-indexing.
 func (c *Cache) FTSetMinWordLength(minWordLength int) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -136,24 +142,45 @@ func (c *Cache) FTSetMinWordLength(minWordLength int) error {
 		return nil
 	}
 
-	// If the new min word length is greater than the max
-	// word length, reset the ft
+	// If the new min word length is greater than the current
+	// min word length, remove the shorter words
 	if minWordLength > c.ft.minWordLength {
-		return c.ftInit(c.ft.maxBytes, c.ft.maxSize, minWordLength)
+		c.ft.minWordLength = minWordLength
+		for word := range c.ft.storage {
+			if len(word) < minWordLength {
+				delete(c.ft.storage, word)
+			}
+		}
+		c.refreshQueries()
+		c.events.publish(EventFTSettings, "", c.ft.settings(), nil, nil)
+		return nil
 	}
 
-	// Set the minWordLength field
-	c.ft.minWordLength = minWordLength
-
-	// Iterate over the ft storage
-	for word := range c.ft.storage {
-		// Check if the word length is less than the min word length
-		if len(word) < minWordLength {
-			// Delete the word from the ft storage
-			delete(c.ft.storage, word)
-		}
+	// Else, re-index the records in a copy of the ft, so that
+	// the ft is left unchanged if a limit is reached
+	var ft *FullText = &FullText{
+		storage:       make(map[string]any, len(c.ft.storage)),
+		indices:       make(map[int]string, len(c.ft.indices)),
+		index:         c.ft.index,
+		maxSize:       c.ft.maxSize,
+		maxBytes:      c.ft.maxBytes,
+		minWordLength: minWordLength,
+	}
+	for word, keys := range c.ft.storage {
+		ft.storage[word] = keys
+	}
+	for index, key := range c.ft.indices {
+		ft.indices[index] = key
+	}
+	if err := c.ftInsert(ft); err != nil {
+		return err
 	}
 
+	// Update the cache full-text
+	c.ft = ft
+	c.refreshQueries()
+	c.events.publish(EventFTSettings, "", c.ft.settings(), nil, nil)
+
 	// Return no error
 	return nil
 }
@@ -223,3 +250,25 @@ func (c *Cache) FTStorageLength() (int, error) {
 	// Return the size of the storage map
 	return len(c.ft.storage), nil
 }
+
+// FTSettings is a method of the Cache struct that returns the limits of the full-text index.
+// If the full-text index is not initialized, this method returns an error.
+// This method is thread-safe.
+//
+// Returns:
+//   - int: The maximum number of words in the full-text index.
+//   - int: The maximum size of the full-text index, in bytes.
+//   - int: The minimum length of the words in the full-text index.
+//   - error: An error object. If no error occurs, this will be nil.
+func (c *Cache) FTSettings() (int, int, int, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	// Check if the ft is initialized
+	if c.ft == nil {
+		return -1, -1, -1, errors.New("full text not initialized")
+	}
+
+	// Return the settings
+	return c.ft.maxSize, c.ft.maxBytes, c.ft.minWordLength, nil
+}
@@ -23,5 +23,5 @@ func (c *Cache) Get(key string) map[string]any {
 // Returns:
 //   - A map[string]any representing the value associated with the given key in the cache.
 func (c *Cache) get(key string) map[string]any {
-	return c.data[key]
+	return c.record(key)
 }
@@ -1,11 +1,11 @@
 package hermes
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
-	"sync"
-
-	utils "github.com/realTristan/hermes/utils"
+	"os"
+	"path/filepath"
 )
 
 // InitCache is a function that initializes a new Cache struct and returns a pointer to it.
@@ -13,11 +13,16 @@ import (
 // Returns:
 //   - A pointer to a new Cache struct.
 func InitCache() *Cache {
-	return &Cache{
-		data:  make(map[string]map[string]any),
-		mutex: &sync.RWMutex{},
-		ft:    nil,
+	var c *Cache = &Cache{
+		data:    make(map[string]map[string]any),
+		ft:      nil,
+		fields:  make(map[string]map[string]Field),
+		events:  newEventLog(),
+		queries: newQueryIndex(),
+		stats:   &cacheStats{},
 	}
+	c.mutex = newLock(c)
+	return c
 }
 
 // Initialize the full-text for the cache
@@ -40,7 +45,11 @@ func (c *Cache) FTInit(maxSize int, maxBytes int, minWordLength int) error {
 	}
 
 	// Initialize the FT
-	return c.ftInit(maxSize, maxBytes, minWordLength)
+	if err := c.ftInit(maxSize, maxBytes, minWordLength); err != nil {
+		return err
+	}
+	c.events.publish(EventFTInit, "", c.ft.settings(), nil, nil)
+	return nil
 }
 
 // Initialize the full-text for the cache.
@@ -64,7 +73,7 @@ func (c *Cache) ftInit(maxSize int, maxBytes int, minWordLength int) error {
 	}
 
 	// Load the cache data
-	if err := ft.insert(&c.data); err != nil {
+	if err := c.ftInsert(ft); err != nil {
 		return err
 	}
 
@@ -100,6 +109,7 @@ func (c *Cache) FTInitWithMap(data map[string]map[string]any, maxSize int, maxBy
 }
 
 // Initialize the full-text for the cache with a map.
+// The records are copied into the cache, so the provided map is not modified.
 // This method is not thread-safe, and should only be called from an exported function.
 //
 // Parameters:
@@ -110,32 +120,32 @@ func (c *Cache) FTInitWithMap(data map[string]map[string]any, maxSize int, maxBy
 // Returns:
 // - error
 func (c *Cache) ftInitWithMap(data map[string]map[string]any, maxSize int, maxBytes int, minWordLength int) error {
-	// Initialize the FT struct
-	var ft *FullText = &FullText{
-		storage:       make(map[string]any),
-		indices:       make(map[int]string),
-		index:         0,
-		maxSize:       maxSize,
-		maxBytes:      maxBytes,
-		minWordLength: minWordLength,
-	}
-
-	// Iterate over the cache keys and add them to the data
-	for k := range c.data {
-		if _, ok := data[k]; ok {
+	// Verify that the keys don't already exist in the cache
+	for k := range data {
+		if _, ok := c.data[k]; ok {
 			return fmt.Errorf("key %s already exists in cache", k)
 		}
-		data[k] = c.data[k]
 	}
 
-	// Insert the data into the ft storage
-	if err := ft.insert(&data); err != nil {
+	// Initialize the full-text with the current cache data
+	if err := c.ftInit(maxSize, maxBytes, minWordLength); err != nil {
 		return err
 	}
+	var l *loader = c.newLoader(c.ft, nil)
+	c.ft = nil
+
+	// Load the data into the cache
+	for k, v := range data {
+		if err := l.load(k, v); err != nil {
+			l.rollback()
+			return err
+		}
+	}
 
-	// Update the cache varoables
-	c.data = data
-	c.ft = ft
+	// Update the cache full-text
+	c.events.publish(EventFTInit, "", l.ft.settings(), nil, nil)
+	c.ft = l.ft
+	l.commit()
 
 	// Return no error
 	return nil
@@ -166,6 +176,7 @@ func (c *Cache) FTInitWithJson(file string, maxSize int, maxBytes int, minWordLe
 }
 
 // Initialize the full-text for the cache with a JSON file.
+// The file is streamed with ftInitWithReader, so it's never held in memory as a whole.
 // This method is not thread-safe, and should only be called from an exported function.
 //
 // Parameters:
@@ -174,56 +185,42 @@ func (c *Cache) FTInitWithJson(file string, maxSize int, maxBytes int, minWordLe
 // - maxBytes: the maximum size, in bytes, of the full-text index.
 //
 // Returns:
-// - error: Json file read error, or init with map error.
+// - error: Json file read error, or init with reader error.
 func (c *Cache) ftInitWithJson(file string, maxSize int, maxBytes int, minWordLength int) error {
-	if data, err := utils.ReadJson[map[string]map[string]any](file); err != nil {
+	var f, err = os.Open(filepath.Clean(file))
+	if err != nil {
 		return err
-	} else {
-		return c.ftInitWithMap(data, maxSize, maxBytes, minWordLength)
 	}
+	defer f.Close()
+	return c.ftInitWithReader(bufio.NewReader(f), maxSize, maxBytes, minWordLength, nil)
 }
 
-// insert is a method of the FullText struct that inserts a value in the full-text cache for the specified key.
+// ftInsert is a method of the Cache struct that inserts the full-text fields of the cache records in a full-text index.
+// The fields are read from the stored records, and the fields that aren't stored from their metadata.
 // This function is not thread-safe and should only be called from an exported function.
 //
 // Parameters:
-//   - data: A map of maps containing the data to be inserted.
+//   - ft: The full-text index to insert the fields in.
 //
 // Returns:
-//   - An error if the full-text storage limit or byte-size limit is reached.
-func (ft *FullText) insert(data *map[string]map[string]any) error {
+//   - An error if an analyzer isn't registered, or the full-text storage limit or byte-size limit is reached.
+func (c *Cache) ftInsert(ft *FullText) error {
 	// Create a new temp storage
 	var ts *TempStorage = NewTempStorage(ft)
 
-	// Loop through the json data
-	for cacheKey, cacheValue := range *data {
-		for k, v := range cacheValue {
-			if ftv := WFTGetValue(v); len(ftv) == 0 {
-				continue
-			} else {
-				// Set the key in the provided value to the fulltext value
-				(*data)[cacheKey][k] = ftv
-
-				// Insert the value in the temp storage
-				if err := ts.insert(ft, cacheKey, ftv); err != nil {
-					return err
-				}
-			}
+	// Loop through the records with full-text fields
+	for key, fields := range c.fields {
+		if err := ts.insertFields(ft, key, indexed(c.record(key), fields), fields); err != nil {
+			return err
 		}
 	}
 
-	// Merge the keys
-	// ts.mergeKeys()
-
 	// Iterate over the temp storage and set the values with len 1 to int
 	ts.cleanSingleArrays()
 
 	// Set the full-text cache to the temp map
 	ts.updateFullText(ft)
 
-	// Print the size of the full-text cache storage
-	fmt.Println(utils.Size(ft.storage))
-
 	// Return nil for no errors
 	return nil
 }
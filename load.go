@@ -0,0 +1,301 @@
+package hermes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Progress is a function that is called by the streaming loaders after each record has been loaded.
+//
+// Parameters:
+//   - loaded (int): The number of records that have been loaded so far.
+type Progress func(loaded int)
+
+// loader is a struct that loads records into the cache one by one, and indexes their full-text values.
+// A single TempStorage is used for the whole load, so that the full-text keys are only built once.
+//
+// Fields:
+//   - c (*Cache): The cache to load the records into.
+//   - ft (*FullText): The full-text index to insert the records into. If nil, the records aren't indexed.
+//   - ts (*TempStorage): The temp storage for the full-text index.
+//   - keys ([]string): The keys of the records that have been loaded.
+//   - progress (Progress): The function to call after each record is loaded. Can be nil.
+type loader struct {
+	c        *Cache
+	ft       *FullText
+	ts       *TempStorage
+	keys     []string
+	progress Progress
+}
+
+// FTInitWithReader is a method of the Cache struct that initializes the full-text index with json data read from a reader.
+// The records are decoded and indexed one by one, so the whole json document is never held in memory.
+// Both the hash format ({"key": {...}, ...}) and the array format ([{...}, ...]) are supported.
+// When the array format is used, the key of each record is its position in the array.
+// If an error occurs, the records that were loaded are removed and the full-text index is not initialized.
+// This method is thread-safe.
+//
+// Parameters:
+//   - r (io.Reader): The reader to read the json data from.
+//   - maxSize (int): The maximum number of words to store in the full-text index.
+//   - maxBytes (int): The maximum size, in bytes, of the full-text index.
+//   - minWordLength (int): The minimum length of a word that is stored in the full-text index.
+//   - progress (Progress): A function that is called after each record is loaded. Can be nil.
+//
+// Returns:
+//   - error: If the full-text is already initialized, the json is invalid, a key already exists, or a full-text limit is reached.
+func (c *Cache) FTInitWithReader(r io.Reader, maxSize int, maxBytes int, minWordLength int, progress Progress) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Verify that the ft cache is not initialized
+	if c.ft != nil {
+		return errors.New("full-text cache already initialized")
+	}
+
+	// Initialize the FT
+	return c.ftInitWithReader(r, maxSize, maxBytes, minWordLength, progress)
+}
+
+// ftInitWithReader is a method of the Cache struct that initializes the full-text index with json data read from a reader.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - r (io.Reader): The reader to read the json data from.
+//   - maxSize (int): The maximum number of words to store in the full-text index.
+//   - maxBytes (int): The maximum size, in bytes, of the full-text index.
+//   - minWordLength (int): The minimum length of a word that is stored in the full-text index.
+//   - progress (Progress): A function that is called after each record is loaded. Can be nil.
+//
+// Returns:
+//   - error: If the json is invalid, a key already exists, or a full-text limit is reached.
+func (c *Cache) ftInitWithReader(r io.Reader, maxSize int, maxBytes int, minWordLength int, progress Progress) error {
+	// Initialize the full-text with the current cache data
+	if err := c.ftInit(maxSize, maxBytes, minWordLength); err != nil {
+		return err
+	}
+	var l *loader = c.newLoader(c.ft, progress)
+	c.ft = nil
+
+	// Decode the records
+	if err := l.decode(json.NewDecoder(r)); err != nil {
+		l.rollback()
+		return err
+	}
+
+	// Update the cache full-text
+	c.events.publish(EventFTInit, "", l.ft.settings(), nil, nil)
+	c.ft = l.ft
+	l.commit()
+
+	// Return no error
+	return nil
+}
+
+// LoadNDJSON is a method of the Cache struct that loads newline-delimited json records into the cache.
+// Each line is a json object that maps keys to records, usually with a single entry: {"key": {...}}.
+// The records are indexed in the full-text index if it's initialized.
+// If an error occurs, the records that were loaded before it remain in the cache, just like with repeated calls to Set.
+// This method is thread-safe.
+//
+// Parameters:
+//   - r (io.Reader): The reader to read the ndjson data from.
+//   - progress (Progress): A function that is called after each record is loaded. Can be nil.
+//
+// Returns:
+//   - error: If a line is invalid, a key already exists, or a full-text limit is reached.
+func (c *Cache) LoadNDJSON(r io.Reader, progress Progress) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.loadNDJSON(r, progress)
+}
+
+// loadNDJSON is a method of the Cache struct that loads newline-delimited json records into the cache.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - r (io.Reader): The reader to read the ndjson data from.
+//   - progress (Progress): A function that is called after each record is loaded. Can be nil.
+//
+// Returns:
+//   - error: If a line is invalid, a key already exists, or a full-text limit is reached.
+func (c *Cache) loadNDJSON(r io.Reader, progress Progress) error {
+	var (
+		l   *loader       = c.newLoader(c.ft, progress)
+		dec *json.Decoder = json.NewDecoder(r)
+	)
+	defer l.commit()
+
+	// Decode the lines
+	for line := 1; ; line++ {
+		var records map[string]map[string]any
+		if err := dec.Decode(&records); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		// Load the records
+		for key, value := range records {
+			if err := l.load(key, value); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+	}
+}
+
+// newLoader is a method of the Cache struct that creates a new loader.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - ft (*FullText): The full-text index to insert the records into. If nil, the records aren't indexed.
+//   - progress (Progress): The function to call after each record is loaded. Can be nil.
+//
+// Returns:
+//   - *loader: A pointer to the new loader.
+func (c *Cache) newLoader(ft *FullText, progress Progress) *loader {
+	var l *loader = &loader{
+		c:        c,
+		ft:       ft,
+		keys:     []string{},
+		progress: progress,
+	}
+	if ft != nil {
+		l.ts = NewTempStorage(ft)
+	}
+	return l
+}
+
+// decode is a method of the loader struct that decodes and loads the records of a json hash or array.
+//
+// Parameters:
+//   - dec (*json.Decoder): The decoder to read the records from.
+//
+// Returns:
+//   - error: If the json is invalid, a key already exists, or a full-text limit is reached.
+func (l *loader) decode(dec *json.Decoder) error {
+	var t, err = dec.Token()
+	if err != nil {
+		return err
+	}
+
+	// Read the records
+	for i := 0; dec.More(); i++ {
+		var key string
+		switch t {
+		case json.Delim('{'):
+			if t, err := dec.Token(); err != nil {
+				return err
+			} else {
+				key = t.(string)
+			}
+		case json.Delim('['):
+			key = strconv.Itoa(i)
+		default:
+			return errors.New("invalid json: expected an object or an array")
+		}
+
+		// Decode and load the record
+		var value map[string]any
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("record %s: %w", key, err)
+		} else if err := l.load(key, value); err != nil {
+			return err
+		}
+	}
+
+	// Read the closing delimiter
+	_, err = dec.Token()
+	return err
+}
+
+// load is a method of the loader struct that loads a single record into the cache.
+// The full-text values of the record are inserted in the temp storage, and removed again if one of them can't be.
+//
+// Parameters:
+//   - key (string): The key of the record.
+//   - value (map[string]any): The record.
+//
+// Returns:
+//   - error: If the key already exists, an analyzer isn't registered, or a full-text limit is reached.
+func (l *loader) load(key string, value map[string]any) error {
+	if _, ok := l.c.data[key]; ok {
+		return fmt.Errorf("key %s already exists in cache", key)
+	}
+
+	// Separate the full-text fields metadata from the values
+	var record, fields, err = flatten(value, l.c.schema)
+	if err != nil {
+		return err
+	}
+
+	// Compress the value before it's indexed
+	v, err := l.c.compression.compress(stored(record, fields))
+	if err != nil {
+		return err
+	}
+
+	// Insert the full-text values in the temp storage
+	if l.ft != nil {
+		if err := l.ts.insertFields(l.ft, key, record, fields); err != nil {
+			l.ts.remove([]string{key})
+			return err
+		}
+	}
+
+	// Set the value in the cache
+	l.c.data[key] = v
+	if fields != nil {
+		l.c.fields[key] = fields
+	}
+	l.keys = append(l.keys, key)
+
+	// Report the progress
+	if l.progress != nil {
+		l.progress(len(l.keys))
+	}
+	return nil
+}
+
+// commit is a method of the loader struct that updates the full-text index with the temp storage,
+// and publishes the set events of the loaded records.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - None
+func (l *loader) commit() {
+	if l.ft != nil {
+		l.ts.cleanSingleArrays()
+		l.ts.updateFullText(l.ft)
+	}
+	for _, key := range l.keys {
+		var (
+			record map[string]any   = l.c.record(key)
+			fields map[string]Field = l.c.fields[key]
+		)
+		l.c.events.publish(EventSet, key, record, l.c.percolate(key, indexed(record, fields), fields), fields)
+	}
+}
+
+// rollback is a method of the loader struct that removes the loaded records from the cache,
+// and their words from the full-text index, since the temp storage writes into it.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - None
+func (l *loader) rollback() {
+	if l.ft != nil {
+		l.ts.remove(l.keys)
+	}
+	for _, key := range l.keys {
+		delete(l.c.data, key)
+		delete(l.c.fields, key)
+	}
+}
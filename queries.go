@@ -0,0 +1,544 @@
+package hermes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	utils "github.com/realTristan/hermes/utils"
+)
+
+// SearchUpdateType is the type of a change in the results of a standing search query.
+type SearchUpdateType string
+
+// The types of the search result changes.
+const (
+	// A record started matching the query. The update contains the record
+	SearchAdded SearchUpdateType = "added"
+	// A record stopped matching the query, because it was deleted or re-indexed
+	SearchRemoved SearchUpdateType = "removed"
+)
+
+// SearchUpdate is a struct that represents a change in the results of a standing search query.
+// The records in the updates are shared with the cache, and must not be modified.
+type SearchUpdate struct {
+	// The type of the change
+	Type SearchUpdateType `json:"type"`
+	// The key of the record
+	Key string `json:"key"`
+	// The record, for added updates
+	Value map[string]any `json:"value,omitempty"`
+}
+
+// query is a struct that represents a search query that the records are matched against when they're indexed.
+//
+// Fields:
+//   - id (string): The id of a registered query. Empty for the standing queries of cache.WatchSearch().
+//   - sp (SearchParams): The search params, with the query lowercased and trimmed.
+//   - words ([]string): The words of the query.
+//   - hits (map[string]bool): The keys of the records that match the query.
+//   - ch (chan SearchUpdate): The channel the result changes are delivered to. Nil if the changes aren't watched.
+//   - done (chan struct{}): A channel that's closed when the query is removed.
+type query struct {
+	id    string
+	sp    SearchParams
+	words []string
+	hits  map[string]bool
+	ch    chan SearchUpdate
+	done  chan struct{}
+}
+
+// queryIndex is a struct that indexes the queries by word, so that a record is only matched
+// against the queries that share a word with it.
+//
+// Fields:
+//   - exact (map[string]map[*query]bool): The strict one-word queries and the multi-word queries, by their first word.
+//   - partial (map[string]map[*query]bool): The one-word queries that match the words containing them, by their word.
+//   - hits (map[string]map[*query]bool): The standing queries, by the keys of the records that match them.
+//   - ids (map[string]*query): The registered queries, by id.
+type queryIndex struct {
+	exact   map[string]map[*query]bool
+	partial map[string]map[*query]bool
+	hits    map[string]map[*query]bool
+	ids     map[string]*query
+}
+
+// newQueryIndex is a function that creates a new empty query index.
+//
+// Returns:
+//   - *queryIndex: A pointer to the new query index.
+func newQueryIndex() *queryIndex {
+	return &queryIndex{
+		exact:   make(map[string]map[*query]bool),
+		partial: make(map[string]map[*query]bool),
+		hits:    make(map[string]map[*query]bool),
+		ids:     make(map[string]*query),
+	}
+}
+
+// RegisterQuery is a method of the Cache struct that stores a full-text search query, so that the documents can be
+// matched against it with cache.Percolate(). The records that are set are matched against the registered queries as well,
+// and the ids of the queries they match are listed in their set events. The limit of the search params is ignored.
+// This method is thread-safe.
+//
+// Parameters:
+//   - id (string): The id of the query.
+//   - sp (SearchParams): The search params. Only the query and strict params are used.
+//
+// Returns:
+//   - error: If the id or query is empty, or a query with the same id is already registered.
+func (c *Cache) RegisterQuery(id string, sp SearchParams) error {
+	if len(id) == 0 {
+		return errors.New("invalid query id")
+	}
+	var q, err = newQuery(sp)
+	if err != nil {
+		return err
+	}
+	q.id = id
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Register the query
+	if _, ok := c.queries.ids[id]; ok {
+		return fmt.Errorf("query %s already registered", id)
+	}
+	c.queries.ids[id] = q
+	c.queries.add(q)
+	return nil
+}
+
+// UnregisterQuery is a method of the Cache struct that removes a registered query.
+// This method is thread-safe.
+//
+// Parameters:
+//   - id (string): The id of the query.
+//
+// Returns:
+//   - error: If no query is registered with the id.
+func (c *Cache) UnregisterQuery(id string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Remove the query
+	if q, ok := c.queries.ids[id]; !ok {
+		return fmt.Errorf("query %s not registered", id)
+	} else {
+		delete(c.queries.ids, id)
+		c.queries.remove(q)
+	}
+	return nil
+}
+
+// Percolate is a method of the Cache struct that returns the ids of the registered queries that a document matches.
+// The document is not stored. Its full-text fields are the ones that would be indexed if it was set with cache.Set(),
+// so its values must be wrapped with cache.WithFT(), or declared as full-text in the schema.
+// The queries are indexed by word, so the document is only matched against the queries that share a word with it.
+// This method is thread-safe.
+//
+// Parameters:
+//   - doc (map[string]any): The document.
+//
+// Returns:
+//   - []string: The sorted ids of the matching queries.
+//   - error: If the full-text index is not initialized, or the document doesn't match the schema.
+func (c *Cache) Percolate(doc map[string]any) ([]string, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	// Check if the FT index is initialized
+	if c.ft == nil {
+		return nil, errors.New("full-text not initialized")
+	}
+
+	// Separate the full-text fields metadata from the values
+	var record, fields, err = flatten(doc, c.schema)
+	if err != nil {
+		return nil, err
+	}
+
+	// Match the document against the registered queries
+	var (
+		words map[string]bool = c.words(record, fields)
+		ids   []string        = []string{}
+	)
+	for q := range c.queries.candidates(words) {
+		if len(q.id) > 0 && q.match(record, words) {
+			ids = append(ids, q.id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// WatchSearch is a method of the Cache struct that registers a standing full-text search query, and returns
+// a channel of the changes in its results. The records that match the query when it's registered are delivered
+// first as added updates. Then, the records are matched against the query when they're set, and an added
+// or removed update is delivered whenever the results change. The limit of the search params is ignored.
+// The channel is closed when the context is done. If the consumer doesn't read the updates fast enough and
+// the channel is full, the query is dropped and the channel is closed.
+// This method is thread-safe.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the query.
+//   - sp (SearchParams): The search params. Only the query and strict params are used.
+//
+// Returns:
+//   - <-chan SearchUpdate: The channel of result changes.
+//   - error: If the query is empty, or the full-text index is not initialized.
+func (c *Cache) WatchSearch(ctx context.Context, sp SearchParams) (<-chan SearchUpdate, error) {
+	var q, err = newQuery(sp)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Check if the FT index is initialized
+	if c.ft == nil {
+		return nil, errors.New("full-text not initialized")
+	}
+
+	// Find the records that match the query
+	var hits []string
+	for key, fields := range c.fields {
+		var record map[string]any = indexed(c.record(key), fields)
+		if q.match(record, c.words(record, fields)) {
+			hits = append(hits, key)
+		}
+	}
+
+	// Register the query, and deliver the current results
+	q.ch = make(chan SearchUpdate, watchBufferSize+len(hits))
+	c.queries.add(q)
+	for _, key := range hits {
+		c.queries.hit(q, key, c.record(key))
+	}
+
+	// Remove the query when the context is done
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			c.queries.remove(q)
+		case <-q.done:
+		}
+	}()
+	return q.ch, nil
+}
+
+// newQuery is a function that creates a new query from search params.
+//
+// Parameters:
+//   - sp (SearchParams): The search params.
+//
+// Returns:
+//   - *query: A pointer to the new query.
+//   - error: If the query is empty.
+func newQuery(sp SearchParams) (*query, error) {
+	sp.Query = strings.TrimSpace(strings.ToLower(sp.Query))
+	if len(sp.Query) == 0 {
+		return nil, errors.New("invalid query")
+	}
+	return &query{
+		sp:    sp,
+		words: strings.Split(sp.Query, " "),
+		hits:  make(map[string]bool),
+		done:  make(chan struct{}),
+	}, nil
+}
+
+// match is a method of the query struct that returns whether a record matches the query, with the same rules as cache.Search().
+// One-word queries match the records with an indexed word that contains the query, or that equals it if the search is strict.
+// Multi-word queries match the records that are indexed under the first word, and have a value that contains the whole query.
+//
+// Parameters:
+//   - record (map[string]any): The record.
+//   - words (map[string]bool): The words the record is indexed under.
+//
+// Returns:
+//   - bool: Whether the record matches the query.
+func (q *query) match(record map[string]any, words map[string]bool) bool {
+	switch {
+	case len(q.words) > 1:
+		if !words[q.words[0]] {
+			return false
+		}
+		for _, value := range record {
+			if v, ok := value.(string); ok && strings.Contains(strings.ToLower(v), q.sp.Query) {
+				return true
+			}
+		}
+		return false
+	case q.sp.Strict:
+		return words[q.sp.Query]
+	}
+	for word := range words {
+		if utils.Contains(word, q.sp.Query) {
+			return true
+		}
+	}
+	return false
+}
+
+// add is a method of the queryIndex struct that adds a query to the index.
+//
+// Parameters:
+//   - q (*query): The query.
+//
+// Returns:
+//   - None
+func (ix *queryIndex) add(q *query) {
+	var m map[string]map[*query]bool = ix.exact
+	if len(q.words) == 1 && !q.sp.Strict {
+		m = ix.partial
+	}
+	if _, ok := m[q.words[0]]; !ok {
+		m[q.words[0]] = make(map[*query]bool)
+	}
+	m[q.words[0]][q] = true
+}
+
+// remove is a method of the queryIndex struct that removes a query from the index, and closes its channel.
+//
+// Parameters:
+//   - q (*query): The query.
+//
+// Returns:
+//   - None
+func (ix *queryIndex) remove(q *query) {
+	for _, m := range []map[string]map[*query]bool{ix.exact, ix.partial} {
+		if !m[q.words[0]][q] {
+			continue
+		}
+		delete(m[q.words[0]], q)
+		if len(m[q.words[0]]) == 0 {
+			delete(m, q.words[0])
+		}
+		for key := range q.hits {
+			ix.unlink(q, key)
+		}
+		if q.ch != nil {
+			close(q.ch)
+			q.ch = nil
+		}
+		close(q.done)
+		return
+	}
+}
+
+// candidates is a method of the queryIndex struct that returns the queries that share a word with a record.
+//
+// Parameters:
+//   - words (map[string]bool): The words the record is indexed under.
+//
+// Returns:
+//   - map[*query]bool: The queries that the record can match.
+func (ix *queryIndex) candidates(words map[string]bool) map[*query]bool {
+	var result map[*query]bool = make(map[*query]bool)
+	for word := range words {
+		for q := range ix.exact[word] {
+			result[q] = true
+		}
+		if len(ix.partial) == 0 {
+			continue
+		}
+
+		// The one-word queries can match any part of the word
+		for i := 0; i < len(word); i++ {
+			for j := i + 1; j <= len(word); j++ {
+				for q := range ix.partial[word[i:j]] {
+					result[q] = true
+				}
+			}
+		}
+	}
+	return result
+}
+
+// hit is a method of the queryIndex struct that adds a record to the results of a query, and delivers an added update.
+//
+// Parameters:
+//   - q (*query): The query.
+//   - key (string): The key of the record.
+//   - record (map[string]any): The record.
+//
+// Returns:
+//   - None
+func (ix *queryIndex) hit(q *query, key string, record map[string]any) {
+	if q.hits[key] || q.removed() {
+		return
+	}
+	q.hits[key] = true
+	if _, ok := ix.hits[key]; !ok {
+		ix.hits[key] = make(map[*query]bool)
+	}
+	ix.hits[key][q] = true
+	ix.send(q, SearchUpdate{Type: SearchAdded, Key: key, Value: record})
+}
+
+// unhit is a method of the queryIndex struct that removes a record from the results of the queries, and delivers the removed updates.
+//
+// Parameters:
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - None
+func (ix *queryIndex) unhit(key string) {
+	for q := range ix.hits[key] {
+		ix.unlink(q, key)
+		ix.send(q, SearchUpdate{Type: SearchRemoved, Key: key})
+	}
+}
+
+// unlink is a method of the queryIndex struct that removes a record from the results of a query.
+//
+// Parameters:
+//   - q (*query): The query.
+//   - key (string): The key of the record.
+//
+// Returns:
+//   - None
+func (ix *queryIndex) unlink(q *query, key string) {
+	delete(q.hits, key)
+	delete(ix.hits[key], q)
+	if len(ix.hits[key]) == 0 {
+		delete(ix.hits, key)
+	}
+}
+
+// send is a method of the queryIndex struct that delivers an update to a query.
+// If the channel of the query is full, the query is removed.
+//
+// Parameters:
+//   - q (*query): The query.
+//   - u (SearchUpdate): The update.
+//
+// Returns:
+//   - None
+func (ix *queryIndex) send(q *query, u SearchUpdate) {
+	if q.ch == nil {
+		return
+	}
+	select {
+	case q.ch <- u:
+	default:
+		ix.remove(q)
+	}
+}
+
+// removed is a method of the query struct that returns whether the query was removed from the index.
+//
+// Returns:
+//   - bool: Whether the query was removed.
+func (q *query) removed() bool {
+	select {
+	case <-q.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// words is a method of the Cache struct that returns the words a record is indexed under in the full-text index.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - record (map[string]any): The record.
+//   - fields (map[string]Field): The metadata of the record fields.
+//
+// Returns:
+//   - map[string]bool: The words of the full-text fields that aren't shorter than the minimum word length.
+func (c *Cache) words(record map[string]any, fields map[string]Field) map[string]bool {
+	var words map[string]bool = make(map[string]bool)
+	for k, f := range fields {
+		var v, ok = record[k].(string)
+		if !ok || !f.FullText {
+			continue
+		}
+		var a, err = analyzer(f.Analyzer)
+		if err != nil {
+			continue
+		}
+		for _, word := range a(v) {
+			if len(word) > 0 && len(word) >= c.ft.minWordLength {
+				words[word] = true
+			}
+		}
+	}
+	return words
+}
+
+// percolate is a method of the Cache struct that matches a record that was just indexed against the queries.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - key (string): The key of the record.
+//   - record (map[string]any): The record, with the values that aren't stored.
+//   - fields (map[string]Field): The metadata of the record fields.
+//
+// Returns:
+//   - []string: The sorted ids of the registered queries that the record matches.
+func (c *Cache) percolate(key string, record map[string]any, fields map[string]Field) []string {
+	if c.ft == nil || len(fields) == 0 || (len(c.queries.exact) == 0 && len(c.queries.partial) == 0) {
+		return nil
+	}
+	var (
+		words map[string]bool = c.words(record, fields)
+		ids   []string
+	)
+	for q := range c.queries.candidates(words) {
+		switch {
+		case !q.match(record, words):
+			continue
+		case len(q.id) > 0:
+			ids = append(ids, q.id)
+		default:
+			c.queries.hit(q, key, c.record(key))
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// refreshQueries is a method of the Cache struct that matches all the records against the queries again,
+// after the records were re-indexed, and delivers the changes in the results.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - None
+func (c *Cache) refreshQueries() {
+	if len(c.queries.exact) == 0 && len(c.queries.partial) == 0 {
+		return
+	}
+
+	// Remove the records that no longer match
+	for key, queries := range c.queries.hits {
+		var (
+			record map[string]any = indexed(c.record(key), c.fields[key])
+			words  map[string]bool
+		)
+		if c.ft != nil {
+			words = c.words(record, c.fields[key])
+		}
+		for q := range queries {
+			if words == nil || !q.match(record, words) {
+				c.queries.unlink(q, key)
+				c.queries.send(q, SearchUpdate{Type: SearchRemoved, Key: key})
+			}
+		}
+	}
+
+	// Add the records that now match. The registered queries are not notified
+	for key, fields := range c.fields {
+		c.percolate(key, indexed(c.record(key), fields), fields)
+	}
+}
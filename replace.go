@@ -0,0 +1,51 @@
+package hermes
+
+// Replace is a method of the Cache struct that sets a value in the cache for the specified key,
+// replacing the value of the key if it already exists. Set fails for an existing key instead.
+// This function is thread-safe.
+//
+// Parameters:
+//   - key: A string representing the key to set the value for.
+//   - value: A map[string]any representing the value to set.
+//
+// Returns:
+//   - An error if the value is invalid, or doesn't fit in the full-text index. The existing value is kept.
+func (c *Cache) Replace(key string, value map[string]any) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.replace(key, value)
+}
+
+// replace is a method of the Cache struct that sets a value in the cache for the specified key,
+// replacing the value of the key if it already exists.
+// This function is not thread-safe, and should only be called from an exported function.
+// The replaced value is deleted and set again, so a delete event and a set event are published.
+//
+// Parameters:
+//   - key: A string representing the key to set the value for.
+//   - value: A map[string]any representing the value to set.
+//
+// Returns:
+//   - An error if the value is invalid, the existing value can't be decompressed, or the new value doesn't fit in the full-text index. Otherwise, nil.
+func (c *Cache) replace(key string, value map[string]any) error {
+	if _, ok := c.data[key]; !ok {
+		return c.set(key, value)
+	}
+
+	// Verify the value before the existing value is deleted
+	if _, _, err := flatten(value, c.schema); err != nil {
+		return err
+	}
+
+	// Replace the value, and set the existing value again if the new value can't be set
+	var existing, err = c.wrapped(key)
+	if err != nil {
+		return err
+	}
+	c.delete(key)
+	if err := c.set(key, value); err != nil {
+		_ = c.set(key, existing)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,366 @@
+package hermes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// FieldType is the type of a schema field. The values of a typed field are validated, and converted into the matching go type.
+type FieldType string
+
+// The field types that are supported by the schema.
+const (
+	// FieldText is a string field. Full-text text fields use the standard analyzer by default
+	FieldText FieldType = "text"
+	// FieldKeyword is a string field. Full-text keyword fields use the keyword analyzer by default
+	FieldKeyword FieldType = "keyword"
+	// FieldInt is an integer field. The values are stored as int
+	FieldInt FieldType = "int"
+	// FieldFloat is a number field. The values are stored as float64
+	FieldFloat FieldType = "float"
+	// FieldBool is a boolean field
+	FieldBool FieldType = "bool"
+	// FieldTime is a time field. The values are stored as time.Time, and strings are parsed as RFC 3339
+	FieldTime FieldType = "time"
+)
+
+// SchemaField is a struct that declares a field of the cache records.
+type SchemaField struct {
+	// The name of the field
+	Name string `json:"name"`
+	// The type of the field
+	Type FieldType `json:"type"`
+	// Whether the field is indexed in the full-text index. Only text and keyword fields can be full-text
+	FullText bool `json:"full_text"`
+	// The name of the analyzer used by the full-text index. Defaults to the analyzer of the field type
+	Analyzer string `json:"analyzer,omitempty"`
+	// Whether the field must be set in every record
+	Required bool `json:"required"`
+}
+
+// Schema is a struct that declares the fields of the cache records.
+// When a schema is set, the records are validated when they're set, and the declared full-text fields
+// are indexed without having to wrap their values with cache.WithFT(). Fields that aren't declared are stored as-is.
+type Schema struct {
+	Fields []SchemaField `json:"fields"`
+}
+
+// SetSchema is a method of the Cache struct that sets the schema of the cache records.
+// The existing records are validated against the new schema, and re-indexed if the full-text index is initialized.
+// If a record is invalid, the schema is not set. A nil schema removes the current schema.
+// This method is thread-safe.
+//
+// Parameters:
+//   - s (*Schema): The schema to set.
+//
+// Returns:
+//   - error: If the schema is invalid, an existing record is invalid, or a full-text limit is reached.
+func (c *Cache) SetSchema(s *Schema) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.setSchema(s)
+}
+
+// setSchema is a method of the Cache struct that sets the schema of the cache records.
+// This method is not thread-safe, and should only be called from an exported function.
+//
+// Parameters:
+//   - s (*Schema): The schema to set.
+//
+// Returns:
+//   - error: If the schema is invalid, an existing record is invalid, or a full-text limit is reached.
+func (c *Cache) setSchema(s *Schema) error {
+	if s != nil {
+		if err := s.validate(); err != nil {
+			return err
+		}
+		s = s.copy()
+	}
+
+	// Validate the existing records against the schema
+	var (
+		data   map[string]map[string]any   = make(map[string]map[string]any, len(c.data))
+		fields map[string]map[string]Field = make(map[string]map[string]Field, len(c.fields))
+	)
+	for key := range c.data {
+		var (
+			record map[string]any   = make(map[string]any)
+			f      map[string]Field = make(map[string]Field)
+		)
+		if v, err := c.materialize(c.data[key]); err != nil {
+			return fmt.Errorf("key %s: %w", key, err)
+		} else {
+			for k, v := range indexed(v, c.fields[key]) {
+				record[k] = v
+			}
+		}
+		for k, v := range c.fields[key] {
+			f[k] = v
+		}
+
+		// Apply the schema and compress the stored fields of the record
+		if err := s.apply(record, f); err != nil {
+			return fmt.Errorf("key %s: %w", key, err)
+		}
+		unstored(record, f)
+		if v, err := c.compression.compress(stored(record, f)); err != nil {
+			return err
+		} else {
+			data[key] = v
+		}
+		if len(f) > 0 {
+			fields[key] = f
+		}
+	}
+
+	// Re-index the records
+	var ft *FullText = c.ft
+	if c.ft != nil {
+		ft = &FullText{
+			storage:       make(map[string]any),
+			indices:       make(map[int]string),
+			index:         0,
+			maxSize:       c.ft.maxSize,
+			maxBytes:      c.ft.maxBytes,
+			minWordLength: c.ft.minWordLength,
+		}
+		var prevData, prevFields = c.data, c.fields
+		c.data, c.fields = data, fields
+		if err := c.ftInsert(ft); err != nil {
+			c.data, c.fields = prevData, prevFields
+			return err
+		}
+	}
+
+	// Update the cache variables
+	c.data = data
+	c.fields = fields
+	c.ft = ft
+	c.schema = s
+	c.refreshQueries()
+	c.events.publish(EventSchema, "", map[string]any{"schema": s.copy()}, nil, nil)
+
+	// Return no error
+	return nil
+}
+
+// GetSchema is a method of the Cache struct that returns the schema of the cache records.
+// This method is thread-safe.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - *Schema: A copy of the schema, or nil if no schema is set.
+func (c *Cache) GetSchema() *Schema {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.schema.copy()
+}
+
+// copy is a method of the Schema struct that returns a copy of the schema.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - *Schema: The copy, or nil if the schema is nil.
+func (s *Schema) copy() *Schema {
+	if s == nil {
+		return nil
+	}
+	var fields []SchemaField = make([]SchemaField, len(s.Fields))
+	copy(fields, s.Fields)
+	return &Schema{Fields: fields}
+}
+
+// validate is a method of the Schema struct that verifies the field declarations.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - error: If a field has no name, is declared twice, has an invalid type, or has an invalid analyzer.
+func (s *Schema) validate() error {
+	var names map[string]bool = make(map[string]bool, len(s.Fields))
+	for _, f := range s.Fields {
+		if len(f.Name) == 0 {
+			return errors.New("schema field with no name")
+		} else if names[f.Name] {
+			return fmt.Errorf("field %s: declared more than once", f.Name)
+		}
+		names[f.Name] = true
+
+		// Verify the type
+		switch f.Type {
+		case FieldText, FieldKeyword:
+		case FieldInt, FieldFloat, FieldBool, FieldTime:
+			if f.FullText {
+				return fmt.Errorf("field %s: %s fields can't be full-text", f.Name, f.Type)
+			}
+		default:
+			return fmt.Errorf("field %s: invalid type %s", f.Name, f.Type)
+		}
+
+		// Verify the analyzer
+		if len(f.Analyzer) == 0 {
+			continue
+		} else if !f.FullText {
+			return fmt.Errorf("field %s: analyzer set on a field that isn't full-text", f.Name)
+		} else if _, err := analyzer(f.Analyzer); err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// apply is a method of the Schema struct that validates a flattened record, and converts its values into the field types.
+// The metadata of the declared full-text fields is set in the fields map. The record and fields are modified in place.
+// If the schema is nil, the record is left as-is.
+//
+// Parameters:
+//   - record (map[string]any): The flattened record.
+//   - fields (map[string]Field): The metadata of the record fields.
+//
+// Returns:
+//   - error: If a required field is missing, or a value doesn't match its field type. The error names the field.
+func (s *Schema) apply(record map[string]any, fields map[string]Field) error {
+	if s == nil {
+		return nil
+	}
+	for _, sf := range s.Fields {
+		var value, ok = record[sf.Name]
+		if !ok || value == nil {
+			// Fields that aren't stored are missing from the stored records
+			if f, ok := fields[sf.Name]; sf.Required && (!ok || f.Stored) {
+				return fmt.Errorf("field %s: required", sf.Name)
+			}
+			continue
+		}
+
+		// Convert the value
+		if v, err := sf.convert(value); err != nil {
+			return fmt.Errorf("field %s: %w", sf.Name, err)
+		} else {
+			record[sf.Name] = v
+		}
+
+		// Set the full-text metadata
+		if !sf.FullText {
+			delete(fields, sf.Name)
+			continue
+		}
+		var f, exists = fields[sf.Name]
+		if !exists {
+			f.Stored = true
+		}
+		f.FullText = true
+		f.Analyzer = sf.analyzer()
+		fields[sf.Name] = f
+	}
+	return nil
+}
+
+// analyzer is a method of the SchemaField struct that returns the name of the analyzer used by the field.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - string: The analyzer set in the field, or the default analyzer of the field type.
+func (sf SchemaField) analyzer() string {
+	if len(sf.Analyzer) > 0 {
+		return sf.Analyzer
+	} else if sf.Type == FieldKeyword {
+		return KeywordAnalyzer
+	}
+	return StandardAnalyzer
+}
+
+// convert is a method of the SchemaField struct that converts a value into the go type of the field.
+//
+// Parameters:
+//   - value (any): The value to convert.
+//
+// Returns:
+//   - any: The converted value.
+//   - error: If the value doesn't match the field type.
+func (sf SchemaField) convert(value any) (any, error) {
+	switch sf.Type {
+	case FieldText, FieldKeyword:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case FieldBool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case FieldInt:
+		if v, ok := toFloat(value); ok && v == math.Trunc(v) {
+			return int(v), nil
+		} else if ok {
+			return nil, fmt.Errorf("expected int, got %v", value)
+		}
+	case FieldFloat:
+		if v, ok := toFloat(value); ok {
+			return v, nil
+		}
+	case FieldTime:
+		switch v := value.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err != nil {
+				return nil, fmt.Errorf("expected an RFC 3339 time, got %s", v)
+			} else {
+				return t, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("expected %s, got %T", sf.Type, value)
+}
+
+// toFloat is a function that converts a number of any go type into a float64.
+//
+// Parameters:
+//   - value (any): The value to convert.
+//
+// Returns:
+//   - float64: The converted value.
+//   - bool: Whether the value is a number.
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f, true
+		}
+	}
+	return 0, false
+}
@@ -37,7 +37,7 @@ func (c *Cache) Search(sp SearchParams) ([]map[string]any, error) {
 	sp.Query = strings.ToLower(sp.Query)
 
 	// Search for the query
-	return c.search(sp), nil
+	return c.stats.searched(c.search(sp)), nil
 }
 
 // search is a method of the Cache struct that searches for a query by splitting the query into separate words and returning the search results.
@@ -82,9 +82,7 @@ func (c *Cache) search(sp SearchParams) []map[string]any {
 			}
 		}*/
 		if temp, ok := indices.(int); ok {
-			return []map[string]any{
-				c.data[c.ft.indices[temp]],
-			}
+			return c.appendRecord([]map[string]any{}, c.ft.indices[temp])
 		}
 		// smallestData = indices.([]int)
 		smallest = len(indices.([]int))
@@ -102,9 +100,7 @@ func (c *Cache) search(sp SearchParams) []map[string]any {
 				}
 			}*/
 			if index, ok := indices.(int); ok {
-				return []map[string]any{
-					c.data[c.ft.indices[index]],
-				}
+				return c.appendRecord([]map[string]any{}, c.ft.indices[index])
 			}
 			/*if l := len(indices.([]int)); l < len(smallestData) {
 				smallestData = indices.([]int)
@@ -129,11 +125,12 @@ func (c *Cache) search(sp SearchParams) []map[string]any {
 	}*/
 	var keys []int = c.ft.storage[words[smallestIndex]].([]int)
 	for i := 0; i < len(keys); i++ {
-		for _, value := range c.data[c.ft.indices[keys[i]]] {
+		var record map[string]any = c.record(c.ft.indices[keys[i]])
+		for _, value := range record {
 			// Check if the value contains the query
 			if v, ok := value.(string); ok {
 				if strings.Contains(strings.ToLower(v), sp.Query) {
-					result = append(result, c.data[c.ft.indices[keys[i]]])
+					result = append(result, record)
 				}
 			}
 		}
@@ -37,7 +37,7 @@ func (c Cache) SearchOneWord(sp SearchParams) ([]map[string]any, error) {
 	}
 
 	// Search the data
-	return c.searchOneWord(sp), nil
+	return c.stats.searched(c.searchOneWord(sp)), nil
 }
 
 // searchOneWord searches for a single word in the FullText struct's data and returns a list of maps containing the search results.
@@ -78,7 +78,7 @@ func (c *Cache) searchOneWord(sp SearchParams) []map[string]any {
 			if _, ok := alreadyAdded[index]; ok {
 				continue
 			}
-			result = append(result, c.data[c.ft.indices[index]])
+			result = c.appendRecord(result, c.ft.indices[index])
 			alreadyAdded[index] = 0
 			continue
 		}
@@ -90,7 +90,7 @@ func (c *Cache) searchOneWord(sp SearchParams) []map[string]any {
 			}
 
 			// Else, append the index to the result
-			result = append(result, c.data[c.ft.indices[indices[j]]])
+			result = c.appendRecord(result, c.ft.indices[indices[j]])
 			alreadyAdded[indices[j]] = 0
 		}
 	}
@@ -116,7 +116,7 @@ func (c *Cache) searchOneWordStrict(result []map[string]any, sp SearchParams) []
 
 	// If there's only one result
 	if v, ok := c.ft.storage[sp.Query].(int); ok {
-		return []map[string]any{c.data[c.ft.indices[v]]}
+		return c.appendRecord(result, c.ft.indices[v])
 	}
 
 	// Loop through the indices
@@ -128,7 +128,7 @@ func (c *Cache) searchOneWordStrict(result []map[string]any, sp SearchParams) []
 			index int    = c.ft.storage[sp.Query].([]int)[i]
 			key   string = c.ft.indices[index]
 		)
-		result = append(result, c.data[key])
+		result = c.appendRecord(result, key)
 	}
 
 	// Return the result
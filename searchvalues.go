@@ -38,7 +38,7 @@ func (c *Cache) SearchValues(sp SearchParams) ([]map[string]any, error) {
 	defer c.mutex.RUnlock()
 
 	// Search the data
-	return c.searchValues(sp), nil
+	return c.stats.searched(c.searchValues(sp)), nil
 }
 
 // searchValues searches for all records containing the given query in the specified schema with a limit of results to return.
@@ -54,7 +54,12 @@ func (c *Cache) searchValues(sp SearchParams) []map[string]any {
 	var result []map[string]any = []map[string]any{}
 
 	// Iterate over the query result
-	for _, item := range c.data {
+	for _, raw := range c.data {
+		var item, err = c.materialize(raw)
+		if err != nil {
+			continue
+		}
+
 		// Iterate over the keys and values for the data for that index
 		for key, value := range item {
 			switch {
@@ -29,7 +29,7 @@ func (c *Cache) SearchWithKey(sp SearchParams) ([]map[string]any, error) {
 	defer c.mutex.RUnlock()
 
 	// Search the data
-	return c.searchWithKey(sp), nil
+	return c.stats.searched(c.searchWithKey(sp)), nil
 }
 
 // searchWithKey searches for all records containing the given query in the specified key column with a limit of results to return.
@@ -44,7 +44,12 @@ func (c *Cache) searchWithKey(sp SearchParams) []map[string]any {
 	var result []map[string]any = []map[string]any{}
 
 	// Iterate over the query result
-	for _, item := range c.data {
+	for _, raw := range c.data {
+		var item, err = c.materialize(raw)
+		if err != nil {
+			continue
+		}
+
 		for _, v := range item {
 			if len(result) >= sp.Limit {
 				return result
@@ -19,7 +19,8 @@ func (c *Cache) Set(key string, value map[string]any) error {
 
 // set is a method of the Cache struct that sets a value in the cache for the specified key.
 // This function is not thread-safe, and should only be called from an exported function.
-// If fullText is true, set the value in the full-text cache as well.
+// The full-text values are set in the full-text cache as well, if it's initialized.
+// The provided value is not modified.
 //
 // Parameters:
 //   - key: A string representing the key to set the value for.
@@ -32,15 +33,32 @@ func (c *Cache) set(key string, value map[string]any) error {
 		return fmt.Errorf("full-text cache key already exists (%s). delete it before setting it another value", key)
 	}
 
+	// Separate the full-text fields metadata from the values
+	var record, fields, err = flatten(value, c.schema)
+	if err != nil {
+		return err
+	}
+
+	// Compress the value before it's indexed, so that a compression
+	// error doesn't leave the key in the FT cache
+	var v, compressed map[string]any = stored(record, fields), nil
+	if compressed, err = c.compression.compress(v); err != nil {
+		return err
+	}
+
 	// Update the value in the FT cache
 	if c.ft != nil {
-		if err := c.ftSet(key, value); err != nil {
+		if err := c.ftSet(key, record, fields); err != nil {
 			return err
 		}
 	}
 
 	// Update the value in the cache
-	c.data[key] = value
+	c.data[key] = compressed
+	if fields != nil {
+		c.fields[key] = fields
+	}
+	c.events.publish(EventSet, key, v, c.percolate(key, record, fields), fields)
 
 	// Return nil for no error
 	return nil
@@ -51,24 +69,15 @@ func (c *Cache) set(key string, value map[string]any) error {
 //
 // Parameters:
 //   - key: A string representing the key to set the value for.
-//   - value: A map[string]any representing the value to set.
+//   - record: The flattened record to set.
+//   - fields: The metadata of the record fields.
 //
 // Returns:
 //   - An error if the full-text storage limit or byte-size limit is reached. Otherwise, nil.
-func (c *Cache) ftSet(key string, value map[string]any) error {
+func (c *Cache) ftSet(key string, record map[string]any, fields map[string]Field) error {
 	var ts *TempStorage = NewTempStorage(c.ft)
-	for k, v := range value {
-		if ftv := WFTGetValue(v); len(ftv) == 0 {
-			continue
-		} else {
-			// Update the value
-			value[k] = ftv
-
-			// Insert the value in the temp storage
-			if err := ts.insert(c.ft, key, ftv); err != nil {
-				return err
-			}
-		}
+	if err := ts.insertFields(c.ft, key, record, fields); err != nil {
+		return err
 	}
 
 	// Iterate over the temp storage and set the values with len 1 to int
@@ -0,0 +1,142 @@
+package hermes
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a struct with the counters of a cache, for monitoring.
+// The counters are updated while the cache is locked for the changes and searches,
+// so they can be read with cache.Stats() without locking the cache.
+type Stats struct {
+	// The number of records in the cache
+	Keys int
+	// The number of words in the full-text index, or 0 if it's not initialized
+	Words int
+	// The number of searches, and the total number of results they returned
+	Searches   uint64
+	SearchHits uint64
+	// The number of times the cache was locked, and the total time spent waiting for the lock
+	LockAcquisitions uint64
+	LockWait         time.Duration
+	// The number of records that were evicted or expired. Always 0, since the cache never evicts
+	// or expires records, so that the monitoring can rely on the counters if it ever does
+	Evictions   uint64
+	Expirations uint64
+}
+
+// cacheStats is a struct that holds the counters of a cache.
+//
+// Fields:
+//   - keys (atomic.Int64): The number of records, updated before the cache is unlocked.
+//   - words (atomic.Int64): The number of words in the full-text index, updated before the cache is unlocked.
+//   - searches (atomic.Uint64): The number of searches.
+//   - hits (atomic.Uint64): The total number of search results.
+type cacheStats struct {
+	keys     atomic.Int64
+	words    atomic.Int64
+	searches atomic.Uint64
+	hits     atomic.Uint64
+}
+
+// lock is a struct that wraps the RWMutex of a cache. It measures how long the callers wait for the lock,
+// and calls a function before the write lock is released, so that the counters of the cache are updated
+// by the goroutine that changed the cache.
+//
+// Fields:
+//   - RWMutex (sync.RWMutex): The mutex.
+//   - acquisitions (atomic.Uint64): The number of times the lock was acquired.
+//   - wait (atomic.Int64): The total time spent waiting for the lock, in nanoseconds.
+//   - unlocking (func()): The function called before the write lock is released. Can be nil.
+type lock struct {
+	sync.RWMutex
+	acquisitions atomic.Uint64
+	wait         atomic.Int64
+	unlocking    func()
+}
+
+// Lock is a method of the lock struct that locks the mutex for writing, and measures the wait.
+func (l *lock) Lock() {
+	var start time.Time = time.Now()
+	l.RWMutex.Lock()
+	l.waited(start)
+}
+
+// Unlock is a method of the lock struct that calls the unlocking function, then unlocks the mutex.
+func (l *lock) Unlock() {
+	if l.unlocking != nil {
+		l.unlocking()
+	}
+	l.RWMutex.Unlock()
+}
+
+// RLock is a method of the lock struct that locks the mutex for reading, and measures the wait.
+func (l *lock) RLock() {
+	var start time.Time = time.Now()
+	l.RWMutex.RLock()
+	l.waited(start)
+}
+
+// waited is a method of the lock struct that adds the time since a lock call to the wait.
+//
+// Parameters:
+//   - start (time.Time): When the lock was called.
+//
+// Returns:
+//   - None
+func (l *lock) waited(start time.Time) {
+	l.acquisitions.Add(1)
+	l.wait.Add(int64(time.Since(start)))
+}
+
+// newLock is a function that creates the lock of a cache, which updates the key and word counters
+// of the cache before the cache is unlocked.
+//
+// Parameters:
+//   - c (*Cache): The cache.
+//
+// Returns:
+//   - *lock: A pointer to the new lock.
+func newLock(c *Cache) *lock {
+	return &lock{
+		unlocking: func() {
+			c.stats.keys.Store(int64(len(c.data)))
+			if c.ft != nil {
+				c.stats.words.Store(int64(len(c.ft.storage)))
+			} else {
+				c.stats.words.Store(0)
+			}
+		},
+	}
+}
+
+// Stats is a method of the Cache struct that returns the counters of the cache.
+// The cache isn't locked, so this method can be called often without slowing down the cache.
+// This method is thread-safe.
+//
+// Returns:
+//   - Stats: The counters of the cache.
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Keys:             int(c.stats.keys.Load()),
+		Words:            int(c.stats.words.Load()),
+		Searches:         c.stats.searches.Load(),
+		SearchHits:       c.stats.hits.Load(),
+		LockAcquisitions: c.mutex.acquisitions.Load(),
+		LockWait:         time.Duration(c.mutex.wait.Load()),
+	}
+}
+
+// searched is a method of the cacheStats struct that counts a search and its results.
+//
+// Parameters:
+//   - result ([]map[string]any): The results of the search.
+//
+// Returns:
+//   - []map[string]any: The results, so that the method can wrap the return value of a search.
+func (s *cacheStats) searched(result []map[string]any) []map[string]any {
+	s.searches.Add(1)
+	s.hits.Add(uint64(len(result)))
+	return result
+}
@@ -108,8 +108,11 @@ func (ts *TempStorage) update(ft *FullText, words []string, cacheKey string) {
 			continue
 		}
 		if temp, ok := ts.data[word]; !ok {
-			ts.data[word] = []int{ts.index}
+			ts.data[word] = []int{ts.keys[cacheKey]}
 		} else if v, ok := temp.([]int); !ok {
+			if temp.(int) == ts.keys[cacheKey] {
+				continue
+			}
 			ts.data[word] = []int{temp.(int), ts.keys[cacheKey]}
 		} else {
 			if utils.SliceContains(v, ts.keys[cacheKey]) {
@@ -134,6 +137,49 @@ func (ts *TempStorage) updateKeys(cacheKey string) {
 	}
 }
 
+// remove is a method of the TempStorage struct that removes cache keys and their words from the temp storage.
+// The words that no longer have a cache key are removed as well.
+// Parameters:
+//   - cacheKeys ([]string): The cache keys to remove.
+//
+// Returns:
+//   - None.
+func (ts *TempStorage) remove(cacheKeys []string) {
+	var removed map[int]bool = make(map[int]bool, len(cacheKeys))
+	for _, cacheKey := range cacheKeys {
+		if index, ok := ts.keys[cacheKey]; ok {
+			removed[index] = true
+			delete(ts.keys, cacheKey)
+			delete(ts.indices, index)
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+
+	// Loop through the words
+	for word, temp := range ts.data {
+		switch v := temp.(type) {
+		case int:
+			if removed[v] {
+				delete(ts.data, word)
+			}
+		case []int:
+			var kept []int = make([]int, 0, len(v))
+			for _, index := range v {
+				if !removed[index] {
+					kept = append(kept, index)
+				}
+			}
+			if len(kept) == 0 {
+				delete(ts.data, word)
+			} else if len(kept) < len(v) {
+				ts.data[word] = kept
+			}
+		}
+	}
+}
+
 // mergeKeys is a method of the TempStorage struct that merges all keys that contain subkeys into a single key.
 // Parameters:
 //   - None.
@@ -169,25 +215,20 @@ func (ts *TempStorage) mergeKeys() {
 	}
 }
 
-// insertWords is a method of the TempStorage struct that inserts data into the temp storage.
+// insert is a method of the TempStorage struct that inserts the words of a value into the temp storage.
 // Parameters:
 //   - ft (*FullText): A pointer to the FullText object to check the storage limit against.
 //   - cacheKey (string): A string representing the cache key to insert.
-//   - ftv (string): A string representing the value to insert.
+//   - words ([]string): The words of the value, as returned by its analyzer.
 //
 // Returns:
 //   - (error): An error if the storage limit has been reached, nil otherwise.
-func (ts *TempStorage) insert(ft *FullText, cacheKey string, ftv string) error {
+func (ts *TempStorage) insert(ft *FullText, cacheKey string, words []string) error {
 	// Set the cache key in the temp storage keys
 	ts.updateKeys(cacheKey)
 
-	// Clean the string value
-	ftv = strings.TrimSpace(ftv)
-	ftv = utils.RemoveDoubleSpaces(ftv)
-	ftv = strings.ToLower(ftv)
-
 	// Loop through the words
-	for _, word := range strings.Split(ftv, " ") {
+	for _, word := range words {
 		if len(word) == 0 {
 			continue
 		} else if len(word) < ft.minWordLength {
@@ -196,14 +237,37 @@ func (ts *TempStorage) insert(ft *FullText, cacheKey string, ftv string) error {
 			return err
 		}
 
-		// Trim the word
-		word = utils.TrimNonAlphaNum(word)
-		var words []string = utils.SplitByAlphaNum(word)
-
 		// Update the temp storage
-		ts.update(ft, words, cacheKey)
+		ts.update(ft, []string{word}, cacheKey)
 	}
 
 	// Return no error
 	return nil
 }
+
+// insertFields is a method of the TempStorage struct that inserts the full-text fields of a record into the temp storage.
+// Each field is split into words by the analyzer in its metadata.
+// Parameters:
+//   - ft (*FullText): A pointer to the FullText object to check the storage limit against.
+//   - cacheKey (string): A string representing the cache key to insert.
+//   - record (map[string]any): The flattened record.
+//   - fields (map[string]Field): The metadata of the record fields.
+//
+// Returns:
+//   - (error): An error if an analyzer isn't registered, or the storage limit has been reached, nil otherwise.
+func (ts *TempStorage) insertFields(ft *FullText, cacheKey string, record map[string]any, fields map[string]Field) error {
+	for name, f := range fields {
+		var value, ok = record[name].(string)
+		if !ok || !f.FullText {
+			continue
+		}
+
+		// Split the value into words and insert them
+		if a, err := analyzer(f.Analyzer); err != nil {
+			return err
+		} else if err := ts.insert(ft, cacheKey, a(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
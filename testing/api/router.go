@@ -8,7 +8,7 @@ import (
 
 func main() {
 	app := fiber.New()
-	cache := hermes.InitCache()
-	api.SetRoutes(app, cache)
+	collections := hermes.InitCollections()
+	api.SetRoutes(app, collections)
 	app.Listen(":3000")
 }
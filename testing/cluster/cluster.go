@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/cloud/cluster"
+)
+
+func main() {
+	// Start a cluster of three nodes. The log is compacted after a few entries, so the
+	// nodes that are added later get the collections from a snapshot
+	var lc, err = cluster.NewLocalCluster(3, cluster.Config{SnapshotThreshold: 4, TrailingLogs: 2})
+	if err != nil {
+		panic(err)
+	}
+	defer lc.Shutdown()
+	var ctx context.Context = context.Background()
+	leader, _ := lc.Leader(time.Second)
+	fmt.Println("leader:", leader.ID())
+
+	// Write through the leader
+	if err := leader.FTInit(ctx, "", cluster.FTSettings{MaxSize: -1, MaxBytes: -1, MinWordLength: 3}); err != nil {
+		panic(err)
+	}
+	var c *hermes.Cache = hermes.InitCache()
+	for i, name := range []string{"Tristan Simpson", "Computer Person", "Tristan Computer"} {
+		if err := leader.Set(ctx, "", fmt.Sprintf("user_%d", i), map[string]any{"name": c.WithFT(name)}); err != nil {
+			panic(err)
+		}
+	}
+	fmt.Println("duplicate key:", leader.Set(ctx, "", "user_0", map[string]any{"name": "again"}))
+
+	// A follower rejects the writes, and serves the stale reads
+	for _, n := range lc.Nodes() {
+		if n != leader {
+			fmt.Println("follower write:", n.Set(ctx, "", "user_9", map[string]any{}))
+			time.Sleep(100 * time.Millisecond)
+			_ = n.Read(ctx, cluster.ReadStale, func(cs *hermes.Collections) error {
+				var c, _ = cs.Get("")
+				var results, _ = c.Search(hermes.SearchParams{Query: "tristan", Limit: 10})
+				fmt.Println("follower search:", n.ID(), len(results))
+				return nil
+			})
+			break
+		}
+	}
+
+	// Stop the leader. The other nodes elect a new one, and keep the writes
+	lc.Stop(leader)
+	if leader, err = lc.Leader(5 * time.Second); err != nil {
+		panic(err)
+	}
+	fmt.Println("new leader:", leader.ID())
+	if err := leader.Replace(ctx, "", "user_1", map[string]any{"name": c.WithFT("Tristan Person")}); err != nil {
+		panic(err)
+	}
+	var bio = map[string]any{"name": c.WithFT("Computer Tristan"), "bio": c.WithFT("Quantum Physicist").NotStored()}
+	if err := leader.Set(ctx, "", "user_3", bio); err != nil {
+		panic(err)
+	}
+	_ = leader.Read(ctx, cluster.ReadLeader, func(cs *hermes.Collections) error {
+		var c, _ = cs.Get("")
+		var results, _ = c.Search(hermes.SearchParams{Query: "tristan", Limit: 10})
+		fmt.Println("leader search:", len(results))
+		return nil
+	})
+
+	// Add a node. It's restored from a snapshot of the leader
+	if err := leader.Snapshot(); err != nil {
+		panic(err)
+	}
+	added, err := lc.Add(ctx)
+	if err != nil {
+		panic(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	var cache, _ = added.Collections().Get("")
+	var restored, _ = added.Status(ctx)
+	fmt.Println("added node:", added.ID(), cache.Length(), cache.FTIsInitialized(), restored.SnapshotIndex)
+
+	// The restored node returns the same search results as the leader, including the fields that aren't stored
+	for _, query := range []string{"tristan", "quantum"} {
+		var params = hermes.SearchParams{Query: query, Limit: 10}
+		var results, _ = cache.Search(params)
+		_ = leader.Read(ctx, cluster.ReadLeader, func(cs *hermes.Collections) error {
+			var c, _ = cs.Get("")
+			var expected, _ = c.Search(params)
+			fmt.Println("restored search:", query, len(results), same(results, expected))
+			return nil
+		})
+	}
+
+	// Remove a follower, and list the members
+	for _, n := range lc.Nodes() {
+		if n != leader && n != added {
+			if err := lc.Remove(ctx, n); err != nil {
+				panic(err)
+			}
+			fmt.Println("removed:", n.ID())
+			break
+		}
+	}
+	var status, _ = leader.Status(ctx)
+	for _, s := range status.Servers {
+		fmt.Println("member:", s.ID, s.Voter, s.Leader)
+	}
+}
+
+// Whether the search results are the same, in any order. The order of the results
+// follows the order the records were indexed in, which differs on a restored node
+func same(a, b []map[string]any) bool {
+	for _, results := range [][]map[string]any{a, b} {
+		sort.Slice(results, func(i, j int) bool { return fmt.Sprint(results[i]) < fmt.Sprint(results[j]) })
+	}
+	return reflect.DeepEqual(a, b)
+}
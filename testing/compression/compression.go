@@ -5,8 +5,10 @@ import (
 	"strings"
 	"time"
 
+	hermes "github.com/realTristan/hermes"
 	gzip "github.com/realTristan/hermes/compression/gzip"
 	"github.com/realTristan/hermes/compression/zlib"
+	"github.com/realTristan/hermes/compression/zstd"
 	utils "github.com/realTristan/hermes/utils"
 )
 
@@ -14,6 +16,29 @@ func main() {
 	var v string = strings.Repeat("computer", 100)
 	TestGzip(v)
 	TestZlib(v)
+	TestCache(v)
+}
+
+// Test storing the cache values compressed.
+func TestCache(v string) {
+	fmt.Println("cache")
+	var cache *hermes.Cache = hermes.InitCache()
+	cache.FTInit(-1, -1, 3)
+	if err := cache.SetCompression(zstd.Codec{}, hermes.CompressDocuments); err != nil {
+		panic(err)
+	}
+	cache.Set("key", map[string]any{
+		"name":        cache.WithFT("computer science"),
+		"description": v,
+		"id":          1,
+	})
+	var st time.Time = time.Now()
+	var res, _ = cache.Search(hermes.SearchParams{
+		Query: "computer",
+		Limit: 10,
+	})
+	fmt.Println(time.Since(st))
+	fmt.Println(res[0]["name"], res[0]["id"], len(res[0]["description"].(string)))
 }
 
 // Test the zlib compression and decompression functions.
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/disk"
+)
+
+func main() {
+	var dir string = os.TempDir() + "/hermes_index"
+
+	// Build the on-disk index
+	var st time.Time = time.Now()
+	if err := disk.WriteJson("../data/data_hash.json", dir, 3); err != nil {
+		panic(err)
+	}
+	fmt.Println("write:", time.Since(st))
+
+	// Open the index
+	var idx, err = disk.Open(dir)
+	if err != nil {
+		panic(err)
+	}
+	defer idx.Close()
+
+	// Search the index
+	st = time.Now()
+	var res, _ = idx.Search(hermes.SearchParams{
+		Query:  "computer",
+		Limit:  100,
+		Strict: false,
+	})
+	fmt.Println("search:", time.Since(st), len(res))
+
+	// Get a document
+	fmt.Println(idx.Get("f33ac682823741e1d079afd766b685e71be228eb"))
+}
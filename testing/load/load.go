@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	hermes "github.com/realTristan/hermes"
+)
+
+func main() {
+	// Stream the hash format
+	var cache *hermes.Cache = hermes.InitCache()
+	var st time.Time = time.Now()
+	if err := cache.FTInitWithJson("../data/data_hash.json", -1, -1, 3); err != nil {
+		panic(err)
+	}
+	fmt.Println("hash:", time.Since(st), cache.Length())
+
+	// Stream the array format, and report the progress
+	cache = hermes.InitCache()
+	var f, err = os.Open("../data/data_array.json")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	if err := cache.FTInitWithReader(f, -1, -1, 3, func(loaded int) {
+		if loaded%1000 == 0 {
+			fmt.Println("loaded:", loaded)
+		}
+	}); err != nil {
+		panic(err)
+	}
+	fmt.Println("array:", cache.Length())
+
+	// Load ndjson records
+	var ndjson *bytes.Buffer = bytes.NewBufferString(
+		`{"user_1": {"name": {"$hermes.full_text": true, "$hermes.value": "Tristan Simpson"}}}` + "\n" +
+			`{"user_2": {"name": {"$hermes.full_text": true, "$hermes.value": "Computer Person"}}}` + "\n",
+	)
+	if err := cache.LoadNDJSON(ndjson, nil); err != nil {
+		panic(err)
+	}
+	fmt.Println(cache.Search(hermes.SearchParams{Query: "tristan", Limit: 10}))
+}
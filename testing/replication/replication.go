@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	hermes "github.com/realTristan/hermes"
+	"github.com/realTristan/hermes/cloud/replication"
+)
+
+func main() {
+	// Serve the replication stream of the leader
+	var leader *hermes.Collections = hermes.InitCollections()
+	var app *fiber.App = fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Post(replication.Path, replication.NewLeader(leader).Handler)
+	var ln, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	go app.Listener(ln)
+	defer app.Shutdown()
+
+	// Write to the leader. The description is indexed, but not stored in the record
+	c, _ := leader.Get("")
+	if err := c.FTInit(-1, -1, 3); err != nil {
+		panic(err)
+	}
+	set(c, 0, 3)
+
+	// Start the follower, and wait until it received the snapshot of the leader
+	var follower *hermes.Collections = hermes.InitCollections()
+	f, err := replication.NewFollower(follower, replication.Config{URL: "http://" + ln.Addr().String()})
+	if err != nil {
+		panic(err)
+	}
+	var stop = run(f)
+	wait(func() bool { return f.Synced() })
+	fmt.Println("synced:", f.Stats().Snapshots, "snapshot(s)")
+	search(c, follower)
+
+	// Disconnect the follower, and write to the leader while it's away
+	stop()
+	set(c, 3, 6)
+	c.Delete("user_0")
+	fmt.Println("disconnected:", f.Stats().Connected)
+
+	// Resume. The follower only receives the changes it missed, and catches up with the leader
+	stop = run(f)
+	defer stop()
+	wait(func() bool {
+		var stats replication.Stats = f.Stats()
+		return stats.Connected && stats.Changes >= 4 && stats.Lag[""] == 0
+	})
+	var stats replication.Stats = f.Stats()
+	fmt.Println("resumed:", stats.Snapshots, "snapshot(s),", stats.Changes, "change(s), lag", stats.Lag[""])
+	search(c, follower)
+}
+
+// Set the users from..to-1 in the leader
+func set(c *hermes.Cache, from, to int) {
+	for i := from; i < to; i++ {
+		var record = map[string]any{
+			"name":        c.WithFT(fmt.Sprintf("Tristan %d", i)),
+			"description": c.WithFT("Computer Person").NotStored(),
+		}
+		if err := c.Set(fmt.Sprintf("user_%d", i), record); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Run the follower until the returned function is called, which waits for it to stop
+func run(f *replication.Follower) func() {
+	var ctx, cancel = context.WithCancel(context.Background())
+	var done = make(chan struct{})
+	go func() {
+		f.Run(ctx)
+		close(done)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// Wait until the condition is true, for at most ten seconds
+func wait(cond func() bool) {
+	for start := time.Now(); !cond(); time.Sleep(50 * time.Millisecond) {
+		if time.Since(start) > 10*time.Second {
+			panic("timeout")
+		}
+	}
+}
+
+// Compare the search results of the non-stored field on the leader and the follower
+func search(leader *hermes.Cache, follower *hermes.Collections) {
+	var c, _ = follower.Get("")
+	var params = hermes.SearchParams{Query: "computer", Limit: 10}
+	var expected, _ = leader.Search(params)
+	var results, _ = c.Search(params)
+	fmt.Println("search:", len(results), "result(s) on the follower,", len(expected), "on the leader")
+}
@@ -7,12 +7,12 @@ import (
 )
 
 func main() {
-	// Cache and fiber app
-	cache := hermes.InitCache()
+	// Collections and fiber app
+	collections := hermes.InitCollections()
 	app := fiber.New()
 
 	// Set the router
-	Socket.SetRouter(app, cache)
+	Socket.SetRouter(app, collections)
 
 	// Listen on port 3000
 	app.Listen(":3000")
@@ -19,7 +19,9 @@ func (c *Cache) Values() []map[string]any {
 func (c *Cache) values() []map[string]any {
 	values := make([]map[string]any, 0, len(c.data))
 	for _, value := range c.data {
-		values = append(values, value)
+		if v, err := c.materialize(value); err == nil {
+			values = append(values, v)
+		}
 	}
 	return values
 }
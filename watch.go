@@ -0,0 +1,290 @@
+package hermes
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// EventType is the type of a change event.
+type EventType string
+
+// The types of the change events.
+const (
+	// A record was set. The event contains the key and the record
+	EventSet EventType = "set"
+	// A record was deleted. The event contains the key
+	EventDelete EventType = "delete"
+	// The cache was cleaned
+	EventClean EventType = "clean"
+	// The full-text index was initialized. The event value contains the full-text settings
+	EventFTInit EventType = "ft.init"
+	// The full-text index was cleaned
+	EventFTClean EventType = "ft.clean"
+	// A full-text setting was changed. The event value contains the full-text settings
+	EventFTSettings EventType = "ft.settings"
+	// The schema was set or removed. The event value contains the schema under the "schema" key, which is nil if it was removed
+	EventSchema EventType = "schema"
+	// Events were lost before the watch started, because they're no longer buffered.
+	// The consumer should reload the cache contents
+	EventLost EventType = "lost"
+)
+
+// eventBufferSize is the number of events that are kept, so that watchers can resume from a sequence number.
+const eventBufferSize int = 4096
+
+// watchBufferSize is the default size of the channel returned by cache.Watch().
+const watchBufferSize int = 256
+
+// Event is a struct that represents a change in the cache.
+// The records in the events are shared with the cache, and must not be modified.
+type Event struct {
+	// The sequence number of the event. The first event has the sequence number 1
+	Seq uint64 `json:"seq"`
+	// The type of the event
+	Type EventType `json:"type"`
+	// The key of the record, for set and delete events
+	Key string `json:"key,omitempty"`
+	// The record for set events, the full-text settings for ft.init and ft.settings events, or the schema for schema events
+	Value map[string]any `json:"value,omitempty"`
+	// The ids of the registered queries that the record matches, for set events
+	Queries []string `json:"queries,omitempty"`
+	// The metadata of the full-text fields of the record for set events, so that the record can be set again
+	// in another cache with the same full-text fields. It's shared with the cache, and must not be modified
+	Fields map[string]Field `json:"-"`
+}
+
+// WatchFilter is a struct that selects the events that are delivered by cache.Watch().
+type WatchFilter struct {
+	// Only deliver the set and delete events of keys with this prefix. The other events are always delivered
+	Prefix string
+	// If set, the buffered events with a greater sequence number are delivered before the new events
+	From uint64
+	// The size of the channel. Defaults to 256
+	Buffer int
+}
+
+// match is a method of the WatchFilter struct that returns whether an event should be delivered.
+//
+// Parameters:
+//   - e (Event): The event.
+//
+// Returns:
+//   - bool: Whether the event matches the filter.
+func (f WatchFilter) match(e Event) bool {
+	switch e.Type {
+	case EventSet, EventDelete:
+		return strings.HasPrefix(e.Key, f.Prefix)
+	}
+	return true
+}
+
+// eventLog is a struct that keeps the recent events of a cache, and delivers the new events to the watchers.
+//
+// Fields:
+//   - mutex (*sync.Mutex): A Mutex that guards access to the log.
+//   - seq (uint64): The sequence number of the last event.
+//   - ring ([]Event): The buffered events. The event with the sequence number n is at ring[n % len(ring)].
+//   - watchers (map[*watcher]bool): The active watchers.
+type eventLog struct {
+	mutex    *sync.Mutex
+	seq      uint64
+	ring     []Event
+	watchers map[*watcher]bool
+}
+
+// watcher is a struct that represents a consumer of the events.
+//
+// Fields:
+//   - ch (chan Event): The channel the events are delivered to.
+//   - filter (WatchFilter): The filter of the events.
+//   - done (chan struct{}): A channel that's closed when the watcher is removed.
+type watcher struct {
+	ch     chan Event
+	filter WatchFilter
+	done   chan struct{}
+}
+
+// newEventLog is a function that creates a new empty event log.
+//
+// Returns:
+//   - *eventLog: A pointer to the new event log.
+func newEventLog() *eventLog {
+	return &eventLog{
+		mutex:    &sync.Mutex{},
+		ring:     make([]Event, eventBufferSize),
+		watchers: make(map[*watcher]bool),
+	}
+}
+
+// Watch is a method of the Cache struct that returns a channel of the changes made to the cache.
+// The channel is closed when the context is done. If the consumer doesn't read the events fast enough
+// and the channel is full, the watcher is dropped and the channel is closed. The consumer can then call
+// Watch again with filter.From set to the sequence number of the last event it received.
+// If the events after filter.From are no longer buffered, or filter.From is greater than the sequence number of
+// the last change (for example after the cache was restored), an EventLost event is delivered first.
+// This method is thread-safe.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the watch.
+//   - filter (WatchFilter): The filter of the events.
+//
+// Returns:
+//   - <-chan Event: The channel of events.
+func (c *Cache) Watch(ctx context.Context, filter WatchFilter) <-chan Event {
+	var events, _ = c.events.watch(ctx, filter)
+	return events
+}
+
+// WatchWithSeq is a method of the Cache struct that returns a channel of the changes made to the cache, like Watch,
+// and the sequence number of the last change made before the watch started. Every change after it is delivered,
+// so the consumer can read the cache contents and apply the events with a greater sequence number.
+// This method is thread-safe.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the watch.
+//   - filter (WatchFilter): The filter of the events.
+//
+// Returns:
+//   - <-chan Event: The channel of events.
+//   - uint64: The sequence number of the last change before the watch started, or 0 if the cache hasn't changed.
+func (c *Cache) WatchWithSeq(ctx context.Context, filter WatchFilter) (<-chan Event, uint64) {
+	return c.events.watch(ctx, filter)
+}
+
+// Seq is a method of the Cache struct that returns the sequence number of the last change made to the cache.
+// This method is thread-safe.
+//
+// Returns:
+//   - uint64: The sequence number of the last event, or 0 if the cache hasn't changed.
+func (c *Cache) Seq() uint64 {
+	c.events.mutex.Lock()
+	defer c.events.mutex.Unlock()
+	return c.events.seq
+}
+
+// watch is a method of the eventLog struct that registers a new watcher.
+// This method is thread-safe.
+//
+// Parameters:
+//   - ctx (context.Context): The context of the watch.
+//   - filter (WatchFilter): The filter of the events.
+//
+// Returns:
+//   - <-chan Event: The channel of events.
+//   - uint64: The sequence number of the last event when the watcher was registered.
+func (l *eventLog) watch(ctx context.Context, filter WatchFilter) (<-chan Event, uint64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// Collect the buffered events to replay. If the watcher resumes
+	// from an event that didn't happen, its events can't be replayed
+	var backlog []Event
+	if filter.From > l.seq {
+		backlog = append(backlog, Event{Seq: l.seq, Type: EventLost})
+	} else if filter.From > 0 && filter.From < l.seq {
+		var oldest uint64 = 1
+		if l.seq > uint64(len(l.ring)) {
+			oldest = l.seq - uint64(len(l.ring)) + 1
+		}
+		var from uint64 = filter.From + 1
+		if from < oldest {
+			backlog = append(backlog, Event{Seq: oldest - 1, Type: EventLost})
+			from = oldest
+		}
+		for seq := from; seq <= l.seq; seq++ {
+			if e := l.ring[seq%uint64(len(l.ring))]; filter.match(e) {
+				backlog = append(backlog, e)
+			}
+		}
+	}
+
+	// Create the watcher
+	if filter.Buffer <= 0 {
+		filter.Buffer = watchBufferSize
+	}
+	var w *watcher = &watcher{
+		ch:     make(chan Event, filter.Buffer+len(backlog)),
+		filter: filter,
+		done:   make(chan struct{}),
+	}
+	for _, e := range backlog {
+		w.ch <- e
+	}
+	l.watchers[w] = true
+
+	// Remove the watcher when the context is done
+	go func() {
+		select {
+		case <-ctx.Done():
+			l.mutex.Lock()
+			l.remove(w)
+			l.mutex.Unlock()
+		case <-w.done:
+		}
+	}()
+	return w.ch, l.seq
+}
+
+// publish is a method of the eventLog struct that adds an event to the log and delivers it to the watchers.
+// The watchers whose channel is full are removed.
+// This method is thread-safe. It's called while the cache is locked, so that the events are in the same order as the changes.
+//
+// Parameters:
+//   - t (EventType): The type of the event.
+//   - key (string): The key of the record. Empty for the events that aren't about a record.
+//   - value (map[string]any): The record, the full-text settings or the schema. Can be nil.
+//   - queries ([]string): The ids of the registered queries that the record matches. Can be nil.
+//   - fields (map[string]Field): The metadata of the full-text fields of the record. Can be nil.
+//
+// Returns:
+//   - None
+func (l *eventLog) publish(t EventType, key string, value map[string]any, queries []string, fields map[string]Field) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// Add the event to the log
+	l.seq++
+	var e Event = Event{Seq: l.seq, Type: t, Key: key, Value: value, Queries: queries, Fields: fields}
+	l.ring[l.seq%uint64(len(l.ring))] = e
+
+	// Deliver the event
+	for w := range l.watchers {
+		if !w.filter.match(e) {
+			continue
+		}
+		select {
+		case w.ch <- e:
+		default:
+			l.remove(w)
+		}
+	}
+}
+
+// remove is a method of the eventLog struct that removes a watcher and closes its channel.
+// This method is not thread-safe, and should only be called while the log is locked.
+//
+// Parameters:
+//   - w (*watcher): The watcher to remove.
+//
+// Returns:
+//   - None
+func (l *eventLog) remove(w *watcher) {
+	if l.watchers[w] {
+		delete(l.watchers, w)
+		close(w.ch)
+		close(w.done)
+	}
+}
+
+// settings is a method of the FullText struct that returns the full-text settings, for the ft.init and ft.settings events.
+//
+// Returns:
+//   - map[string]any: The maximum size, maximum bytes, and minimum word length of the full-text index.
+func (ft *FullText) settings() map[string]any {
+	return map[string]any{
+		"max_size":        ft.maxSize,
+		"max_bytes":       ft.maxBytes,
+		"min_word_length": ft.minWordLength,
+	}
+}
@@ -1,8 +1,12 @@
 package hermes
 
+import "encoding/json"
+
 // WFT is a struct that represents a value to be set in the cache and in the full-text cache.
 type WFT struct {
-	value string
+	value     string
+	analyzer  string
+	notStored bool
 }
 
 // WithFT is a function that creates a new WFT struct with the specified value.
@@ -13,7 +17,30 @@ type WFT struct {
 // Returns:
 //   - A WFT struct with the specified value or the initial string
 func (cache *Cache) WithFT(value string) *WFT {
-	return &WFT{value}
+	return &WFT{value: value}
+}
+
+// WithAnalyzer is a method of the WFT struct that sets the analyzer used to split the value into words.
+//
+// Parameters:
+//   - name: The name of a registered analyzer.
+//
+// Returns:
+//   - The WFT struct, so that the calls can be chained.
+func (wft *WFT) WithAnalyzer(name string) *WFT {
+	wft.analyzer = name
+	return wft
+}
+
+// NotStored is a method of the WFT struct that marks the value as indexed only.
+// The value is inserted in the full-text index, but it's not kept in the cache record.
+// It's only kept in the metadata of the field, so that it can be re-indexed.
+//
+// Returns:
+//   - The WFT struct, so that the calls can be chained.
+func (wft *WFT) NotStored() *WFT {
+	wft.notStored = true
+	return wft
 }
 
 func (wft *WFT) Set(value string) {
@@ -24,6 +51,33 @@ func (wft *WFT) Value() string {
 	return wft.value
 }
 
+// field is a method of the WFT struct that returns the metadata of the field the value is set in.
+//
+// Returns:
+//   - The field metadata.
+func (wft *WFT) field() Field {
+	var analyzer string = wft.analyzer
+	if len(analyzer) == 0 {
+		analyzer = StandardAnalyzer
+	}
+	return Field{
+		FullText: true,
+		Analyzer: analyzer,
+		Stored:   !wft.notStored,
+	}
+}
+
+// MarshalJSON is a method of the WFT struct that encodes the value in the map format that is used in json files,
+// so that the value is a full-text value again when the json is set in a cache.
+//
+// Returns:
+//   - The json of the wrapped value: {"$hermes.full_text": true, "$hermes.value": value}, with the
+//     "$hermes.analyzer" and "$hermes.stored" keys if the value doesn't use the default ones.
+//   - An error if the value can't be encoded.
+func (wft *WFT) MarshalJSON() ([]byte, error) {
+	return json.Marshal(wrapFullText(wft.value, wft.field()))
+}
+
 func WFTGetValue(value any) string {
 	if wft, ok := value.(*WFT); ok {
 		return wft.value
@@ -41,25 +95,73 @@ func WFTGetValue(value any) string {
 // Returns:
 //   - A string representing the full-text value, or an empty string if the value is not a map or does not contain the correct keys.
 func WFTGetValueFromMap(value any) string {
-	if _, ok := value.(map[string]any); !ok {
-		return ""
+	if wft := wftFromMap(value); wft != nil {
+		return wft.value
 	}
+	return ""
+}
 
-	// Verify that the map has the correct length
-	var v map[string]any = value.(map[string]any)
-	if len(v) != 2 {
-		return ""
+// wftFromMap is a function that converts a {"$hermes.full_text": true, "$hermes.value": "..."} map into a WFT struct.
+// The map can also contain the optional "$hermes.analyzer" (string) and "$hermes.stored" (bool) keys.
+//
+// Parameters:
+//   - value: any representing the value to convert.
+//
+// Returns:
+//   - A pointer to the WFT struct, or nil if the value is not a map or does not contain the correct keys.
+func wftFromMap(value any) *WFT {
+	var v, ok = value.(map[string]any)
+	if !ok || len(v) < 2 || len(v) > 4 {
+		return nil
 	}
 
 	// Verify that the map has the correct keys
-	if ft, ok := v["$hermes.full_text"]; ok {
-		if ft, ok := ft.(bool); ok && ft {
-			if v, ok := v["$hermes.value"]; ok {
-				if v, ok := v.(string); ok {
-					return v
-				}
+	var wft *WFT = &WFT{}
+	for key, value := range v {
+		switch key {
+		case "$hermes.full_text":
+			if ft, ok := value.(bool); !ok || !ft {
+				return nil
 			}
+		case "$hermes.value":
+			if wft.value, ok = value.(string); !ok {
+				return nil
+			}
+		case "$hermes.analyzer":
+			if wft.analyzer, ok = value.(string); !ok {
+				return nil
+			}
+		case "$hermes.stored":
+			if stored, ok := value.(bool); !ok {
+				return nil
+			} else {
+				wft.notStored = !stored
+			}
+		default:
+			return nil
 		}
 	}
-	return ""
+
+	// Verify that the required keys are set
+	if _, ok := v["$hermes.full_text"]; !ok || len(wft.value) == 0 {
+		return nil
+	}
+	return wft
+}
+
+// wftFrom is a function that converts a record value into a WFT struct.
+//
+// Parameters:
+//   - value: any representing the record value.
+//
+// Returns:
+//   - A pointer to the WFT struct, or nil if the value is not a full-text value.
+func wftFrom(value any) *WFT {
+	if wft, ok := value.(*WFT); ok {
+		if len(wft.value) == 0 {
+			return nil
+		}
+		return wft
+	}
+	return wftFromMap(value)
 }